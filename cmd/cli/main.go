@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"crypto/tls"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"github.com/passsquale/key-value-storage/internal/tools"
 	"go.uber.org/zap"
 	"os"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -17,6 +19,10 @@ func main() {
 	address := flag.String("address", "localhost:8080", "Address of the kv-storage")
 	idleTimeout := flag.Duration("idle_timeout", time.Minute, "Idle timeout for connection")
 	maxMessageSizeStr := flag.String("max_message_size", "4KB", "Max message size for connection")
+	useTLS := flag.Bool("tls", false, "Connect to the kv-storage over TLS")
+	tlsCAFile := flag.String("tls_ca", "", "CA certificate to verify the kv-storage, system roots are used by default")
+	tlsCertFile := flag.String("tls_cert", "", "Client certificate for mutual TLS")
+	tlsKeyFile := flag.String("tls_key", "", "Client key for mutual TLS")
 	flag.Parse()
 
 	logger, _ := zap.NewProduction()
@@ -25,8 +31,16 @@ func main() {
 		logger.Fatal("failed to parse max message size", zap.Error(err))
 	}
 
+	var tlsConfig *tls.Config
+	if *useTLS || *tlsCAFile != "" || *tlsCertFile != "" || *tlsKeyFile != "" {
+		tlsConfig, err = network.NewClientTLSConfig(*tlsCertFile, *tlsKeyFile, *tlsCAFile)
+		if err != nil {
+			logger.Fatal("failed to create tls config", zap.Error(err))
+		}
+	}
+
 	reader := bufio.NewReader(os.Stdin)
-	client, err := network.NewTCPClient(*address, maxMessageSize, *idleTimeout)
+	client, err := network.NewTCPClientWithTLS(*address, maxMessageSize, *idleTimeout, tlsConfig)
 	if err != nil {
 		logger.Fatal("failed to connect with server", zap.Error(err))
 	}
@@ -51,6 +65,7 @@ func main() {
 			logger.Error("failed to send query", zap.Error(err))
 		}
 
-		fmt.Println(string(response))
+		// pipelined responses are already terminated by newline
+		fmt.Println(strings.TrimSuffix(string(response), "\n"))
 	}
 }
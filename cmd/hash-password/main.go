@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+	"log"
+	"os"
+	"strings"
+)
+
+// hash-password reads password from stdin and prints its
+// bcrypt hash for password_hash of users in acl config
+func main() {
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatal("failed to read password: ", err)
+	}
+
+	passwordHash, err := acl.HashPassword(strings.TrimRight(password, "\r\n"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(passwordHash)
+}
@@ -8,11 +8,14 @@ import (
 )
 
 type Config struct {
-	Engine      *EngineConfig      `yaml:"engine"`
-	WAL         *WALConfig         `yaml:"wal"`
-	Replication *ReplicationConfig `yaml:"replication"`
-	Network     *NetworkConfig     `yaml:"network"`
-	Logging     *LoggingConfig     `yaml:"logging"`
+	Engine        *EngineConfig        `yaml:"engine"`
+	WAL           *WALConfig           `yaml:"wal"`
+	Replication   *ReplicationConfig   `yaml:"replication"`
+	Network       *NetworkConfig       `yaml:"network"`
+	Listeners     []*NetworkConfig     `yaml:"listeners"`
+	Logging       *LoggingConfig       `yaml:"logging"`
+	Notifications *NotificationsConfig `yaml:"notifications"`
+	ACL           *ACLConfig           `yaml:"acl"`
 }
 
 type EngineConfig struct {
@@ -26,17 +29,62 @@ type WALConfig struct {
 	DataDirectory        string        `yaml:"data_directory"`
 }
 
+// ReplicationConfig with TLS makes master to accept replicas over TLS
+// and replicas to connect to it over TLS, certificate of a replica is
+// its client certificate, which is verified by the master with the CA
 type ReplicationConfig struct {
 	ReplicaType   string        `yaml:"replica_type"`
 	MasterAddress string        `yaml:"master_address"`
 	SyncInterval  time.Duration `yaml:"sync_interval"`
+	TLS           *TLSConfig    `yaml:"tls"`
 }
 
+// NetworkConfig configures the listener, protocol is text (by
+// default), resp2 or resp3, additional listeners are configured by
+// the same way, so RESP clients may be served beside text ones,
+// HTTP gateway and gRPC service are enabled by
+// their addresses in the network section
 type NetworkConfig struct {
-	Address        string        `yaml:"address"`
-	MaxConnections int           `yaml:"max_connections"`
-	MaxMessageSize string        `yaml:"max_message_size"`
-	IdleTimeout    time.Duration `yaml:"idle_timeout"`
+	Address          string        `yaml:"address"`
+	Protocol         string        `yaml:"protocol"`
+	HTTPAddress      string        `yaml:"http_address"`
+	GRPCAddress      string        `yaml:"grpc_address"`
+	MaxConnections   int           `yaml:"max_connections"`
+	MaxMessageSize   string        `yaml:"max_message_size"`
+	IdleTimeout      time.Duration `yaml:"idle_timeout"`
+	OutputBufferSize int           `yaml:"output_buffer_size"`
+	TLS              *TLSConfig    `yaml:"tls"`
+}
+
+// TLSConfig enables TLS, certificates of clients are verified by
+// the CA and required only if RequireClientCert is set (mutual TLS)
+type TLSConfig struct {
+	CertFile          string `yaml:"cert_file"`
+	KeyFile           string `yaml:"key_file"`
+	CAFile            string `yaml:"ca_file"`
+	RequireClientCert bool   `yaml:"require_client_cert"`
+}
+
+// NotificationsConfig enables keyspace events of the classes
+// (set, del, expired), events are disabled by default
+type NotificationsConfig struct {
+	Events []string `yaml:"events"`
+}
+
+// ACLConfig enables authentication of clients, the database is
+// open for everyone without it, see ACLUserConfig for permissions
+type ACLConfig struct {
+	Users []ACLUserConfig `yaml:"users"`
+}
+
+// ACLUserConfig allows the user to execute commands of the categories
+// (read, write, admin) with keys matching any of the glob-style key
+// patterns, password is stored as bcrypt hash
+type ACLUserConfig struct {
+	Name         string   `yaml:"name"`
+	PasswordHash string   `yaml:"password_hash"`
+	Categories   []string `yaml:"categories"`
+	Keys         []string `yaml:"keys"`
 }
 
 type LoggingConfig struct {
@@ -46,12 +46,47 @@ func TestLoadConfig(t *testing.T) {
 	require.Equal(t, "slave", cfg.Replication.ReplicaType)
 	require.Equal(t, "127.0.0.1:3232", cfg.Replication.MasterAddress)
 	require.Equal(t, time.Second, cfg.Replication.SyncInterval)
+	require.Equal(t, &TLSConfig{
+		CertFile: "/etc/kv-storage/replica.crt",
+		KeyFile:  "/etc/kv-storage/replica.key",
+		CAFile:   "/etc/kv-storage/ca.crt",
+	}, cfg.Replication.TLS)
 
 	require.Equal(t, "127.0.0.1:3223", cfg.Network.Address)
 	require.Equal(t, 100, cfg.Network.MaxConnections)
 	require.Equal(t, "4KB", cfg.Network.MaxMessageSize)
 	require.Equal(t, time.Minute*5, cfg.Network.IdleTimeout)
+	require.Equal(t, 128, cfg.Network.OutputBufferSize)
+	require.Equal(t, "127.0.0.1:8081", cfg.Network.HTTPAddress)
+	require.Equal(t, "127.0.0.1:8082", cfg.Network.GRPCAddress)
+	require.Equal(t, &TLSConfig{
+		CertFile:          "/etc/kv-storage/server.crt",
+		KeyFile:           "/etc/kv-storage/server.key",
+		CAFile:            "/etc/kv-storage/ca.crt",
+		RequireClientCert: true,
+	}, cfg.Network.TLS)
+
+	require.Len(t, cfg.Listeners, 1)
+	require.Equal(t, "127.0.0.1:6379", cfg.Listeners[0].Address)
+	require.Equal(t, "resp2", cfg.Listeners[0].Protocol)
 
 	require.Equal(t, "info", cfg.Logging.Level)
 	require.Equal(t, "/log/output.log", cfg.Logging.Output)
+
+	require.Equal(t, []string{"set", "expired"}, cfg.Notifications.Events)
+
+	require.Equal(t, []ACLUserConfig{
+		{
+			Name:         "admin",
+			PasswordHash: "$2a$10$Y.7KgLL3qEGGFBSV73WBneT.R4n.kpzBNGH8YadMtIrTz6.g3KZdm",
+			Categories:   []string{"read", "write", "admin"},
+			Keys:         []string{"*"},
+		},
+		{
+			Name:         "reader",
+			PasswordHash: "$2a$10$W0JWxVYWhp6ono64SXDBoOx7NiD9L5r7H6bOqEshN8./l3HvlHiFa",
+			Categories:   []string{"read"},
+			Keys:         []string{"user:*", "session:*"},
+		},
+	}, cfg.ACL.Users)
 }
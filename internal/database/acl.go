@@ -0,0 +1,284 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"go.uber.org/zap"
+	"strings"
+)
+
+var (
+	errAuthRequired       = errors.New("authentication required")
+	errAuthDisabled       = errors.New("authentication isn't enabled")
+	errAuthSessionMissing = errors.New("authentication requires client session")
+	errPermissionDenied   = errors.New("permission denied")
+)
+
+// commandCategories lists categories required by commands, commands
+// with empty list are allowed to any authenticated user and commands
+// missing here require admin category, so new commands aren't open
+// by default; commands working with the whole keyspace are admin ones
+var commandCategories = map[int][]string{
+	compute.AuthCommandID:          {},
+	compute.BeginCommandID:         {},
+	compute.CommitCommandID:        {},
+	compute.RollbackCommandID:      {},
+	compute.UnwatchCommandID:       {},
+	compute.UnsubscribeCommandID:   {},
+	compute.PUnsubscribeCommandID:  {},
+	compute.GetCommandID:           {acl.ReadCategory},
+	compute.TTLCommandID:           {acl.ReadCategory},
+	compute.PTTLCommandID:          {acl.ReadCategory},
+	compute.MGetCommandID:          {acl.ReadCategory},
+	compute.ExistsCommandID:        {acl.ReadCategory},
+	compute.WatchCommandID:         {acl.ReadCategory},
+	compute.StrLenCommandID:        {acl.ReadCategory},
+	compute.GetRangeCommandID:      {acl.ReadCategory},
+	compute.HGetCommandID:          {acl.ReadCategory},
+	compute.HGetAllCommandID:       {acl.ReadCategory},
+	compute.HLenCommandID:          {acl.ReadCategory},
+	compute.HExistsCommandID:       {acl.ReadCategory},
+	compute.LLenCommandID:          {acl.ReadCategory},
+	compute.LRangeCommandID:        {acl.ReadCategory},
+	compute.LIndexCommandID:        {acl.ReadCategory},
+	compute.SIsMemberCommandID:     {acl.ReadCategory},
+	compute.SMembersCommandID:      {acl.ReadCategory},
+	compute.SCardCommandID:         {acl.ReadCategory},
+	compute.SInterCommandID:        {acl.ReadCategory},
+	compute.SUnionCommandID:        {acl.ReadCategory},
+	compute.SDiffCommandID:         {acl.ReadCategory},
+	compute.ZScoreCommandID:        {acl.ReadCategory},
+	compute.ZRankCommandID:         {acl.ReadCategory},
+	compute.ZRangeCommandID:        {acl.ReadCategory},
+	compute.ZRangeByScoreCommandID: {acl.ReadCategory},
+	compute.ZCardCommandID:         {acl.ReadCategory},
+	compute.SubscribeCommandID:     {acl.ReadCategory},
+	compute.PSubscribeCommandID:    {acl.ReadCategory},
+	compute.SetCommandID:           {acl.WriteCategory},
+	compute.SetNXCommandID:         {acl.WriteCategory},
+	compute.DelCommandID:           {acl.WriteCategory},
+	compute.MSetCommandID:          {acl.WriteCategory},
+	compute.MDelCommandID:          {acl.WriteCategory},
+	compute.ExpireCommandID:        {acl.WriteCategory},
+	compute.PersistCommandID:       {acl.WriteCategory},
+	compute.IncrCommandID:          {acl.WriteCategory},
+	compute.DecrCommandID:          {acl.WriteCategory},
+	compute.IncrByCommandID:        {acl.WriteCategory},
+	compute.DecrByCommandID:        {acl.WriteCategory},
+	compute.IncrByFloatCommandID:   {acl.WriteCategory},
+	compute.AppendCommandID:        {acl.WriteCategory},
+	compute.SetRangeCommandID:      {acl.WriteCategory},
+	compute.HSetCommandID:          {acl.WriteCategory},
+	compute.HDelCommandID:          {acl.WriteCategory},
+	compute.HIncrByCommandID:       {acl.WriteCategory},
+	compute.LPushCommandID:         {acl.WriteCategory},
+	compute.RPushCommandID:         {acl.WriteCategory},
+	compute.LTrimCommandID:         {acl.WriteCategory},
+	compute.SAddCommandID:          {acl.WriteCategory},
+	compute.SRemCommandID:          {acl.WriteCategory},
+	compute.ZAddCommandID:          {acl.WriteCategory},
+	compute.ZRemCommandID:          {acl.WriteCategory},
+	compute.PublishCommandID:       {acl.WriteCategory},
+	compute.CASCommandID:           {acl.ReadCategory, acl.WriteCategory},
+	compute.GetSetCommandID:        {acl.ReadCategory, acl.WriteCategory},
+	compute.GetDelCommandID:        {acl.ReadCategory, acl.WriteCategory},
+	compute.BGetDelCommandID:       {acl.ReadCategory, acl.WriteCategory},
+	compute.LPopCommandID:          {acl.ReadCategory, acl.WriteCategory},
+	compute.RPopCommandID:          {acl.ReadCategory, acl.WriteCategory},
+}
+
+// keylessCommands have no keys to check, channels of pub/sub commands
+// aren't keys, so they aren't restricted by key patterns except keyspace
+// channels of subscriptions, see subscriptionKeys; keys found by SCAN
+// and KEYS are filtered by accessibleKeys instead
+var keylessCommands = map[int]struct{}{
+	compute.AuthCommandID:         {},
+	compute.BeginCommandID:        {},
+	compute.CommitCommandID:       {},
+	compute.RollbackCommandID:     {},
+	compute.UnwatchCommandID:      {},
+	compute.PublishCommandID:      {},
+	compute.UnsubscribeCommandID:  {},
+	compute.PUnsubscribeCommandID: {},
+	compute.ScanCommandID:         {},
+	compute.KeysCommandID:         {},
+}
+
+// multiKeyCommands take only keys as arguments
+var multiKeyCommands = map[int]struct{}{
+	compute.MGetCommandID:   {},
+	compute.MDelCommandID:   {},
+	compute.ExistsCommandID: {},
+	compute.WatchCommandID:  {},
+	compute.SInterCommandID: {},
+	compute.SUnionCommandID: {},
+	compute.SDiffCommandID:  {},
+}
+
+// EnableACL makes clients to authenticate by AUTH command before
+// other queries, which are checked against permissions of the user
+func (d *Database) EnableACL(accessList *acl.ACL) {
+	d.acl = accessList
+}
+
+// authorizeQuery checks that the user of the query is allowed
+// to execute it, all queries are allowed if ACL isn't enabled
+func (d *Database) authorizeQuery(ctx context.Context, query compute.Query) error {
+	if d.acl == nil || query.CommandID() == compute.AuthCommandID {
+		return nil
+	}
+
+	user := d.userFromContext(ctx)
+	if user == nil {
+		return errAuthRequired
+	}
+
+	categories, found := commandCategories[query.CommandID()]
+	if !found {
+		categories = []string{acl.AdminCategory}
+	}
+
+	for _, category := range categories {
+		if !user.HasCategory(category) {
+			return fmt.Errorf("%w: user %s has no %s category", errPermissionDenied, user.Name(), category)
+		}
+	}
+
+	for _, key := range queryKeys(query) {
+		if !user.CanAccessKey(key) {
+			return fmt.Errorf("%w: user %s has no access to key %s", errPermissionDenied, user.Name(), key)
+		}
+	}
+
+	return nil
+}
+
+// userFromContext returns user authenticated in the session of
+// the connection or by the HTTP request under "user" key
+func (d *Database) userFromContext(ctx context.Context) *acl.User {
+	var name string
+	if conn, ok := ctx.Value("session").(authenticatedConnection); ok {
+		name = conn.User()
+	} else if value, ok := ctx.Value("user").(string); ok {
+		name = value
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	user, _ := d.acl.User(name)
+	return user
+}
+
+// accessibleKeys filters keys found by the query
+// by key patterns of its user if ACL is enabled
+func (d *Database) accessibleKeys(ctx context.Context, keys []string) []string {
+	if d.acl == nil {
+		return keys
+	}
+
+	user := d.userFromContext(ctx)
+	if user == nil {
+		return nil
+	}
+
+	accessible := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if user.CanAccessKey(key) {
+			accessible = append(accessible, key)
+		}
+	}
+
+	return accessible
+}
+
+// subscriptionKeys returns keys (or key patterns) of keyspace channels,
+// a pattern is allowed only if it's matched by a key pattern of the user,
+// events of other channels matching keyspace ones are filtered on delivery
+func subscriptionKeys(channels []string) []string {
+	var keys []string
+	for _, channel := range channels {
+		if key, found := strings.CutPrefix(channel, keyspaceChannelPrefix); found {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// canReceiveEvents checks that the user authenticated by the connection
+// of the subscriber has access to the key of keyspace events
+func (d *Database) canReceiveEvents(client subscriber, key string) bool {
+	if d.acl == nil {
+		return true
+	}
+
+	conn, ok := client.(authenticatedConnection)
+	if !ok {
+		return false
+	}
+
+	user, found := d.acl.User(conn.User())
+	return found && user.HasCategory(acl.ReadCategory) && user.CanAccessKey(key)
+}
+
+func (d *Database) handleAuthQuery(ctx context.Context, query compute.Query) string {
+	if d.acl == nil {
+		return fmt.Sprintf("[error] %s", errAuthDisabled.Error())
+	}
+
+	conn, ok := ctx.Value("session").(authenticatedConnection)
+	if !ok {
+		return fmt.Sprintf("[error] %s", errAuthSessionMissing.Error())
+	}
+
+	arguments := query.Arguments()
+	user, err := d.acl.Authenticate(arguments[0], arguments[1])
+	if err != nil {
+		txID := ctx.Value("tx").(int64)
+		d.logger.Warn("failed to authenticate", zap.Int64("tx", txID), zap.String("user", arguments[0]))
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	conn.SetUser(user.Name())
+	return "[ok]"
+}
+
+func queryKeys(query compute.Query) []string {
+	arguments := query.Arguments()
+	if _, found := keylessCommands[query.CommandID()]; found || len(arguments) == 0 {
+		return nil
+	}
+
+	if _, found := multiKeyCommands[query.CommandID()]; found {
+		return arguments
+	}
+
+	switch query.CommandID() {
+	case compute.MSetCommandID:
+		keys := make([]string, 0, len(arguments)/2)
+		for idx := 0; idx < len(arguments); idx += 2 {
+			keys = append(keys, arguments[idx])
+		}
+
+		return keys
+	case compute.BGetDelCommandID:
+		return arguments[:len(arguments)-1]
+	case compute.EvalCommandID:
+		// invalid number of keys is reported by handleEvalQuery
+		keys, _, err := evalKeys(arguments)
+		if err != nil {
+			return nil
+		}
+
+		return keys
+	case compute.SubscribeCommandID, compute.PSubscribeCommandID:
+		return subscriptionKeys(arguments)
+	}
+
+	return arguments[:1]
+}
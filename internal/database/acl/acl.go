@@ -0,0 +1,180 @@
+package acl
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"golang.org/x/crypto/bcrypt"
+	"sync"
+)
+
+const (
+	ReadCategory  = "read"
+	WriteCategory = "write"
+	AdminCategory = "admin"
+)
+
+const (
+	// passwordHashCost is bcrypt cost of hashes made by HashPassword
+	passwordHashCost = bcrypt.DefaultCost
+	// passwordHashSize is size of bcrypt hash with its prefix and salt
+	passwordHashSize = 60
+)
+
+var errInvalidPasswordHash = errors.New("expected bcrypt hash")
+
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+var categories = map[string]struct{}{
+	ReadCategory:  {},
+	WriteCategory: {},
+	AdminCategory: {},
+}
+
+// dummyPasswordHash is compared with passwords of unknown users,
+// so they can't be found out by time of authentication
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword(nil, passwordHashCost)
+	return hash
+})
+
+// User is allowed to execute commands of its categories
+// with keys matching any of its glob-style key patterns
+type User struct {
+	name         string
+	passwordHash []byte
+	categories   map[string]struct{}
+	keyPatterns  []string
+}
+
+// NewUser creates user with the password hash made by HashPassword
+func NewUser(name, passwordHash string, categoryNames, keyPatterns []string) (*User, error) {
+	if name == "" {
+		return nil, errors.New("user name is empty")
+	}
+
+	if err := checkPasswordHash(passwordHash); err != nil {
+		return nil, fmt.Errorf("invalid password hash of user %s: %w", name, err)
+	}
+
+	userCategories := make(map[string]struct{}, len(categoryNames))
+	for _, category := range categoryNames {
+		if _, found := categories[category]; !found {
+			return nil, fmt.Errorf("unknown category %s of user %s", category, name)
+		}
+
+		userCategories[category] = struct{}{}
+	}
+
+	return &User{
+		name:         name,
+		passwordHash: []byte(passwordHash),
+		categories:   userCategories,
+		keyPatterns:  keyPatterns,
+	}, nil
+}
+
+func (u *User) Name() string {
+	return u.name
+}
+
+func (u *User) HasCategory(category string) bool {
+	_, found := u.categories[category]
+	return found
+}
+
+func (u *User) CanAccessKey(key string) bool {
+	for _, pattern := range u.keyPatterns {
+		if tools.MatchPattern(pattern, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *User) checkPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword(u.passwordHash, []byte(password)) == nil
+}
+
+// ACL keeps users allowed to access the database, it isn't
+// changed after creation, so it's safe for concurrent use
+type ACL struct {
+	users map[string]*User
+	// verified keeps SHA-256 of the last password checked by bcrypt
+	// per user name, so repeated authentication of requests is cheap,
+	// the cache is dropped with the ACL when users are reconfigured
+	verified sync.Map
+}
+
+func NewACL(users []*User) (*ACL, error) {
+	if len(users) == 0 {
+		return nil, errors.New("acl has no users")
+	}
+
+	acl := &ACL{
+		users: make(map[string]*User, len(users)),
+	}
+
+	for _, user := range users {
+		if _, found := acl.users[user.Name()]; found {
+			return nil, fmt.Errorf("user %s is duplicated", user.Name())
+		}
+
+		acl.users[user.Name()] = user
+	}
+
+	return acl, nil
+}
+
+func (a *ACL) User(name string) (*User, bool) {
+	user, found := a.users[name]
+	return user, found
+}
+
+func (a *ACL) Authenticate(name, password string) (*User, error) {
+	user, found := a.users[name]
+	if !found {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+
+	digest := sha256.Sum256([]byte(password))
+	if verified, found := a.verified.Load(name); found {
+		if subtle.ConstantTimeCompare(verified.([]byte), digest[:]) == 1 {
+			return user, nil
+		}
+	}
+
+	if !user.checkPassword(password) {
+		return nil, ErrInvalidCredentials
+	}
+
+	a.verified.Store(name, digest[:])
+	return user, nil
+}
+
+// HashPassword returns bcrypt hash of the password,
+// passwords longer than 72 bytes aren't supported
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+func checkPasswordHash(passwordHash string) error {
+	if len(passwordHash) != passwordHashSize {
+		return errInvalidPasswordHash
+	}
+
+	if _, err := bcrypt.Cost([]byte(passwordHash)); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidPasswordHash, err)
+	}
+
+	return nil
+}
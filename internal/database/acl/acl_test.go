@@ -0,0 +1,149 @@
+package acl
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func newTestUser(t *testing.T, name, password string, categories, keyPatterns []string) *User {
+	t.Helper()
+
+	hash, err := HashPassword(password)
+	require.NoError(t, err)
+
+	user, err := NewUser(name, hash, categories, keyPatterns)
+	require.NoError(t, err)
+	return user
+}
+
+func TestHashPassword(t *testing.T) {
+	t.Parallel()
+
+	first, err := HashPassword("password")
+	require.NoError(t, err)
+	second, err := HashPassword("password")
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(first, "$2a$10$"))
+	require.NotEqual(t, first, second)
+
+	_, err = HashPassword(strings.Repeat("a", 73))
+	require.Error(t, err)
+}
+
+func TestNewUser(t *testing.T) {
+	t.Parallel()
+
+	hash, err := HashPassword("password")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		name         string
+		passwordHash string
+		categories   []string
+	}{
+		"empty name":           {name: "", passwordHash: hash},
+		"unknown category":     {name: "user", passwordHash: hash, categories: []string{"read", "delete"}},
+		"plain text password":  {name: "user", passwordHash: "password"},
+		"unknown hash version": {name: "user", passwordHash: "$3a" + strings.TrimPrefix(hash, "$2a")},
+		"invalid cost":         {name: "user", passwordHash: "$2a$99" + strings.TrimPrefix(hash, "$2a$10")},
+		"truncated hash value": {name: "user", passwordHash: hash[:len(hash)-2]},
+		"extended hash value":  {name: "user", passwordHash: hash + "aa"},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			user, err := NewUser(test.name, test.passwordHash, test.categories, nil)
+			require.Error(t, err)
+			require.Nil(t, user)
+		})
+	}
+}
+
+func TestUserPermissions(t *testing.T) {
+	t.Parallel()
+
+	user := newTestUser(t, "reader", "password", []string{ReadCategory}, []string{"user:*", "config"})
+	require.Equal(t, "reader", user.Name())
+
+	require.True(t, user.HasCategory(ReadCategory))
+	require.False(t, user.HasCategory(WriteCategory))
+	require.False(t, user.HasCategory(AdminCategory))
+
+	require.True(t, user.CanAccessKey("user:1"))
+	require.True(t, user.CanAccessKey("config"))
+	require.False(t, user.CanAccessKey("config:1"))
+	require.False(t, user.CanAccessKey("admin:1"))
+
+	user = newTestUser(t, "nobody", "password", nil, nil)
+	require.False(t, user.CanAccessKey("key"))
+}
+
+func TestACL(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewACL(nil)
+	require.Error(t, err)
+
+	admin := newTestUser(t, "admin", "secret", []string{ReadCategory, WriteCategory, AdminCategory}, []string{"*"})
+	_, err = NewACL([]*User{admin, newTestUser(t, "admin", "other", nil, nil)})
+	require.Error(t, err)
+
+	acl, err := NewACL([]*User{admin, newTestUser(t, "reader", "password", []string{ReadCategory}, nil)})
+	require.NoError(t, err)
+
+	user, found := acl.User("admin")
+	require.True(t, found)
+	require.Equal(t, admin, user)
+
+	_, found = acl.User("unknown")
+	require.False(t, found)
+
+	user, err = acl.Authenticate("admin", "secret")
+	require.NoError(t, err)
+	require.Equal(t, admin, user)
+
+	_, err = acl.Authenticate("admin", "password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = acl.Authenticate("unknown", "secret")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestACLVerifiedPasswords(t *testing.T) {
+	t.Parallel()
+
+	admin := newTestUser(t, "admin", "secret", []string{AdminCategory}, []string{"*"})
+	acl, err := NewACL([]*User{admin})
+	require.NoError(t, err)
+
+	_, err = acl.Authenticate("admin", "password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, found := acl.verified.Load("admin")
+	require.False(t, found)
+
+	user, err := acl.Authenticate("admin", "secret")
+	require.NoError(t, err)
+	require.Equal(t, admin, user)
+	_, found = acl.verified.Load("admin")
+	require.True(t, found)
+
+	// the cached password doesn't let other passwords in
+	_, err = acl.Authenticate("admin", "password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// the password isn't checked by bcrypt again
+	admin.passwordHash = nil
+	user, err = acl.Authenticate("admin", "secret")
+	require.NoError(t, err)
+	require.Equal(t, admin, user)
+
+	reconfigured, err := NewACL([]*User{admin})
+	require.NoError(t, err)
+	_, err = reconfigured.Authenticate("admin", "secret")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
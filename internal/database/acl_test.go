@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+	"github.com/passsquale/key-value-storage/internal/network"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestACL(t *testing.T) *acl.ACL {
+	t.Helper()
+
+	newUser := func(name, password string, categories, keyPatterns []string) *acl.User {
+		passwordHash, err := acl.HashPassword(password)
+		require.NoError(t, err)
+		user, err := acl.NewUser(name, passwordHash, categories, keyPatterns)
+		require.NoError(t, err)
+		return user
+	}
+
+	accessList, err := acl.NewACL([]*acl.User{
+		newUser("admin", "secret", []string{acl.ReadCategory, acl.WriteCategory, acl.AdminCategory}, []string{"*"}),
+		newUser("reader", "password", []string{acl.ReadCategory}, []string{"user:*"}),
+		newUser("writer", "password", []string{acl.ReadCategory, acl.WriteCategory}, []string{"user:*"}),
+		newUser("operator", "password", []string{acl.ReadCategory, acl.WriteCategory, acl.AdminCategory}, []string{"user:*"}),
+	})
+	require.NoError(t, err)
+	return accessList
+}
+
+func TestHandleAuthQuery(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	session := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+	require.Equal(t, "[error] authentication isn't enabled", database.HandleQuery(session, "AUTH admin secret"))
+
+	database.EnableACL(newTestACL(t))
+	require.Equal(t, "[error] authentication required", database.HandleQuery(session, "GET user:1"))
+	require.Equal(t, "[error] invalid username or password", database.HandleQuery(session, "AUTH admin password"))
+	require.Equal(t, "[error] invalid username or password", database.HandleQuery(session, "AUTH unknown secret"))
+	require.Equal(t, "[error] authentication required", database.HandleQuery(session, "GET user:1"))
+	require.Equal(t, "[error] authentication requires client session", database.HandleQuery(context.Background(), "AUTH admin secret"))
+	require.Equal(t, "[error] authentication required", database.HandleQuery(context.Background(), "GET user:1"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(session, "AUTH admin secret"))
+	require.Equal(t, "[ok]", database.HandleQuery(session, "SET user:1 value"))
+	require.Equal(t, "[ok] value", database.HandleQuery(session, "GET user:1"))
+
+	// failed authentication keeps the user of the session
+	require.Equal(t, "[error] invalid username or password", database.HandleQuery(session, "AUTH reader secret"))
+	require.Equal(t, "[ok]", database.HandleQuery(session, "DEL user:1"))
+
+	// the user is bound to the connection
+	other := context.WithValue(context.Background(), "session", network.NewSession(2, 1))
+	require.Equal(t, "[error] authentication required", database.HandleQuery(other, "GET user:1"))
+}
+
+func TestHandleQueryWithPermissions(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	database.EnableACL(newTestACL(t))
+
+	admin := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+	reader := context.WithValue(context.Background(), "session", network.NewSession(2, 1))
+	writer := context.WithValue(context.Background(), "session", network.NewSession(3, 1))
+	operator := context.WithValue(context.Background(), "session", network.NewSession(4, 1))
+	require.Equal(t, "[ok]", database.HandleQuery(admin, "AUTH admin secret"))
+	require.Equal(t, "[ok]", database.HandleQuery(reader, "AUTH reader password"))
+	require.Equal(t, "[ok]", database.HandleQuery(writer, "AUTH writer password"))
+	require.Equal(t, "[ok]", database.HandleQuery(operator, "AUTH operator password"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(admin, "MSET user:1 a config b"))
+
+	tests := []struct {
+		ctx      context.Context
+		query    string
+		response string
+	}{
+		{reader, "GET user:1", "[ok] a"},
+		{reader, "GET config", "[error] permission denied: user reader has no access to key config"},
+		{reader, "MGET user:1 config", "[error] permission denied: user reader has no access to key config"},
+		{reader, "SET user:1 b", "[error] permission denied: user reader has no write category"},
+		{reader, "KEYS *", "[error] permission denied: user reader has no admin category"},
+		{reader, "EVAL 'return get(KEYS[1])' 1 user:1", "[error] permission denied: user reader has no admin category"},
+		{writer, "SET user:2 b", "[ok]"},
+		{writer, "MSET user:3 c user:4 d", "[ok]"},
+		{writer, "MSET user:5 config config e", "[error] permission denied: user writer has no access to key config"},
+		{writer, "GETDEL user:4", "[ok] d"},
+		{writer, "DEL config", "[error] permission denied: user writer has no access to key config"},
+		{writer, "BEGIN", "[ok]"},
+		{writer, "SET config c", "[error] permission denied: user writer has no access to key config"},
+		{writer, "SET user:6 f", "[ok]"},
+		{writer, "COMMIT", "[ok]"},
+		{admin, "GET config", "[ok] b"},
+		{admin, "GET user:6", "[ok] f"},
+		{admin, "KEYS config", `[ok] "config"`},
+		{operator, "KEYS *", `[ok] "user:1" "user:2" "user:3" "user:6"`},
+		{operator, "KEYS config", "[ok]"},
+		{operator, "SCAN 0 COUNT 100", `[ok] 0 "user:1" "user:2" "user:3" "user:6"`},
+		{operator, "EVAL 'return get(KEYS[1])' 1 user:1", "[ok] a"},
+		{operator, "EVAL 'return get(KEYS[1])' 2 user:1 config", "[error] permission denied: user operator has no access to key config"},
+		{operator, "EVAL 'return get(KEYS[1])' 1 config", "[error] permission denied: user operator has no access to key config"},
+		{operator, "EVAL 'return 1' 2 user:1", "[error] number of keys is not an integer or out of range"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.response, database.HandleQuery(test.ctx, test.query), test.query)
+	}
+}
+
+func TestHandleRESPCommandWithACL(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	database.EnableACL(newTestACL(t))
+	ctx := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+
+	handle := func(arguments ...string) string {
+		return string(database.HandleRESPCommand(ctx, arguments, network.RESP2Protocol))
+	}
+
+	require.Equal(t, "-ERR authentication required\r\n", handle("GET", "user:1"))
+	require.Equal(t, "+OK\r\n", handle("auth", "reader", "password"))
+	require.Equal(t, "$-1\r\n", handle("GET", "user:1"))
+	require.Equal(t, "-ERR permission denied: user reader has no write category\r\n", handle("SET", "user:1", "value"))
+}
+
+func TestHTTPHandlerWithACL(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	database.EnableACL(newTestACL(t))
+	handler := database.HTTPHandler(32)
+
+	serve := func(method, target, user, password string) (int, string) {
+		request := httptest.NewRequest(method, target, strings.NewReader("value"))
+		if user != "" {
+			request.SetBasicAuth(user, password)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code, strings.TrimSpace(recorder.Body.String())
+	}
+
+	code, response := serve(http.MethodGet, "/v1/keys/user:1", "", "")
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Equal(t, `{"status":"error","error":"authentication required"}`, response)
+
+	code, response = serve(http.MethodGet, "/v1/keys/user:1", "reader", "secret")
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Equal(t, `{"status":"error","error":"invalid username or password"}`, response)
+
+	code, _ = serve(http.MethodPut, "/v1/keys/user:1", "writer", "password")
+	require.Equal(t, http.StatusOK, code)
+
+	code, response = serve(http.MethodGet, "/v1/keys/user:1", "reader", "password")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, `{"status":"ok","value":"value"}`, response)
+
+	code, response = serve(http.MethodDelete, "/v1/keys/user:1", "reader", "password")
+	require.Equal(t, http.StatusForbidden, code)
+	require.Equal(t, `{"status":"error","error":"permission denied: user reader has no write category"}`, response)
+}
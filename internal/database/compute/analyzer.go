@@ -4,24 +4,178 @@ import (
 	"context"
 	"errors"
 	"go.uber.org/zap"
+	"strings"
 )
 
 const (
-	setQueryArgumentsNumber = 2
-	getQueryArgumentsNumber = 1
-	delQueryArgumentsNumber = 1
+	setQueryArgumentsNumber           = 2
+	getQueryArgumentsNumber           = 1
+	delQueryArgumentsNumber           = 1
+	ttlQueryArgumentsNumber           = 1
+	pttlQueryArgumentsNumber          = 1
+	expireQueryArgumentsNumber        = 2
+	persistQueryArgumentsNumber       = 1
+	beginQueryArgumentsNumber         = 0
+	commitQueryArgumentsNumber        = 0
+	rollbackQueryArgumentsNumber      = 0
+	unwatchQueryArgumentsNumber       = 0
+	casQueryArgumentsNumber           = 3
+	incrQueryArgumentsNumber          = 1
+	decrQueryArgumentsNumber          = 1
+	incrByQueryArgumentsNumber        = 2
+	decrByQueryArgumentsNumber        = 2
+	incrByFloatQueryArgumentsNumber   = 2
+	appendQueryArgumentsNumber        = 2
+	strLenQueryArgumentsNumber        = 1
+	getRangeQueryArgumentsNumber      = 3
+	setRangeQueryArgumentsNumber      = 3
+	getSetQueryArgumentsNumber        = 2
+	getDelQueryArgumentsNumber        = 1
+	setNXQueryArgumentsNumber         = 2
+	scanQueryArgumentsNumber          = 1
+	keysQueryArgumentsNumber          = 1
+	hGetQueryArgumentsNumber          = 2
+	hGetAllQueryArgumentsNumber       = 1
+	hLenQueryArgumentsNumber          = 1
+	hExistsQueryArgumentsNumber       = 2
+	hIncrByQueryArgumentsNumber       = 3
+	lPopQueryArgumentsNumber          = 1
+	rPopQueryArgumentsNumber          = 1
+	lLenQueryArgumentsNumber          = 1
+	lRangeQueryArgumentsNumber        = 3
+	lIndexQueryArgumentsNumber        = 2
+	lTrimQueryArgumentsNumber         = 3
+	sIsMemberQueryArgumentsNumber     = 2
+	sMembersQueryArgumentsNumber      = 1
+	sCardQueryArgumentsNumber         = 1
+	zScoreQueryArgumentsNumber        = 2
+	zRankQueryArgumentsNumber         = 2
+	zRangeQueryArgumentsNumber        = 3
+	zRangeByScoreQueryArgumentsNumber = 3
+	zCardQueryArgumentsNumber         = 1
+	publishQueryArgumentsNumber       = 2
+	authQueryArgumentsNumber          = 2
 )
 
 var queryArgumentsNumber = map[int]int{
-	SetCommandID: setQueryArgumentsNumber,
-	GetCommandID: getQueryArgumentsNumber,
-	DelCommandID: delQueryArgumentsNumber,
+	SetCommandID:           setQueryArgumentsNumber,
+	GetCommandID:           getQueryArgumentsNumber,
+	DelCommandID:           delQueryArgumentsNumber,
+	TTLCommandID:           ttlQueryArgumentsNumber,
+	PTTLCommandID:          pttlQueryArgumentsNumber,
+	ExpireCommandID:        expireQueryArgumentsNumber,
+	PersistCommandID:       persistQueryArgumentsNumber,
+	BeginCommandID:         beginQueryArgumentsNumber,
+	CommitCommandID:        commitQueryArgumentsNumber,
+	RollbackCommandID:      rollbackQueryArgumentsNumber,
+	UnwatchCommandID:       unwatchQueryArgumentsNumber,
+	CASCommandID:           casQueryArgumentsNumber,
+	IncrCommandID:          incrQueryArgumentsNumber,
+	DecrCommandID:          decrQueryArgumentsNumber,
+	IncrByCommandID:        incrByQueryArgumentsNumber,
+	DecrByCommandID:        decrByQueryArgumentsNumber,
+	IncrByFloatCommandID:   incrByFloatQueryArgumentsNumber,
+	AppendCommandID:        appendQueryArgumentsNumber,
+	StrLenCommandID:        strLenQueryArgumentsNumber,
+	GetRangeCommandID:      getRangeQueryArgumentsNumber,
+	SetRangeCommandID:      setRangeQueryArgumentsNumber,
+	GetSetCommandID:        getSetQueryArgumentsNumber,
+	GetDelCommandID:        getDelQueryArgumentsNumber,
+	SetNXCommandID:         setNXQueryArgumentsNumber,
+	ScanCommandID:          scanQueryArgumentsNumber,
+	KeysCommandID:          keysQueryArgumentsNumber,
+	HGetCommandID:          hGetQueryArgumentsNumber,
+	HGetAllCommandID:       hGetAllQueryArgumentsNumber,
+	HLenCommandID:          hLenQueryArgumentsNumber,
+	HExistsCommandID:       hExistsQueryArgumentsNumber,
+	HIncrByCommandID:       hIncrByQueryArgumentsNumber,
+	LPopCommandID:          lPopQueryArgumentsNumber,
+	RPopCommandID:          rPopQueryArgumentsNumber,
+	LLenCommandID:          lLenQueryArgumentsNumber,
+	LRangeCommandID:        lRangeQueryArgumentsNumber,
+	LIndexCommandID:        lIndexQueryArgumentsNumber,
+	LTrimCommandID:         lTrimQueryArgumentsNumber,
+	SIsMemberCommandID:     sIsMemberQueryArgumentsNumber,
+	SMembersCommandID:      sMembersQueryArgumentsNumber,
+	SCardCommandID:         sCardQueryArgumentsNumber,
+	ZScoreCommandID:        zScoreQueryArgumentsNumber,
+	ZRankCommandID:         zRankQueryArgumentsNumber,
+	ZRangeCommandID:        zRangeQueryArgumentsNumber,
+	ZRangeByScoreCommandID: zRangeByScoreQueryArgumentsNumber,
+	ZCardCommandID:         zCardQueryArgumentsNumber,
+	PublishCommandID:       publishQueryArgumentsNumber,
+	AuthCommandID:          authQueryArgumentsNumber,
+}
+
+type variadicArguments struct {
+	leading   int
+	minimum   int
+	groupSize int
+}
+
+// variadicQueryArguments describes commands taking arbitrary number of
+// arguments after the leading ones (like a key), which must come in
+// groups of the same size (keys or pairs)
+var variadicQueryArguments = map[int]variadicArguments{
+	HSetCommandID:         {leading: 1, minimum: 2, groupSize: 2},
+	HDelCommandID:         {leading: 1, minimum: 1, groupSize: 1},
+	LPushCommandID:        {leading: 1, minimum: 1, groupSize: 1},
+	RPushCommandID:        {leading: 1, minimum: 1, groupSize: 1},
+	SAddCommandID:         {leading: 1, minimum: 1, groupSize: 1},
+	SRemCommandID:         {leading: 1, minimum: 1, groupSize: 1},
+	ZAddCommandID:         {leading: 1, minimum: 2, groupSize: 2},
+	ZRemCommandID:         {leading: 1, minimum: 1, groupSize: 1},
+	SInterCommandID:       {minimum: 1, groupSize: 1},
+	SUnionCommandID:       {minimum: 1, groupSize: 1},
+	SDiffCommandID:        {minimum: 1, groupSize: 1},
+	MGetCommandID:         {minimum: 1, groupSize: 1},
+	MSetCommandID:         {minimum: 2, groupSize: 2},
+	MDelCommandID:         {minimum: 1, groupSize: 1},
+	ExistsCommandID:       {minimum: 1, groupSize: 1},
+	WatchCommandID:        {minimum: 1, groupSize: 1},
+	BGetDelCommandID:      {minimum: 2, groupSize: 1},
+	SubscribeCommandID:    {minimum: 1, groupSize: 1},
+	UnsubscribeCommandID:  {groupSize: 1},
+	PSubscribeCommandID:   {minimum: 1, groupSize: 1},
+	PUnsubscribeCommandID: {groupSize: 1},
+	EvalCommandID:         {minimum: 2, groupSize: 1},
+}
+
+// queryOptions describes optional arguments that may follow the
+// required ones, with the number of values (zero or one) each option takes
+var queryOptions = map[int]map[string]int{
+	SetCommandID: {
+		SecondsExpirationOption:      1,
+		MillisecondsExpirationOption: 1,
+		OnlyIfAbsentOption:           0,
+		OnlyIfPresentOption:          0,
+	},
+	ScanCommandID: {
+		MatchOption: 1,
+		CountOption: 1,
+	},
+	ZRangeCommandID: {
+		WithScoresOption: 0,
+	},
+	ZRangeByScoreCommandID: {
+		WithScoresOption: 0,
+	},
+}
+
+var conflictingOptions = map[string]string{
+	SecondsExpirationOption:      MillisecondsExpirationOption,
+	MillisecondsExpirationOption: SecondsExpirationOption,
+	OnlyIfAbsentOption:           OnlyIfPresentOption,
+	OnlyIfPresentOption:          OnlyIfAbsentOption,
 }
 
 var (
-	errInvalidSymbol    = errors.New("invalid symbol")
-	errInvalidCommand   = errors.New("invalid command")
-	errInvalidArguments = errors.New("invalid arguments")
+	errInvalidSymbol     = errors.New("invalid symbol")
+	errInvalidEscape     = errors.New("invalid escape sequence")
+	errUnterminatedQuote = errors.New("unterminated quoted string")
+	errInvalidCommand    = errors.New("invalid command")
+	errInvalidArguments  = errors.New("invalid arguments")
+	errInvalidOptions    = errors.New("invalid options")
 )
 
 type Analyzer struct {
@@ -57,18 +211,36 @@ func (a *Analyzer) AnalyzeQuery(ctx context.Context, tokens []string) (Query, er
 		return Query{}, errInvalidCommand
 	}
 
-	query := NewQuery(commandID, tokens[1:])
+	arguments := tokens[1:]
+	if variadic, found := variadicQueryArguments[commandID]; found {
+		return a.analyzeVariadicQuery(ctx, commandID, arguments, variadic)
+	}
+
 	argumentsNumber := queryArgumentsNumber[commandID]
-	if len(query.Arguments()) != argumentsNumber {
+	_, withOptions := queryOptions[commandID]
+	if len(arguments) < argumentsNumber || (!withOptions && len(arguments) != argumentsNumber) {
 		txID := ctx.Value("tx").(int64)
 		a.logger.Debug(
 			"invalid arguments for query",
 			zap.Int64("tx", txID),
-			zap.Any("args", query.Arguments()),
+			zap.Any("args", RedactTokens(tokens)[1:]),
 		)
 		return Query{}, errInvalidArguments
 	}
 
+	options, err := a.analyzeOptions(commandID, arguments[argumentsNumber:])
+	if err != nil {
+		txID := ctx.Value("tx").(int64)
+		a.logger.Debug(
+			"invalid options for query",
+			zap.Int64("tx", txID),
+			zap.Any("options", arguments[argumentsNumber:]),
+		)
+		return Query{}, err
+	}
+
+	query := NewQueryWithOptions(commandID, arguments[:argumentsNumber], options)
+
 	txID := ctx.Value("tx").(int64)
 	a.logger.Debug(
 		"query analyzed",
@@ -78,3 +250,69 @@ func (a *Analyzer) AnalyzeQuery(ctx context.Context, tokens []string) (Query, er
 
 	return query, nil
 }
+
+func (a *Analyzer) analyzeVariadicQuery(
+	ctx context.Context,
+	commandID int,
+	arguments []string,
+	variadic variadicArguments,
+) (Query, error) {
+	txID := ctx.Value("tx").(int64)
+	grouped := len(arguments) - variadic.leading
+	if grouped < variadic.minimum || grouped%variadic.groupSize != 0 {
+		a.logger.Debug(
+			"invalid arguments for query",
+			zap.Int64("tx", txID),
+			zap.Any("args", arguments),
+		)
+		return Query{}, errInvalidArguments
+	}
+
+	query := NewQuery(commandID, arguments)
+	a.logger.Debug(
+		"query analyzed",
+		zap.Int64("tx", txID),
+		zap.Any("query", query),
+	)
+
+	return query, nil
+}
+
+func (a *Analyzer) analyzeOptions(commandID int, tokens []string) (map[string]string, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	allowedOptions := queryOptions[commandID]
+	options := make(map[string]string, len(tokens))
+	for idx := 0; idx < len(tokens); idx++ {
+		// names of options are case-insensitive like in Redis
+		name := strings.ToUpper(tokens[idx])
+		valuesNumber, found := allowedOptions[name]
+		if !found {
+			return nil, errInvalidOptions
+		}
+
+		if _, found := options[name]; found {
+			return nil, errInvalidOptions
+		}
+
+		if conflict, found := conflictingOptions[name]; found {
+			if _, found := options[conflict]; found {
+				return nil, errInvalidOptions
+			}
+		}
+
+		if idx+valuesNumber >= len(tokens) {
+			return nil, errInvalidOptions
+		}
+
+		options[name] = ""
+		if valuesNumber > 0 {
+			options[name] = tokens[idx+1]
+			idx += valuesNumber
+		}
+	}
+
+	return options, nil
+}
@@ -57,6 +57,310 @@ func TestAnalyzeQuery(t *testing.T) {
 			tokens: []string{"DEL", "key"},
 			query:  NewQuery(DelCommandID, []string{"key"}),
 		},
+		"valid set query with expiration in seconds": {
+			tokens: []string{"SET", "key", "value", "EX", "10"},
+			query:  NewQueryWithOptions(SetCommandID, []string{"key", "value"}, map[string]string{"EX": "10"}),
+		},
+		"valid set query with expiration in milliseconds": {
+			tokens: []string{"SET", "key", "value", "PX", "100"},
+			query:  NewQueryWithOptions(SetCommandID, []string{"key", "value"}, map[string]string{"PX": "100"}),
+		},
+		"valid set query with lowercase options": {
+			tokens: []string{"SET", "key", "value", "ex", "10", "Nx"},
+			query:  NewQueryWithOptions(SetCommandID, []string{"key", "value"}, map[string]string{"EX": "10", "NX": ""}),
+		},
+		"set query with options differing by case": {
+			tokens: []string{"SET", "key", "value", "EX", "10", "ex", "100"},
+			err:    errInvalidOptions,
+		},
+		"set query with unknown option": {
+			tokens: []string{"SET", "key", "value", "KEEP", "10"},
+			err:    errInvalidOptions,
+		},
+		"set query with option without value": {
+			tokens: []string{"SET", "key", "value", "EX"},
+			err:    errInvalidOptions,
+		},
+		"set query with conflicting options": {
+			tokens: []string{"SET", "key", "value", "EX", "10", "PX", "100"},
+			err:    errInvalidOptions,
+		},
+		"valid set query with condition and expiration": {
+			tokens: []string{"SET", "key", "value", "NX", "PX", "100"},
+			query: NewQueryWithOptions(
+				SetCommandID,
+				[]string{"key", "value"},
+				map[string]string{"NX": "", "PX": "100"},
+			),
+		},
+		"set query with conflicting conditions": {
+			tokens: []string{"SET", "key", "value", "NX", "XX"},
+			err:    errInvalidOptions,
+		},
+		"valid setnx query": {
+			tokens: []string{"SETNX", "key", "value"},
+			query:  NewQuery(SetNXCommandID, []string{"key", "value"}),
+		},
+		"valid scan query": {
+			tokens: []string{"SCAN", "0", "MATCH", "user:*", "COUNT", "100"},
+			query: NewQueryWithOptions(
+				ScanCommandID,
+				[]string{"0"},
+				map[string]string{"MATCH": "user:*", "COUNT": "100"},
+			),
+		},
+		"scan query without cursor": {
+			tokens: []string{"SCAN"},
+			err:    errInvalidArguments,
+		},
+		"valid keys query": {
+			tokens: []string{"KEYS", "*"},
+			query:  NewQuery(KeysCommandID, []string{"*"}),
+		},
+		"valid hset query": {
+			tokens: []string{"HSET", "key", "field_1", "value_1", "field_2", "value_2"},
+			query:  NewQuery(HSetCommandID, []string{"key", "field_1", "value_1", "field_2", "value_2"}),
+		},
+		"hset query without value": {
+			tokens: []string{"HSET", "key", "field_1", "value_1", "field_2"},
+			err:    errInvalidArguments,
+		},
+		"hset query without fields": {
+			tokens: []string{"HSET", "key"},
+			err:    errInvalidArguments,
+		},
+		"valid hdel query": {
+			tokens: []string{"HDEL", "key", "field_1"},
+			query:  NewQuery(HDelCommandID, []string{"key", "field_1"}),
+		},
+		"valid hincrby query": {
+			tokens: []string{"HINCRBY", "key", "field", "10"},
+			query:  NewQuery(HIncrByCommandID, []string{"key", "field", "10"}),
+		},
+		"valid lpush query": {
+			tokens: []string{"LPUSH", "key", "value_1", "value_2"},
+			query:  NewQuery(LPushCommandID, []string{"key", "value_1", "value_2"}),
+		},
+		"rpush query without values": {
+			tokens: []string{"RPUSH", "key"},
+			err:    errInvalidArguments,
+		},
+		"valid lrange query": {
+			tokens: []string{"LRANGE", "key", "0", "-1"},
+			query:  NewQuery(LRangeCommandID, []string{"key", "0", "-1"}),
+		},
+		"invalid number arguments for ltrim query": {
+			tokens: []string{"LTRIM", "key", "0"},
+			err:    errInvalidArguments,
+		},
+		"valid sadd query": {
+			tokens: []string{"SADD", "key", "member_1", "member_2"},
+			query:  NewQuery(SAddCommandID, []string{"key", "member_1", "member_2"}),
+		},
+		"srem query without members": {
+			tokens: []string{"SREM", "key"},
+			err:    errInvalidArguments,
+		},
+		"valid sinter query": {
+			tokens: []string{"SINTER", "key_1", "key_2"},
+			query:  NewQuery(SInterCommandID, []string{"key_1", "key_2"}),
+		},
+		"sdiff query without keys": {
+			tokens: []string{"SDIFF"},
+			err:    errInvalidArguments,
+		},
+		"valid zadd query": {
+			tokens: []string{"ZADD", "key", "1.5", "member_1", "2", "member_2"},
+			query:  NewQuery(ZAddCommandID, []string{"key", "1.5", "member_1", "2", "member_2"}),
+		},
+		"zadd query without member": {
+			tokens: []string{"ZADD", "key", "1.5"},
+			err:    errInvalidArguments,
+		},
+		"valid zrange query with scores": {
+			tokens: []string{"ZRANGE", "key", "0", "-1", "WITHSCORES"},
+			query: NewQueryWithOptions(
+				ZRangeCommandID,
+				[]string{"key", "0", "-1"},
+				map[string]string{"WITHSCORES": ""},
+			),
+		},
+		"valid zrangebyscore query": {
+			tokens: []string{"ZRANGEBYSCORE", "key", "(1", "+inf"},
+			query:  NewQuery(ZRangeByScoreCommandID, []string{"key", "(1", "+inf"}),
+		},
+		"zrangebyscore query with invalid option": {
+			tokens: []string{"ZRANGEBYSCORE", "key", "0", "1", "LIMIT"},
+			err:    errInvalidOptions,
+		},
+		"valid bgetdel query": {
+			tokens: []string{"BGETDEL", "key_1", "key_2", "0.5"},
+			query:  NewQuery(BGetDelCommandID, []string{"key_1", "key_2", "0.5"}),
+		},
+		"bgetdel query without timeout": {
+			tokens: []string{"BGETDEL", "key_1"},
+			err:    errInvalidArguments,
+		},
+		"valid publish query": {
+			tokens: []string{"PUBLISH", "channel", "message"},
+			query:  NewQuery(PublishCommandID, []string{"channel", "message"}),
+		},
+		"publish query without message": {
+			tokens: []string{"PUBLISH", "channel"},
+			err:    errInvalidArguments,
+		},
+		"valid subscribe query": {
+			tokens: []string{"SUBSCRIBE", "channel_1", "channel_2"},
+			query:  NewQuery(SubscribeCommandID, []string{"channel_1", "channel_2"}),
+		},
+		"subscribe query without channels": {
+			tokens: []string{"SUBSCRIBE"},
+			err:    errInvalidArguments,
+		},
+		"unsubscribe query without channels": {
+			tokens: []string{"UNSUBSCRIBE"},
+			query:  NewQuery(UnsubscribeCommandID, []string{}),
+		},
+		"valid auth query": {
+			tokens: []string{"AUTH", "user", "password"},
+			query:  NewQuery(AuthCommandID, []string{"user", "password"}),
+		},
+		"auth query without password": {
+			tokens: []string{"AUTH", "user"},
+			err:    errInvalidArguments,
+		},
+		"valid eval query": {
+			tokens: []string{"EVAL", "return get(KEYS[1])", "1", "key"},
+			query:  NewQuery(EvalCommandID, []string{"return get(KEYS[1])", "1", "key"}),
+		},
+		"eval query without keys number": {
+			tokens: []string{"EVAL", "return 1"},
+			err:    errInvalidArguments,
+		},
+		"valid psubscribe query": {
+			tokens: []string{"PSUBSCRIBE", "news.*"},
+			query:  NewQuery(PSubscribeCommandID, []string{"news.*"}),
+		},
+		"set query with duplicated option": {
+			tokens: []string{"SET", "key", "value", "EX", "10", "EX", "100"},
+			err:    errInvalidOptions,
+		},
+		"invalid number arguments for expire query": {
+			tokens: []string{"EXPIRE", "key"},
+			err:    errInvalidArguments,
+		},
+		"valid expire query": {
+			tokens: []string{"EXPIRE", "key", "10"},
+			query:  NewQuery(ExpireCommandID, []string{"key", "10"}),
+		},
+		"valid ttl query": {
+			tokens: []string{"TTL", "key"},
+			query:  NewQuery(TTLCommandID, []string{"key"}),
+		},
+		"valid pttl query": {
+			tokens: []string{"PTTL", "key"},
+			query:  NewQuery(PTTLCommandID, []string{"key"}),
+		},
+		"mget query without keys": {
+			tokens: []string{"MGET"},
+			err:    errInvalidArguments,
+		},
+		"mset query with odd number of arguments": {
+			tokens: []string{"MSET", "key_1", "value_1", "key_2"},
+			err:    errInvalidArguments,
+		},
+		"mdel query without keys": {
+			tokens: []string{"MDEL"},
+			err:    errInvalidArguments,
+		},
+		"valid mget query": {
+			tokens: []string{"MGET", "key_1", "key_2", "key_3"},
+			query:  NewQuery(MGetCommandID, []string{"key_1", "key_2", "key_3"}),
+		},
+		"valid mset query": {
+			tokens: []string{"MSET", "key_1", "value_1", "key_2", "value_2"},
+			query:  NewQuery(MSetCommandID, []string{"key_1", "value_1", "key_2", "value_2"}),
+		},
+		"valid mdel query": {
+			tokens: []string{"MDEL", "key_1"},
+			query:  NewQuery(MDelCommandID, []string{"key_1"}),
+		},
+		"exists query without keys": {
+			tokens: []string{"EXISTS"},
+			err:    errInvalidArguments,
+		},
+		"valid exists query": {
+			tokens: []string{"EXISTS", "key_1", "key_2"},
+			query:  NewQuery(ExistsCommandID, []string{"key_1", "key_2"}),
+		},
+		"invalid number arguments for begin query": {
+			tokens: []string{"BEGIN", "key"},
+			err:    errInvalidArguments,
+		},
+		"valid begin query": {
+			tokens: []string{"BEGIN"},
+			query:  NewQuery(BeginCommandID, []string{}),
+		},
+		"valid commit query": {
+			tokens: []string{"COMMIT"},
+			query:  NewQuery(CommitCommandID, []string{}),
+		},
+		"valid rollback query": {
+			tokens: []string{"ROLLBACK"},
+			query:  NewQuery(RollbackCommandID, []string{}),
+		},
+		"watch query without keys": {
+			tokens: []string{"WATCH"},
+			err:    errInvalidArguments,
+		},
+		"valid watch query": {
+			tokens: []string{"WATCH", "key_1", "key_2"},
+			query:  NewQuery(WatchCommandID, []string{"key_1", "key_2"}),
+		},
+		"valid unwatch query": {
+			tokens: []string{"UNWATCH"},
+			query:  NewQuery(UnwatchCommandID, []string{}),
+		},
+		"invalid number arguments for cas query": {
+			tokens: []string{"CAS", "key", "expected"},
+			err:    errInvalidArguments,
+		},
+		"valid cas query": {
+			tokens: []string{"CAS", "key", "expected", "value"},
+			query:  NewQuery(CASCommandID, []string{"key", "expected", "value"}),
+		},
+		"valid incr query": {
+			tokens: []string{"INCR", "key"},
+			query:  NewQuery(IncrCommandID, []string{"key"}),
+		},
+		"invalid number arguments for incrby query": {
+			tokens: []string{"INCRBY", "key"},
+			err:    errInvalidArguments,
+		},
+		"valid incrbyfloat query": {
+			tokens: []string{"INCRBYFLOAT", "key", "-1.5"},
+			query:  NewQuery(IncrByFloatCommandID, []string{"key", "-1.5"}),
+		},
+		"valid append query": {
+			tokens: []string{"APPEND", "key", "value"},
+			query:  NewQuery(AppendCommandID, []string{"key", "value"}),
+		},
+		"invalid number arguments for getrange query": {
+			tokens: []string{"GETRANGE", "key", "0"},
+			err:    errInvalidArguments,
+		},
+		"valid setrange query": {
+			tokens: []string{"SETRANGE", "key", "6", "value"},
+			query:  NewQuery(SetRangeCommandID, []string{"key", "6", "value"}),
+		},
+		"valid getdel query": {
+			tokens: []string{"GETDEL", "key"},
+			query:  NewQuery(GetDelCommandID, []string{"key"}),
+		},
+		"valid persist query": {
+			tokens: []string{"PERSIST", "key"},
+			query:  NewQuery(PersistCommandID, []string{"key"}),
+		},
 	}
 
 	ctx := context.WithValue(context.Background(), "tx", int64(555))
@@ -1,26 +1,238 @@
 package compute
 
+import "strings"
+
+const redactedArguments = "<redacted>"
+
 const (
 	UnknownCommandID = iota
 	SetCommandID
 	GetCommandID
 	DelCommandID
+	TTLCommandID
+	PTTLCommandID
+	ExpireCommandID
+	PersistCommandID
+	MGetCommandID
+	MSetCommandID
+	MDelCommandID
+	ExistsCommandID
+	BeginCommandID
+	CommitCommandID
+	RollbackCommandID
+	WatchCommandID
+	UnwatchCommandID
+	CASCommandID
+	IncrCommandID
+	DecrCommandID
+	IncrByCommandID
+	DecrByCommandID
+	IncrByFloatCommandID
+	AppendCommandID
+	StrLenCommandID
+	GetRangeCommandID
+	SetRangeCommandID
+	GetSetCommandID
+	GetDelCommandID
+	SetNXCommandID
+	ScanCommandID
+	KeysCommandID
+	HSetCommandID
+	HGetCommandID
+	HDelCommandID
+	HGetAllCommandID
+	HLenCommandID
+	HExistsCommandID
+	HIncrByCommandID
+	LPushCommandID
+	RPushCommandID
+	LPopCommandID
+	RPopCommandID
+	LLenCommandID
+	LRangeCommandID
+	LIndexCommandID
+	LTrimCommandID
+	SAddCommandID
+	SRemCommandID
+	SIsMemberCommandID
+	SMembersCommandID
+	SCardCommandID
+	SInterCommandID
+	SUnionCommandID
+	SDiffCommandID
+	ZAddCommandID
+	ZRemCommandID
+	ZScoreCommandID
+	ZRankCommandID
+	ZRangeCommandID
+	ZRangeByScoreCommandID
+	ZCardCommandID
+	BGetDelCommandID
+	PublishCommandID
+	SubscribeCommandID
+	UnsubscribeCommandID
+	PSubscribeCommandID
+	PUnsubscribeCommandID
+	EvalCommandID
+	AuthCommandID
 )
 
 var (
-	UnknownCommand = "UNKNOWN"
-	SetCommand     = "SET"
-	GetCommand     = "GET"
-	DelCommand     = "DEL"
+	UnknownCommand       = "UNKNOWN"
+	SetCommand           = "SET"
+	GetCommand           = "GET"
+	DelCommand           = "DEL"
+	TTLCommand           = "TTL"
+	PTTLCommand          = "PTTL"
+	ExpireCommand        = "EXPIRE"
+	PersistCommand       = "PERSIST"
+	MGetCommand          = "MGET"
+	MSetCommand          = "MSET"
+	MDelCommand          = "MDEL"
+	ExistsCommand        = "EXISTS"
+	BeginCommand         = "BEGIN"
+	CommitCommand        = "COMMIT"
+	RollbackCommand      = "ROLLBACK"
+	WatchCommand         = "WATCH"
+	UnwatchCommand       = "UNWATCH"
+	CASCommand           = "CAS"
+	IncrCommand          = "INCR"
+	DecrCommand          = "DECR"
+	IncrByCommand        = "INCRBY"
+	DecrByCommand        = "DECRBY"
+	IncrByFloatCommand   = "INCRBYFLOAT"
+	AppendCommand        = "APPEND"
+	StrLenCommand        = "STRLEN"
+	GetRangeCommand      = "GETRANGE"
+	SetRangeCommand      = "SETRANGE"
+	GetSetCommand        = "GETSET"
+	GetDelCommand        = "GETDEL"
+	SetNXCommand         = "SETNX"
+	ScanCommand          = "SCAN"
+	KeysCommand          = "KEYS"
+	HSetCommand          = "HSET"
+	HGetCommand          = "HGET"
+	HDelCommand          = "HDEL"
+	HGetAllCommand       = "HGETALL"
+	HLenCommand          = "HLEN"
+	HExistsCommand       = "HEXISTS"
+	HIncrByCommand       = "HINCRBY"
+	LPushCommand         = "LPUSH"
+	RPushCommand         = "RPUSH"
+	LPopCommand          = "LPOP"
+	RPopCommand          = "RPOP"
+	LLenCommand          = "LLEN"
+	LRangeCommand        = "LRANGE"
+	LIndexCommand        = "LINDEX"
+	LTrimCommand         = "LTRIM"
+	SAddCommand          = "SADD"
+	SRemCommand          = "SREM"
+	SIsMemberCommand     = "SISMEMBER"
+	SMembersCommand      = "SMEMBERS"
+	SCardCommand         = "SCARD"
+	SInterCommand        = "SINTER"
+	SUnionCommand        = "SUNION"
+	SDiffCommand         = "SDIFF"
+	ZAddCommand          = "ZADD"
+	ZRemCommand          = "ZREM"
+	ZScoreCommand        = "ZSCORE"
+	ZRankCommand         = "ZRANK"
+	ZRangeCommand        = "ZRANGE"
+	ZRangeByScoreCommand = "ZRANGEBYSCORE"
+	ZCardCommand         = "ZCARD"
+	BGetDelCommand       = "BGETDEL"
+	PublishCommand       = "PUBLISH"
+	SubscribeCommand     = "SUBSCRIBE"
+	UnsubscribeCommand   = "UNSUBSCRIBE"
+	PSubscribeCommand    = "PSUBSCRIBE"
+	PUnsubscribeCommand  = "PUNSUBSCRIBE"
+	EvalCommand          = "EVAL"
+	AuthCommand          = "AUTH"
 )
 
 var commandNamesToId = map[string]int{
-	UnknownCommand: UnknownCommandID,
-	SetCommand:     SetCommandID,
-	GetCommand:     GetCommandID,
-	DelCommand:     DelCommandID,
+	UnknownCommand:       UnknownCommandID,
+	SetCommand:           SetCommandID,
+	GetCommand:           GetCommandID,
+	DelCommand:           DelCommandID,
+	TTLCommand:           TTLCommandID,
+	PTTLCommand:          PTTLCommandID,
+	ExpireCommand:        ExpireCommandID,
+	PersistCommand:       PersistCommandID,
+	MGetCommand:          MGetCommandID,
+	MSetCommand:          MSetCommandID,
+	MDelCommand:          MDelCommandID,
+	ExistsCommand:        ExistsCommandID,
+	BeginCommand:         BeginCommandID,
+	CommitCommand:        CommitCommandID,
+	RollbackCommand:      RollbackCommandID,
+	WatchCommand:         WatchCommandID,
+	UnwatchCommand:       UnwatchCommandID,
+	CASCommand:           CASCommandID,
+	IncrCommand:          IncrCommandID,
+	DecrCommand:          DecrCommandID,
+	IncrByCommand:        IncrByCommandID,
+	DecrByCommand:        DecrByCommandID,
+	IncrByFloatCommand:   IncrByFloatCommandID,
+	AppendCommand:        AppendCommandID,
+	StrLenCommand:        StrLenCommandID,
+	GetRangeCommand:      GetRangeCommandID,
+	SetRangeCommand:      SetRangeCommandID,
+	GetSetCommand:        GetSetCommandID,
+	GetDelCommand:        GetDelCommandID,
+	SetNXCommand:         SetNXCommandID,
+	ScanCommand:          ScanCommandID,
+	KeysCommand:          KeysCommandID,
+	HSetCommand:          HSetCommandID,
+	HGetCommand:          HGetCommandID,
+	HDelCommand:          HDelCommandID,
+	HGetAllCommand:       HGetAllCommandID,
+	HLenCommand:          HLenCommandID,
+	HExistsCommand:       HExistsCommandID,
+	HIncrByCommand:       HIncrByCommandID,
+	LPushCommand:         LPushCommandID,
+	RPushCommand:         RPushCommandID,
+	LPopCommand:          LPopCommandID,
+	RPopCommand:          RPopCommandID,
+	LLenCommand:          LLenCommandID,
+	LRangeCommand:        LRangeCommandID,
+	LIndexCommand:        LIndexCommandID,
+	LTrimCommand:         LTrimCommandID,
+	SAddCommand:          SAddCommandID,
+	SRemCommand:          SRemCommandID,
+	SIsMemberCommand:     SIsMemberCommandID,
+	SMembersCommand:      SMembersCommandID,
+	SCardCommand:         SCardCommandID,
+	SInterCommand:        SInterCommandID,
+	SUnionCommand:        SUnionCommandID,
+	SDiffCommand:         SDiffCommandID,
+	ZAddCommand:          ZAddCommandID,
+	ZRemCommand:          ZRemCommandID,
+	ZScoreCommand:        ZScoreCommandID,
+	ZRankCommand:         ZRankCommandID,
+	ZRangeCommand:        ZRangeCommandID,
+	ZRangeByScoreCommand: ZRangeByScoreCommandID,
+	ZCardCommand:         ZCardCommandID,
+	BGetDelCommand:       BGetDelCommandID,
+	PublishCommand:       PublishCommandID,
+	SubscribeCommand:     SubscribeCommandID,
+	UnsubscribeCommand:   UnsubscribeCommandID,
+	PSubscribeCommand:    PSubscribeCommandID,
+	PUnsubscribeCommand:  PUnsubscribeCommandID,
+	EvalCommand:          EvalCommandID,
+	AuthCommand:          AuthCommandID,
 }
 
+var (
+	SecondsExpirationOption      = "EX"
+	MillisecondsExpirationOption = "PX"
+	OnlyIfAbsentOption           = "NX"
+	OnlyIfPresentOption          = "XX"
+	MatchOption                  = "MATCH"
+	CountOption                  = "COUNT"
+	WithScoresOption             = "WITHSCORES"
+)
+
 func CommandNameToCommandID(command string) int {
 	status, found := commandNamesToId[command]
 	if !found {
@@ -29,3 +241,23 @@ func CommandNameToCommandID(command string) int {
 
 	return status
 }
+
+// RedactTokens hides arguments of AUTH command, so
+// passwords don't get to logs, other tokens are kept
+func RedactTokens(tokens []string) []string {
+	if len(tokens) < 2 || !strings.EqualFold(tokens[0], AuthCommand) {
+		return tokens
+	}
+
+	return []string{tokens[0], redactedArguments}
+}
+
+// RedactQuery is RedactTokens for the raw query
+func RedactQuery(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) < 2 || !strings.EqualFold(fields[0], AuthCommand) {
+		return query
+	}
+
+	return fields[0] + " " + redactedArguments
+}
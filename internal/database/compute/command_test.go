@@ -11,5 +11,83 @@ func TestCommandNameToCommandID(t *testing.T) {
 	require.Equal(t, SetCommandID, CommandNameToCommandID("SET"))
 	require.Equal(t, GetCommandID, CommandNameToCommandID("GET"))
 	require.Equal(t, DelCommandID, CommandNameToCommandID("DEL"))
+	require.Equal(t, TTLCommandID, CommandNameToCommandID("TTL"))
+	require.Equal(t, PTTLCommandID, CommandNameToCommandID("PTTL"))
+	require.Equal(t, ExpireCommandID, CommandNameToCommandID("EXPIRE"))
+	require.Equal(t, PersistCommandID, CommandNameToCommandID("PERSIST"))
+	require.Equal(t, MGetCommandID, CommandNameToCommandID("MGET"))
+	require.Equal(t, MSetCommandID, CommandNameToCommandID("MSET"))
+	require.Equal(t, MDelCommandID, CommandNameToCommandID("MDEL"))
+	require.Equal(t, ExistsCommandID, CommandNameToCommandID("EXISTS"))
+	require.Equal(t, BeginCommandID, CommandNameToCommandID("BEGIN"))
+	require.Equal(t, CommitCommandID, CommandNameToCommandID("COMMIT"))
+	require.Equal(t, RollbackCommandID, CommandNameToCommandID("ROLLBACK"))
+	require.Equal(t, WatchCommandID, CommandNameToCommandID("WATCH"))
+	require.Equal(t, UnwatchCommandID, CommandNameToCommandID("UNWATCH"))
+	require.Equal(t, CASCommandID, CommandNameToCommandID("CAS"))
+	require.Equal(t, IncrCommandID, CommandNameToCommandID("INCR"))
+	require.Equal(t, DecrCommandID, CommandNameToCommandID("DECR"))
+	require.Equal(t, IncrByCommandID, CommandNameToCommandID("INCRBY"))
+	require.Equal(t, DecrByCommandID, CommandNameToCommandID("DECRBY"))
+	require.Equal(t, IncrByFloatCommandID, CommandNameToCommandID("INCRBYFLOAT"))
+	require.Equal(t, AppendCommandID, CommandNameToCommandID("APPEND"))
+	require.Equal(t, StrLenCommandID, CommandNameToCommandID("STRLEN"))
+	require.Equal(t, GetRangeCommandID, CommandNameToCommandID("GETRANGE"))
+	require.Equal(t, SetRangeCommandID, CommandNameToCommandID("SETRANGE"))
+	require.Equal(t, GetSetCommandID, CommandNameToCommandID("GETSET"))
+	require.Equal(t, GetDelCommandID, CommandNameToCommandID("GETDEL"))
+	require.Equal(t, SetNXCommandID, CommandNameToCommandID("SETNX"))
+	require.Equal(t, ScanCommandID, CommandNameToCommandID("SCAN"))
+	require.Equal(t, KeysCommandID, CommandNameToCommandID("KEYS"))
+	require.Equal(t, HSetCommandID, CommandNameToCommandID("HSET"))
+	require.Equal(t, HGetCommandID, CommandNameToCommandID("HGET"))
+	require.Equal(t, HDelCommandID, CommandNameToCommandID("HDEL"))
+	require.Equal(t, HGetAllCommandID, CommandNameToCommandID("HGETALL"))
+	require.Equal(t, HLenCommandID, CommandNameToCommandID("HLEN"))
+	require.Equal(t, HExistsCommandID, CommandNameToCommandID("HEXISTS"))
+	require.Equal(t, HIncrByCommandID, CommandNameToCommandID("HINCRBY"))
+	require.Equal(t, LPushCommandID, CommandNameToCommandID("LPUSH"))
+	require.Equal(t, RPushCommandID, CommandNameToCommandID("RPUSH"))
+	require.Equal(t, LPopCommandID, CommandNameToCommandID("LPOP"))
+	require.Equal(t, RPopCommandID, CommandNameToCommandID("RPOP"))
+	require.Equal(t, LLenCommandID, CommandNameToCommandID("LLEN"))
+	require.Equal(t, LRangeCommandID, CommandNameToCommandID("LRANGE"))
+	require.Equal(t, LIndexCommandID, CommandNameToCommandID("LINDEX"))
+	require.Equal(t, LTrimCommandID, CommandNameToCommandID("LTRIM"))
+	require.Equal(t, SAddCommandID, CommandNameToCommandID("SADD"))
+	require.Equal(t, SRemCommandID, CommandNameToCommandID("SREM"))
+	require.Equal(t, SIsMemberCommandID, CommandNameToCommandID("SISMEMBER"))
+	require.Equal(t, SMembersCommandID, CommandNameToCommandID("SMEMBERS"))
+	require.Equal(t, SCardCommandID, CommandNameToCommandID("SCARD"))
+	require.Equal(t, SInterCommandID, CommandNameToCommandID("SINTER"))
+	require.Equal(t, SUnionCommandID, CommandNameToCommandID("SUNION"))
+	require.Equal(t, SDiffCommandID, CommandNameToCommandID("SDIFF"))
+	require.Equal(t, ZAddCommandID, CommandNameToCommandID("ZADD"))
+	require.Equal(t, ZRemCommandID, CommandNameToCommandID("ZREM"))
+	require.Equal(t, ZScoreCommandID, CommandNameToCommandID("ZSCORE"))
+	require.Equal(t, ZRankCommandID, CommandNameToCommandID("ZRANK"))
+	require.Equal(t, ZRangeCommandID, CommandNameToCommandID("ZRANGE"))
+	require.Equal(t, ZRangeByScoreCommandID, CommandNameToCommandID("ZRANGEBYSCORE"))
+	require.Equal(t, ZCardCommandID, CommandNameToCommandID("ZCARD"))
+	require.Equal(t, BGetDelCommandID, CommandNameToCommandID("BGETDEL"))
+	require.Equal(t, PublishCommandID, CommandNameToCommandID("PUBLISH"))
+	require.Equal(t, SubscribeCommandID, CommandNameToCommandID("SUBSCRIBE"))
+	require.Equal(t, UnsubscribeCommandID, CommandNameToCommandID("UNSUBSCRIBE"))
+	require.Equal(t, PSubscribeCommandID, CommandNameToCommandID("PSUBSCRIBE"))
+	require.Equal(t, PUnsubscribeCommandID, CommandNameToCommandID("PUNSUBSCRIBE"))
+	require.Equal(t, EvalCommandID, CommandNameToCommandID("EVAL"))
+	require.Equal(t, AuthCommandID, CommandNameToCommandID("AUTH"))
 	require.Equal(t, UnknownCommandID, CommandNameToCommandID("TRUNCATE"))
 }
+
+func TestRedactTokens(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"AUTH", "<redacted>"}, RedactTokens([]string{"AUTH", "user", "password"}))
+	require.Equal(t, []string{"auth", "<redacted>"}, RedactTokens([]string{"auth", "user"}))
+	require.Equal(t, []string{"AUTH"}, RedactTokens([]string{"AUTH"}))
+	require.Equal(t, []string{"GET", "key"}, RedactTokens([]string{"GET", "key"}))
+
+	require.Equal(t, "AUTH <redacted>", RedactQuery("AUTH user password"))
+	require.Equal(t, "GET key", RedactQuery("GET key"))
+}
@@ -53,3 +53,9 @@ func (d *Compute) HandleQuery(ctx context.Context, queryStr string) (Query, erro
 
 	return query, nil
 }
+
+// HandleTokens analyzes the query, which has been already split
+// into tokens by the protocol, bypassing the text parser
+func (d *Compute) HandleTokens(ctx context.Context, tokens []string) (Query, error) {
+	return d.analyzer.AnalyzeQuery(ctx, tokens)
+}
@@ -99,3 +99,23 @@ func TestHandleQuery(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, NewQuery(GetCommandID, []string{"key"}), query)
 }
+
+func TestHandleTokens(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	parser := NewMockparser(ctrl)
+	analyzer := NewMockanalyzer(ctrl)
+	analyzer.EXPECT().
+		AnalyzeQuery(ctx, []string{"SET", "key", "value with spaces"}).
+		Return(NewQuery(SetCommandID, []string{"key", "value with spaces"}), nil)
+
+	compute, err := NewCompute(parser, analyzer, zap.NewNop())
+	require.NoError(t, err)
+
+	query, err := compute.HandleTokens(ctx, []string{"SET", "key", "value with spaces"})
+	require.NoError(t, err)
+	require.Equal(t, NewQuery(SetCommandID, []string{"key", "value with spaces"}), query)
+}
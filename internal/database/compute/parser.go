@@ -31,7 +31,7 @@ func (p *Parser) ParseQuery(ctx context.Context, query string) ([]string, error)
 	p.logger.Debug(
 		"query parsed",
 		zap.Int64("tx", txID),
-		zap.Any("tokens", tokens),
+		zap.Any("tokens", RedactTokens(tokens)),
 	)
 
 	return tokens, nil
@@ -47,3 +47,33 @@ func isLetter(symbol byte) bool {
 		(symbol >= '0' && symbol <= '9') ||
 		(symbol == '_')
 }
+
+func isPunctuation(symbol byte) bool {
+	switch symbol {
+	case '-', '+', '.', ':', '/', '*', '?', '@', '(', ')':
+		return true
+	default:
+		return false
+	}
+}
+
+func isQuote(symbol byte) bool {
+	return symbol == '"' || symbol == '\''
+}
+
+func isHexDigit(symbol byte) bool {
+	return (symbol >= '0' && symbol <= '9') ||
+		(symbol >= 'a' && symbol <= 'f') ||
+		(symbol >= 'A' && symbol <= 'F')
+}
+
+func hexValue(symbol byte) byte {
+	switch {
+	case symbol >= 'a':
+		return symbol - 'a' + 10
+	case symbol >= 'A':
+		return symbol - 'A' + 10
+	default:
+		return symbol - '0'
+	}
+}
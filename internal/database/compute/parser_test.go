@@ -60,6 +60,66 @@ func TestParse(t *testing.T) {
 			query:  " set   key  ",
 			tokens: []string{"set", "key"},
 		},
+		"query with punctuation in tokens": {
+			query:  "SET user:1 -10.5",
+			tokens: []string{"SET", "user:1", "-10.5"},
+		},
+		"query with exclusive score bound": {
+			query:  "ZRANGEBYSCORE board (10 +inf",
+			tokens: []string{"ZRANGEBYSCORE", "board", "(10", "+inf"},
+		},
+		"query with parentheses in tokens": {
+			query:  "SET (key) value)",
+			tokens: []string{"SET", "(key)", "value)"},
+		},
+		"query with unmatched parentheses": {
+			query:  "SET ( )",
+			tokens: []string{"SET", "(", ")"},
+		},
+		"query with double quoted token": {
+			query:  `SET key "Ann Lee"`,
+			tokens: []string{"SET", "key", "Ann Lee"},
+		},
+		"query with single quoted token": {
+			query:  `SET key 'a "quoted" #value'`,
+			tokens: []string{"SET", "key", `a "quoted" #value`},
+		},
+		"query with empty quoted token": {
+			query:  `SET key ""`,
+			tokens: []string{"SET", "key", ""},
+		},
+		"query with escaped symbols": {
+			query:  `SET user:1 "{\"name\": \"Ann Lee\"}\n\\\x41\x6a"`,
+			tokens: []string{"SET", "user:1", "{\"name\": \"Ann Lee\"}\n\\Aj"},
+		},
+		"query with UTF symbols in quoted token": {
+			query:  `SET key "字文下"`,
+			tokens: []string{"SET", "key", "字文下"},
+		},
+		"query with invalid escape sequence": {
+			query: `SET key "\q"`,
+			err:   errInvalidEscape,
+		},
+		"query with invalid hex escape sequence": {
+			query: `SET key "\x4g"`,
+			err:   errInvalidEscape,
+		},
+		"query with unterminated quote": {
+			query: `SET key "value`,
+			err:   errUnterminatedQuote,
+		},
+		"query with unterminated escape sequence": {
+			query: `SET key "value\`,
+			err:   errUnterminatedQuote,
+		},
+		"query with quote inside word": {
+			query: `SET ke"y value"`,
+			err:   errInvalidSymbol,
+		},
+		"query with word right after quote": {
+			query: `SET "key"value`,
+			err:   errInvalidSymbol,
+		},
 	}
 
 	ctx := context.WithValue(context.Background(), "tx", int64(555))
@@ -3,6 +3,7 @@ package compute
 type Query struct {
 	commandID int
 	arguments []string
+	options   map[string]string
 }
 
 func NewQuery(commandID int, arguments []string) Query {
@@ -12,6 +13,14 @@ func NewQuery(commandID int, arguments []string) Query {
 	}
 }
 
+func NewQueryWithOptions(commandID int, arguments []string, options map[string]string) Query {
+	return Query{
+		commandID: commandID,
+		arguments: arguments,
+		options:   options,
+	}
+}
+
 func (c *Query) CommandID() int {
 	return c.commandID
 }
@@ -19,3 +28,8 @@ func (c *Query) CommandID() int {
 func (c *Query) Arguments() []string {
 	return c.arguments
 }
+
+func (c *Query) Option(name string) (string, bool) {
+	value, found := c.options[name]
+	return value, found
+}
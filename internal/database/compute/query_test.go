@@ -11,3 +11,15 @@ func TestQuery(t *testing.T) {
 	require.Equal(t, GetCommandID, query.CommandID())
 	require.True(t, reflect.DeepEqual([]string{"GET", "key"}, query.Arguments()))
 }
+
+func TestQueryWithOptions(t *testing.T) {
+	query := NewQueryWithOptions(SetCommandID, []string{"key", "value"}, map[string]string{"EX": "10"})
+	require.Equal(t, SetCommandID, query.CommandID())
+
+	value, found := query.Option("EX")
+	require.True(t, found)
+	require.Equal(t, "10", value)
+
+	_, found = query.Option("PX")
+	require.False(t, found)
+}
@@ -7,6 +7,11 @@ import (
 const (
 	foundLetterEvent = iota
 	foundWhiteSpaceEvent
+	foundQuoteEvent
+	foundBackslashEvent
+	foundSymbolEvent
+	foundEndEvent
+
 	// must be last
 	eventsNumber
 )
@@ -15,11 +20,18 @@ const (
 	initialState = iota
 	wordState
 	whiteSpaceState
+	quotedState
+	escapeState
+	hexEscapeState
+	closedQuoteState
 	invalidState
+
 	// must be last
 	statesNumber
 )
 
+const hexEscapeLength = 2
+
 type transition struct {
 	jump   func(byte) int
 	action func()
@@ -28,6 +40,10 @@ type transition struct {
 type stateMachine struct {
 	transitions [statesNumber][eventsNumber]transition
 	state       int
+	err         error
+
+	quote     byte
+	hexDigits []byte
 
 	tokens []string
 	sb     strings.Builder
@@ -42,14 +58,47 @@ func newStateMachine() *stateMachine {
 		initialState: {
 			foundLetterEvent:     transition{jump: machine.appendLetterJump},
 			foundWhiteSpaceEvent: transition{jump: machine.skipWhiteSpaceJump},
+			foundQuoteEvent:      transition{jump: machine.openQuoteJump},
+			foundEndEvent:        transition{jump: machine.skipWhiteSpaceJump},
 		},
 		wordState: {
 			foundLetterEvent:     transition{jump: machine.appendLetterJump},
 			foundWhiteSpaceEvent: transition{jump: machine.skipWhiteSpaceJump, action: machine.addTokenAction},
+			foundEndEvent:        transition{jump: machine.skipWhiteSpaceJump, action: machine.addTokenAction},
 		},
 		whiteSpaceState: {
 			foundLetterEvent:     transition{jump: machine.appendLetterJump},
 			foundWhiteSpaceEvent: transition{jump: machine.skipWhiteSpaceJump},
+			foundQuoteEvent:      transition{jump: machine.openQuoteJump},
+			foundEndEvent:        transition{jump: machine.skipWhiteSpaceJump},
+		},
+		quotedState: {
+			foundLetterEvent:     transition{jump: machine.appendQuotedJump},
+			foundWhiteSpaceEvent: transition{jump: machine.appendQuotedJump},
+			foundQuoteEvent:      transition{jump: machine.closeQuoteJump},
+			foundBackslashEvent:  transition{jump: machine.startEscapeJump},
+			foundSymbolEvent:     transition{jump: machine.appendQuotedJump},
+			foundEndEvent:        transition{jump: machine.unterminatedQuoteJump},
+		},
+		escapeState: {
+			foundLetterEvent:     transition{jump: machine.escapeLetterJump},
+			foundWhiteSpaceEvent: transition{jump: machine.invalidEscapeJump},
+			foundQuoteEvent:      transition{jump: machine.appendQuotedJump},
+			foundBackslashEvent:  transition{jump: machine.appendQuotedJump},
+			foundSymbolEvent:     transition{jump: machine.invalidEscapeJump},
+			foundEndEvent:        transition{jump: machine.unterminatedQuoteJump},
+		},
+		hexEscapeState: {
+			foundLetterEvent:     transition{jump: machine.hexDigitJump},
+			foundWhiteSpaceEvent: transition{jump: machine.invalidEscapeJump},
+			foundQuoteEvent:      transition{jump: machine.invalidEscapeJump},
+			foundBackslashEvent:  transition{jump: machine.invalidEscapeJump},
+			foundSymbolEvent:     transition{jump: machine.invalidEscapeJump},
+			foundEndEvent:        transition{jump: machine.unterminatedQuoteJump},
+		},
+		closedQuoteState: {
+			foundWhiteSpaceEvent: transition{jump: machine.skipWhiteSpaceJump, action: machine.addTokenAction},
+			foundEndEvent:        transition{jump: machine.skipWhiteSpaceJump, action: machine.addTokenAction},
 		},
 		invalidState: {},
 	}
@@ -62,21 +111,38 @@ func (sm *stateMachine) parse(query string) ([]string, error) {
 		symbol := query[i]
 		if isWhiteSpace(symbol) {
 			sm.processEvent(foundWhiteSpaceEvent, symbol)
-		} else if isLetter(symbol) {
+		} else if isLetter(symbol) || isPunctuation(symbol) {
 			sm.processEvent(foundLetterEvent, symbol)
+		} else if isQuote(symbol) {
+			sm.processEvent(foundQuoteEvent, symbol)
+		} else if symbol == '\\' {
+			sm.processEvent(foundBackslashEvent, symbol)
 		} else {
-			return nil, errInvalidSymbol
+			sm.processEvent(foundSymbolEvent, symbol)
+		}
+
+		if sm.state == invalidState {
+			return nil, sm.err
 		}
 	}
 
-	sm.processEvent(foundWhiteSpaceEvent, ' ')
+	sm.processEvent(foundEndEvent, ' ')
+	if sm.state == invalidState {
+		return nil, sm.err
+	}
+
 	return sm.tokens, nil
 }
 
 func (sm *stateMachine) processEvent(event int, symbol byte) {
 	transition := sm.transitions[sm.state][event]
+	if transition.jump == nil {
+		sm.state = sm.invalidSymbolJump(symbol)
+		return
+	}
+
 	sm.state = transition.jump(symbol)
-	if transition.action != nil {
+	if transition.action != nil && sm.state != invalidState {
 		transition.action()
 	}
 }
@@ -90,6 +156,74 @@ func (sm *stateMachine) skipWhiteSpaceJump(byte) int {
 	return whiteSpaceState
 }
 
+func (sm *stateMachine) openQuoteJump(quote byte) int {
+	sm.quote = quote
+	return quotedState
+}
+
+func (sm *stateMachine) closeQuoteJump(quote byte) int {
+	if quote != sm.quote {
+		return sm.appendQuotedJump(quote)
+	}
+
+	return closedQuoteState
+}
+
+func (sm *stateMachine) appendQuotedJump(symbol byte) int {
+	sm.sb.WriteByte(symbol)
+	return quotedState
+}
+
+func (sm *stateMachine) startEscapeJump(byte) int {
+	return escapeState
+}
+
+func (sm *stateMachine) escapeLetterJump(letter byte) int {
+	switch letter {
+	case 'n':
+		return sm.appendQuotedJump('\n')
+	case 't':
+		return sm.appendQuotedJump('\t')
+	case 'r':
+		return sm.appendQuotedJump('\r')
+	case '0':
+		return sm.appendQuotedJump(0)
+	case 'x':
+		sm.hexDigits = sm.hexDigits[:0]
+		return hexEscapeState
+	default:
+		return sm.invalidEscapeJump(letter)
+	}
+}
+
+func (sm *stateMachine) hexDigitJump(digit byte) int {
+	if !isHexDigit(digit) {
+		return sm.invalidEscapeJump(digit)
+	}
+
+	sm.hexDigits = append(sm.hexDigits, digit)
+	if len(sm.hexDigits) < hexEscapeLength {
+		return hexEscapeState
+	}
+
+	return sm.appendQuotedJump(hexValue(sm.hexDigits[0])<<4 | hexValue(sm.hexDigits[1]))
+}
+
+func (sm *stateMachine) invalidSymbolJump(byte) int {
+	sm.err = errInvalidSymbol
+	return invalidState
+}
+
+func (sm *stateMachine) invalidEscapeJump(byte) int {
+	sm.err = errInvalidEscape
+	return invalidState
+}
+
+func (sm *stateMachine) unterminatedQuoteJump(byte) int {
+	sm.err = errUnterminatedQuote
+	return invalidState
+}
+
 func (sm *stateMachine) addTokenAction() {
 	sm.tokens = append(sm.tokens, sm.sb.String())
 	sm.sb.Reset()
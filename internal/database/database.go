@@ -4,24 +4,143 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
 	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
 	"go.uber.org/zap"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	missingKeyTTL    = -2
+	persistentKeyTTL = -1
+)
+
+// maxRangeOffset is the largest offset of SETRANGE,
+// which keeps length of the value within 512 megabytes
+const maxRangeOffset = 512<<20 - 1
+
+const (
+	defaultScanPattern = "*"
+	defaultScanCount   = 10
+)
+
+var (
+	errInvalidExpiration       = errors.New("invalid expiration time")
+	errInvalidInteger          = errors.New("value is not an integer or out of range")
+	errInvalidFloat            = errors.New("value is not a valid float")
+	errInvalidOffset           = errors.New("offset is out of range")
+	errInvalidCount            = errors.New("count must be positive integer")
+	errInvalidTimeout          = errors.New("timeout is not a float or out of range")
+	errSessionRequired         = errors.New("transactions require client session")
+	errTransactionStarted      = errors.New("transaction is already started")
+	errTransactionNotStarted   = errors.New("transaction is not started")
+	errNotAllowedInTransaction = errors.New("command is not allowed in transaction")
+	errNotAllowedWithWatches   = errors.New("command is not allowed while keys are watched")
+)
+
+// transactionalCommands can be executed inside transactions
+var transactionalCommands = map[int]struct{}{
+	compute.SetCommandID:    {},
+	compute.GetCommandID:    {},
+	compute.DelCommandID:    {},
+	compute.MGetCommandID:   {},
+	compute.MSetCommandID:   {},
+	compute.MDelCommandID:   {},
+	compute.ExistsCommandID: {},
+}
+
+// watchedCommands are aborted if any of the watched keys has been changed
+var watchedCommands = map[int]struct{}{
+	compute.SetCommandID:  {},
+	compute.DelCommandID:  {},
+	compute.MSetCommandID: {},
+	compute.MDelCommandID: {},
+}
+
+// queryDispatcher handles the query by its command
+type queryDispatcher func(context.Context, compute.Query) string
+
 type computeLayer interface {
 	HandleQuery(context.Context, string) (compute.Query, error)
+	HandleTokens(context.Context, []string) (compute.Query, error)
 }
 
-type storageLayer interface {
+// keyValueLayer is implemented both by storage and by
+// transactions, which buffer writes until commit
+type keyValueLayer interface {
 	Set(ctx context.Context, key, value string) error
+	SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
-	Del(ctx context.Context, key string) error
+	Del(ctx context.Context, key string) (bool, error)
+	MGet(ctx context.Context, keys []string) ([]*string, error)
+	MSet(ctx context.Context, pairs []string) error
+	MDel(ctx context.Context, keys []string) (int, error)
+	Exists(ctx context.Context, keys []string) (int, error)
+}
+
+type storageLayer interface {
+	keyValueLayer
+	Begin() *storage.Transaction
+	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Persist(ctx context.Context, key string) (bool, error)
+	TTL(ctx context.Context, key string) (time.Duration, bool, error)
+	CompareAndSet(ctx context.Context, key, expected, value string) (bool, error)
+	SetIf(ctx context.Context, key, value string, ttl time.Duration, exists bool) (bool, error)
+	Version(ctx context.Context, key string) int64
+	Scan(ctx context.Context, cursor, pattern string, count int) ([]string, string, error)
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	HSet(ctx context.Context, key string, pairs []string) (int, error)
+	HGet(ctx context.Context, key, field string) (string, error)
+	HDel(ctx context.Context, key string, fields []string) (int, error)
+	HGetAll(ctx context.Context, key string) ([]string, error)
+	HLen(ctx context.Context, key string) (int, error)
+	HExists(ctx context.Context, key, field string) (bool, error)
+	HIncrBy(ctx context.Context, key, field string, delta int64) (int64, error)
+	LPush(ctx context.Context, key string, values []string) (int, error)
+	RPush(ctx context.Context, key string, values []string) (int, error)
+	LPop(ctx context.Context, key string) (string, error)
+	RPop(ctx context.Context, key string) (string, error)
+	LLen(ctx context.Context, key string) (int, error)
+	LRange(ctx context.Context, key string, start, stop int) ([]string, error)
+	LIndex(ctx context.Context, key string, index int) (string, error)
+	LTrim(ctx context.Context, key string, start, stop int) error
+	SAdd(ctx context.Context, key string, members []string) (int, error)
+	SRem(ctx context.Context, key string, members []string) (int, error)
+	SIsMember(ctx context.Context, key, member string) (bool, error)
+	SMembers(ctx context.Context, key string) ([]string, error)
+	SCard(ctx context.Context, key string) (int, error)
+	SInter(ctx context.Context, keys []string) ([]string, error)
+	SUnion(ctx context.Context, keys []string) ([]string, error)
+	SDiff(ctx context.Context, keys []string) ([]string, error)
+	ZAdd(ctx context.Context, key string, pairs []string) (int, error)
+	ZRem(ctx context.Context, key string, members []string) (int, error)
+	ZScore(ctx context.Context, key, member string) (float64, error)
+	ZRank(ctx context.Context, key, member string) (int, error)
+	ZRange(ctx context.Context, key string, start, stop int) ([]string, error)
+	ZRangeByScore(ctx context.Context, key string, min, max float64) ([]string, error)
+	ZCard(ctx context.Context, key string) (int, error)
+	IncrBy(ctx context.Context, key string, delta int64) (int64, error)
+	IncrByFloat(ctx context.Context, key string, delta float64) (float64, error)
+	Append(ctx context.Context, key, value string) (int, error)
+	StrLen(ctx context.Context, key string) (int, error)
+	GetRange(ctx context.Context, key string, start, end int) (string, error)
+	SetRange(ctx context.Context, key string, offset int, value string) (int, error)
+	GetSet(ctx context.Context, key, value string) (string, error)
+	GetDel(ctx context.Context, key string) (string, error)
+	BGetDel(ctx context.Context, keys []string, timeout time.Duration) (string, string, error)
+	Eval(ctx context.Context, source string, keys, args []string) (string, bool, error)
 }
 
 type Database struct {
 	computeLayer computeLayer
 	storageLayer storageLayer
 	idGenerator  *IDGenerator
+	broker       *broker
+	acl          *acl.ACL
 	logger       *zap.Logger
 }
 
@@ -42,62 +161,718 @@ func NewDatabase(computeLayer computeLayer, storageLayer storageLayer, logger *z
 		computeLayer: computeLayer,
 		storageLayer: storageLayer,
 		idGenerator:  NewIDGenerator(),
+		broker:       newBroker(logger),
 		logger:       logger,
 	}, nil
 }
 
+// IDGenerator returns generator of transaction IDs, it must be shared
+// with other layers writing to the storage, so LSNs aren't repeated
+func (d *Database) IDGenerator() *IDGenerator {
+	return d.idGenerator
+}
+
 func (d *Database) HandleQuery(ctx context.Context, queryStr string) string {
+	ctx, query, err := d.parseQuery(ctx, queryStr)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return d.handleQuery(ctx, query)
+}
+
+// parseQuery returns context of the query with new
+// transaction ID and the query analyzed by compute layer
+func (d *Database) parseQuery(ctx context.Context, queryStr string) (context.Context, compute.Query, error) {
 	txID := d.idGenerator.Generate()
 	ctx = context.WithValue(ctx, "tx", txID)
 
 	d.logger.Debug(
 		"handling query",
 		zap.Int64("tx", txID),
-		zap.String("query", queryStr),
+		zap.String("query", compute.RedactQuery(queryStr)),
 	)
 
 	query, err := d.computeLayer.HandleQuery(ctx, queryStr)
-	if err != nil {
+	return ctx, query, err
+}
+
+func (d *Database) handleQuery(ctx context.Context, query compute.Query) string {
+	return d.handleQueryBy(ctx, query, d.dispatchQuery)
+}
+
+// handleQueryBy checks the query against permissions of the user and
+// state of the session and then handles it by dispatch, which is
+// different for protocols with replies richer than text responses
+func (d *Database) handleQueryBy(ctx context.Context, query compute.Query, dispatch queryDispatcher) string {
+	if err := d.authorizeQuery(ctx, query); err != nil {
 		return fmt.Sprintf("[error] %s", err.Error())
 	}
 
+	switch query.CommandID() {
+	case compute.AuthCommandID:
+		return d.handleAuthQuery(ctx, query)
+	case compute.BeginCommandID:
+		return d.handleBeginQuery(ctx)
+	case compute.CommitCommandID:
+		return d.handleCommitQuery(ctx)
+	case compute.RollbackCommandID:
+		return d.handleRollbackQuery(ctx)
+	}
+
+	if session := sessionFromContext(ctx); session != nil && session.transaction != nil {
+		if _, found := transactionalCommands[query.CommandID()]; !found {
+			return fmt.Sprintf("[error] %s", errNotAllowedInTransaction.Error())
+		}
+	} else if session != nil && len(session.watches) != 0 {
+		if _, found := watchedCommands[query.CommandID()]; found && !isConditionalSet(query) {
+			return d.handleWatchedQuery(ctx, session, query, dispatch)
+		}
+
+		if modifiesKeys(query.CommandID()) {
+			return fmt.Sprintf("[error] %s", errNotAllowedWithWatches.Error())
+		}
+	}
+
+	return dispatch(ctx, query)
+}
+
+func (d *Database) dispatchQuery(ctx context.Context, query compute.Query) string {
 	switch query.CommandID() {
 	case compute.SetCommandID:
 		return d.handleSetQuery(ctx, query)
+	case compute.ScanCommandID:
+		return d.handleScanQuery(ctx, query)
+	case compute.KeysCommandID:
+		return d.handleKeysQuery(ctx, query)
+	case compute.HSetCommandID:
+		return d.handleHSetQuery(ctx, query)
+	case compute.HGetCommandID:
+		return d.handleHGetQuery(ctx, query)
+	case compute.HDelCommandID:
+		return d.handleHDelQuery(ctx, query)
+	case compute.HGetAllCommandID:
+		return d.handleHGetAllQuery(ctx, query)
+	case compute.HLenCommandID:
+		return d.handleHLenQuery(ctx, query)
+	case compute.HExistsCommandID:
+		return d.handleHExistsQuery(ctx, query)
+	case compute.HIncrByCommandID:
+		return d.handleHIncrByQuery(ctx, query)
+	case compute.LPushCommandID, compute.RPushCommandID:
+		return d.handlePushQuery(ctx, query)
+	case compute.LPopCommandID, compute.RPopCommandID:
+		return d.handlePopQuery(ctx, query)
+	case compute.LLenCommandID:
+		return d.handleLLenQuery(ctx, query)
+	case compute.LRangeCommandID:
+		return d.handleLRangeQuery(ctx, query)
+	case compute.LIndexCommandID:
+		return d.handleLIndexQuery(ctx, query)
+	case compute.LTrimCommandID:
+		return d.handleLTrimQuery(ctx, query)
+	case compute.SAddCommandID:
+		return d.handleSAddQuery(ctx, query)
+	case compute.SRemCommandID:
+		return d.handleSRemQuery(ctx, query)
+	case compute.SIsMemberCommandID:
+		return d.handleSIsMemberQuery(ctx, query)
+	case compute.SCardCommandID:
+		return d.handleSCardQuery(ctx, query)
+	case compute.SMembersCommandID, compute.SInterCommandID, compute.SUnionCommandID, compute.SDiffCommandID:
+		return d.handleMembersQuery(ctx, query)
+	case compute.ZAddCommandID:
+		return d.handleZAddQuery(ctx, query)
+	case compute.ZRemCommandID:
+		return d.handleZRemQuery(ctx, query)
+	case compute.ZScoreCommandID:
+		return d.handleZScoreQuery(ctx, query)
+	case compute.ZRankCommandID:
+		return d.handleZRankQuery(ctx, query)
+	case compute.ZRangeCommandID:
+		return d.handleZRangeQuery(ctx, query)
+	case compute.ZRangeByScoreCommandID:
+		return d.handleZRangeByScoreQuery(ctx, query)
+	case compute.ZCardCommandID:
+		return d.handleZCardQuery(ctx, query)
+	case compute.SetNXCommandID:
+		return d.handleSetIfQuery(ctx, query, 0, false)
 	case compute.GetCommandID:
 		return d.handleGetQuery(ctx, query)
 	case compute.DelCommandID:
 		return d.handleDelQuery(ctx, query)
+	case compute.MGetCommandID:
+		return d.handleMGetQuery(ctx, query)
+	case compute.MSetCommandID:
+		return d.handleMSetQuery(ctx, query)
+	case compute.MDelCommandID:
+		return d.handleMDelQuery(ctx, query)
+	case compute.ExistsCommandID:
+		return d.handleExistsQuery(ctx, query)
+	case compute.TTLCommandID:
+		return d.handleTTLQuery(ctx, query, time.Second)
+	case compute.PTTLCommandID:
+		return d.handleTTLQuery(ctx, query, time.Millisecond)
+	case compute.ExpireCommandID:
+		return d.handleExpireQuery(ctx, query)
+	case compute.PersistCommandID:
+		return d.handlePersistQuery(ctx, query)
+	case compute.WatchCommandID:
+		return d.handleWatchQuery(ctx, query)
+	case compute.UnwatchCommandID:
+		return d.handleUnwatchQuery(ctx)
+	case compute.CASCommandID:
+		return d.handleCASQuery(ctx, query)
+	case compute.IncrCommandID:
+		return d.handleIncrByQuery(ctx, query.Arguments()[0], 1)
+	case compute.DecrCommandID:
+		return d.handleIncrByQuery(ctx, query.Arguments()[0], -1)
+	case compute.IncrByCommandID, compute.DecrByCommandID:
+		return d.handleIncrByDeltaQuery(ctx, query)
+	case compute.IncrByFloatCommandID:
+		return d.handleIncrByFloatQuery(ctx, query)
+	case compute.AppendCommandID:
+		return d.handleAppendQuery(ctx, query)
+	case compute.StrLenCommandID:
+		return d.handleStrLenQuery(ctx, query)
+	case compute.GetRangeCommandID:
+		return d.handleGetRangeQuery(ctx, query)
+	case compute.SetRangeCommandID:
+		return d.handleSetRangeQuery(ctx, query)
+	case compute.GetSetCommandID:
+		return d.handleGetSetQuery(ctx, query)
+	case compute.BGetDelCommandID:
+		return d.handleBGetDelQuery(ctx, query)
+	case compute.GetDelCommandID:
+		return d.handleGetDelQuery(ctx, query)
+	case compute.EvalCommandID:
+		return d.handleEvalQuery(ctx, query)
+	case compute.PublishCommandID:
+		return d.handlePublishQuery(query)
+	case compute.SubscribeCommandID, compute.PSubscribeCommandID:
+		return d.handleSubscribeQuery(ctx, query)
+	case compute.UnsubscribeCommandID, compute.PUnsubscribeCommandID:
+		return d.handleUnsubscribeQuery(ctx, query)
 	}
 
+	txID := ctx.Value("tx").(int64)
 	d.logger.Error("compute layer is incorrect", zap.Int64("tx", txID))
 	return "[error] internal configuration error"
 }
 
+func (d *Database) handleBeginQuery(ctx context.Context) string {
+	session := sessionFromContext(ctx)
+	if session == nil {
+		return fmt.Sprintf("[error] %s", errSessionRequired.Error())
+	}
+
+	if session.transaction != nil {
+		return fmt.Sprintf("[error] %s", errTransactionStarted.Error())
+	}
+
+	session.transaction = d.beginTransaction(session)
+	return "[ok]"
+}
+
+func (d *Database) handleCommitQuery(ctx context.Context) string {
+	session := sessionFromContext(ctx)
+	if session == nil || session.transaction == nil {
+		return fmt.Sprintf("[error] %s", errTransactionNotStarted.Error())
+	}
+
+	transaction := session.transaction
+	session.transaction = nil
+	if err := transaction.Commit(ctx); err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return "[ok]"
+}
+
+func (d *Database) handleRollbackQuery(ctx context.Context) string {
+	session := sessionFromContext(ctx)
+	if session == nil || session.transaction == nil {
+		return fmt.Sprintf("[error] %s", errTransactionNotStarted.Error())
+	}
+
+	session.transaction = nil
+	return "[ok]"
+}
+
+func (d *Database) handleWatchQuery(ctx context.Context, query compute.Query) string {
+	session := sessionFromContext(ctx)
+	if session == nil {
+		return fmt.Sprintf("[error] %s", errSessionRequired.Error())
+	}
+
+	if session.watches == nil {
+		session.watches = make(map[string]int64)
+	}
+
+	for _, key := range query.Arguments() {
+		if _, found := session.watches[key]; !found {
+			session.watches[key] = d.storageLayer.Version(ctx, key)
+		}
+	}
+
+	return "[ok]"
+}
+
+func (d *Database) handleUnwatchQuery(ctx context.Context) string {
+	if session := sessionFromContext(ctx); session != nil {
+		session.watches = nil
+	}
+
+	return "[ok]"
+}
+
+// handleWatchedQuery executes the write as a transaction of single command,
+// which is aborted if any of the watched keys has been changed
+func (d *Database) handleWatchedQuery(ctx context.Context, session *session, query compute.Query, dispatch queryDispatcher) string {
+	transaction := d.beginTransaction(session)
+	session.transaction = transaction
+	response := dispatch(ctx, query)
+	session.transaction = nil
+
+	if strings.HasPrefix(response, "[error]") {
+		return response
+	}
+
+	if err := transaction.Commit(ctx); err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return response
+}
+
+func (d *Database) handleCASQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	updated, err := d.storageLayer.CompareAndSet(ctx, arguments[0], arguments[1], arguments[2])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", boolToInt(updated))
+}
+
+func (d *Database) handleScanQuery(ctx context.Context, query compute.Query) string {
+	pattern, found := query.Option(compute.MatchOption)
+	if !found {
+		pattern = defaultScanPattern
+	}
+
+	count := defaultScanCount
+	if value, found := query.Option(compute.CountOption); found {
+		var err error
+		if count, err = strconv.Atoi(value); err != nil || count <= 0 {
+			return fmt.Sprintf("[error] %s", errInvalidCount.Error())
+		}
+	}
+
+	keys, cursor, err := d.storageLayer.Scan(ctx, query.Arguments()[0], pattern, count)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	keys = d.accessibleKeys(ctx, keys)
+
+	if len(keys) == 0 {
+		return fmt.Sprintf("[ok] %s", cursor)
+	}
+
+	return fmt.Sprintf("[ok] %s %s", cursor, quoteValues(keys))
+}
+
+func (d *Database) handleKeysQuery(ctx context.Context, query compute.Query) string {
+	keys, err := d.storageLayer.Keys(ctx, query.Arguments()[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	keys = d.accessibleKeys(ctx, keys)
+
+	if len(keys) == 0 {
+		return "[ok]"
+	}
+
+	return fmt.Sprintf("[ok] %s", quoteValues(keys))
+}
+
+func (d *Database) handleIncrByDeltaQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	delta, err := strconv.ParseInt(arguments[1], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", errInvalidInteger.Error())
+	}
+
+	if query.CommandID() == compute.DecrByCommandID {
+		if delta == math.MinInt64 {
+			return fmt.Sprintf("[error] %s", errInvalidInteger.Error())
+		}
+
+		delta = -delta
+	}
+
+	return d.handleIncrByQuery(ctx, arguments[0], delta)
+}
+
+func (d *Database) handleIncrByQuery(ctx context.Context, key string, delta int64) string {
+	value, err := d.storageLayer.IncrBy(ctx, key, delta)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", value)
+}
+
+func (d *Database) handleIncrByFloatQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	delta, err := strconv.ParseFloat(arguments[1], 64)
+	if err != nil || math.IsNaN(delta) || math.IsInf(delta, 0) {
+		return fmt.Sprintf("[error] %s", errInvalidFloat.Error())
+	}
+
+	value, err := d.storageLayer.IncrByFloat(ctx, arguments[0], delta)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func (d *Database) handleAppendQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	length, err := d.storageLayer.Append(ctx, arguments[0], arguments[1])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+func (d *Database) handleStrLenQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	length, err := d.storageLayer.StrLen(ctx, arguments[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+func (d *Database) handleGetRangeQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	start, err := strconv.Atoi(arguments[1])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", errInvalidInteger.Error())
+	}
+
+	end, err := strconv.Atoi(arguments[2])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", errInvalidInteger.Error())
+	}
+
+	value, err := d.storageLayer.GetRange(ctx, arguments[0], start, end)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", value)
+}
+
+func (d *Database) handleSetRangeQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	offset, err := strconv.Atoi(arguments[1])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", errInvalidInteger.Error())
+	}
+
+	if offset < 0 || offset > maxRangeOffset {
+		return fmt.Sprintf("[error] %s", errInvalidOffset.Error())
+	}
+
+	length, err := d.storageLayer.SetRange(ctx, arguments[0], offset, arguments[2])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+func (d *Database) handleGetSetQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	value, err := d.storageLayer.GetSet(ctx, arguments[0], arguments[1])
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", value)
+}
+
+func (d *Database) handleGetDelQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	value, err := d.storageLayer.GetDel(ctx, arguments[0])
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", value)
+}
+
+// handleBGetDelQuery blocks the caller until one of the keys is set,
+// the last argument is timeout in seconds, zero means no timeout
+func (d *Database) handleBGetDelQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	keys := arguments[:len(arguments)-1]
+	seconds, err := strconv.ParseFloat(arguments[len(arguments)-1], 64)
+	timeout := seconds * float64(time.Second)
+	if err != nil || math.IsNaN(timeout) || timeout < 0 || timeout >= math.MaxInt64 {
+		return fmt.Sprintf("[error] %s", errInvalidTimeout.Error())
+	}
+
+	key, value, err := d.storageLayer.BGetDel(ctx, keys, time.Duration(timeout))
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", quoteValues([]string{key, value}))
+}
+
 func (d *Database) handleSetQuery(ctx context.Context, query compute.Query) string {
 	arguments := query.Arguments()
-	if err := d.storageLayer.Set(ctx, arguments[0], arguments[1]); err != nil {
+	ttl, withTTL, err := parseExpirationOptions(query)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if isConditionalSet(query) {
+		_, onlyIfPresent := query.Option(compute.OnlyIfPresentOption)
+		return d.handleSetIfQuery(ctx, query, ttl, onlyIfPresent)
+	}
+
+	if withTTL {
+		err = d.keyValueLayer(ctx).SetWithTTL(ctx, arguments[0], arguments[1], ttl)
+	} else {
+		err = d.keyValueLayer(ctx).Set(ctx, arguments[0], arguments[1])
+	}
+
+	if err != nil {
 		return fmt.Sprintf("[error] %s", err.Error())
 	}
 
 	return "[ok]"
 }
 
-func (d *Database) handleGetQuery(ctx context.Context, query compute.Query) string {
+// handleSetIfQuery writes the value only if existence of the key
+// equals to exists and reports whether the write happened
+func (d *Database) handleSetIfQuery(ctx context.Context, query compute.Query, ttl time.Duration, exists bool) string {
+	if session := sessionFromContext(ctx); session != nil && session.transaction != nil {
+		return fmt.Sprintf("[error] %s", errNotAllowedInTransaction.Error())
+	}
+
 	arguments := query.Arguments()
-	value, err := d.storageLayer.Get(ctx, arguments[0])
+	updated, err := d.storageLayer.SetIf(ctx, arguments[0], arguments[1], ttl, exists)
 	if err != nil {
 		return fmt.Sprintf("[error] %s", err.Error())
 	}
 
+	return fmt.Sprintf("[ok] %d", boolToInt(updated))
+}
+
+func (d *Database) handleGetQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	value, err := d.keyValueLayer(ctx).Get(ctx, arguments[0])
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
 	return fmt.Sprintf("[ok] %s", value)
 }
 
 func (d *Database) handleDelQuery(ctx context.Context, query compute.Query) string {
 	arguments := query.Arguments()
-	if err := d.storageLayer.Del(ctx, arguments[0]); err != nil {
+	if _, err := d.keyValueLayer(ctx).Del(ctx, arguments[0]); err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return "[ok]"
+}
+
+func (d *Database) handleMGetQuery(ctx context.Context, query compute.Query) string {
+	values, err := d.keyValueLayer(ctx).MGet(ctx, query.Arguments())
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", quoteNullableValues(values))
+}
+
+func (d *Database) handleMSetQuery(ctx context.Context, query compute.Query) string {
+	if err := d.keyValueLayer(ctx).MSet(ctx, query.Arguments()); err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return "[ok]"
+}
+
+func (d *Database) handleMDelQuery(ctx context.Context, query compute.Query) string {
+	if _, err := d.keyValueLayer(ctx).MDel(ctx, query.Arguments()); err != nil {
 		return fmt.Sprintf("[error] %s", err.Error())
 	}
 
 	return "[ok]"
 }
+
+func (d *Database) handleExistsQuery(ctx context.Context, query compute.Query) string {
+	count, err := d.keyValueLayer(ctx).Exists(ctx, query.Arguments())
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", count)
+}
+
+func (d *Database) handleTTLQuery(ctx context.Context, query compute.Query, unit time.Duration) string {
+	arguments := query.Arguments()
+	ttl, found, err := d.storageLayer.TTL(ctx, arguments[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if !found {
+		return fmt.Sprintf("[ok] %d", missingKeyTTL)
+	}
+
+	if ttl < 0 {
+		return fmt.Sprintf("[ok] %d", persistentKeyTTL)
+	}
+
+	return fmt.Sprintf("[ok] %d", ttl.Round(unit)/unit)
+}
+
+func (d *Database) handleExpireQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	seconds, err := strconv.ParseInt(arguments[1], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", errInvalidExpiration.Error())
+	}
+
+	ttl, err := unitsToDuration(seconds, time.Second)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	updated, err := d.storageLayer.Expire(ctx, arguments[0], ttl)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", boolToInt(updated))
+}
+
+func (d *Database) handlePersistQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	updated, err := d.storageLayer.Persist(ctx, arguments[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", boolToInt(updated))
+}
+
+// beginTransaction starts transaction, which takes
+// over the keys watched in the session
+func (d *Database) beginTransaction(session *session) *storage.Transaction {
+	transaction := d.storageLayer.Begin()
+	for key, version := range session.watches {
+		transaction.Watch(key, version)
+	}
+
+	session.watches = nil
+	return transaction
+}
+
+func (d *Database) keyValueLayer(ctx context.Context) keyValueLayer {
+	if session := sessionFromContext(ctx); session != nil && session.transaction != nil {
+		return session.transaction
+	}
+
+	return d.storageLayer
+}
+
+func parseExpirationOptions(query compute.Query) (time.Duration, bool, error) {
+	unit := time.Second
+	value, found := query.Option(compute.SecondsExpirationOption)
+	if !found {
+		unit = time.Millisecond
+		value, found = query.Option(compute.MillisecondsExpirationOption)
+	}
+
+	if !found {
+		return 0, false, nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number <= 0 {
+		return 0, false, errInvalidExpiration
+	}
+
+	ttl, err := unitsToDuration(number, unit)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return ttl, true, nil
+}
+
+// unitsToDuration rejects numbers of units, which
+// overflow duration instead of wrapping them around
+func unitsToDuration(number int64, unit time.Duration) (time.Duration, error) {
+	if number > math.MaxInt64/int64(unit) || number < math.MinInt64/int64(unit) {
+		return 0, errInvalidExpiration
+	}
+
+	return time.Duration(number) * unit, nil
+}
+
+func isConditionalSet(query compute.Query) bool {
+	_, onlyIfAbsent := query.Option(compute.OnlyIfAbsentOption)
+	_, onlyIfPresent := query.Option(compute.OnlyIfPresentOption)
+	return onlyIfAbsent || onlyIfPresent
+}
+
+// modifiesKeys reports whether the command can change keys, such
+// commands can't be checked against the watched keys, so they are
+// rejected while keys are watched except the watched commands
+func modifiesKeys(commandID int) bool {
+	switch commandID {
+	case compute.EvalCommandID:
+		return true
+	case compute.PublishCommandID:
+		return false
+	}
+
+	for _, category := range commandCategories[commandID] {
+		if category == acl.WriteCategory {
+			return true
+		}
+	}
+
+	return false
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,1082 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: database.go
+
+// Package database is a generated GoMock package.
+package database
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	compute "github.com/passsquale/key-value-storage/internal/database/compute"
+	storage "github.com/passsquale/key-value-storage/internal/database/storage"
+)
+
+// MockcomputeLayer is a mock of computeLayer interface.
+type MockcomputeLayer struct {
+	ctrl     *gomock.Controller
+	recorder *MockcomputeLayerMockRecorder
+}
+
+// MockcomputeLayerMockRecorder is the mock recorder for MockcomputeLayer.
+type MockcomputeLayerMockRecorder struct {
+	mock *MockcomputeLayer
+}
+
+// NewMockcomputeLayer creates a new mock instance.
+func NewMockcomputeLayer(ctrl *gomock.Controller) *MockcomputeLayer {
+	mock := &MockcomputeLayer{ctrl: ctrl}
+	mock.recorder = &MockcomputeLayerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcomputeLayer) EXPECT() *MockcomputeLayerMockRecorder {
+	return m.recorder
+}
+
+// HandleQuery mocks base method.
+func (m *MockcomputeLayer) HandleQuery(arg0 context.Context, arg1 string) (compute.Query, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleQuery", arg0, arg1)
+	ret0, _ := ret[0].(compute.Query)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleQuery indicates an expected call of HandleQuery.
+func (mr *MockcomputeLayerMockRecorder) HandleQuery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleQuery", reflect.TypeOf((*MockcomputeLayer)(nil).HandleQuery), arg0, arg1)
+}
+
+// HandleTokens mocks base method.
+func (m *MockcomputeLayer) HandleTokens(arg0 context.Context, arg1 []string) (compute.Query, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleTokens", arg0, arg1)
+	ret0, _ := ret[0].(compute.Query)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleTokens indicates an expected call of HandleTokens.
+func (mr *MockcomputeLayerMockRecorder) HandleTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleTokens", reflect.TypeOf((*MockcomputeLayer)(nil).HandleTokens), arg0, arg1)
+}
+
+// MockkeyValueLayer is a mock of keyValueLayer interface.
+type MockkeyValueLayer struct {
+	ctrl     *gomock.Controller
+	recorder *MockkeyValueLayerMockRecorder
+}
+
+// MockkeyValueLayerMockRecorder is the mock recorder for MockkeyValueLayer.
+type MockkeyValueLayerMockRecorder struct {
+	mock *MockkeyValueLayer
+}
+
+// NewMockkeyValueLayer creates a new mock instance.
+func NewMockkeyValueLayer(ctrl *gomock.Controller) *MockkeyValueLayer {
+	mock := &MockkeyValueLayer{ctrl: ctrl}
+	mock.recorder = &MockkeyValueLayerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockkeyValueLayer) EXPECT() *MockkeyValueLayerMockRecorder {
+	return m.recorder
+}
+
+// Del mocks base method.
+func (m *MockkeyValueLayer) Del(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockkeyValueLayerMockRecorder) Del(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockkeyValueLayer)(nil).Del), ctx, key)
+}
+
+// Exists mocks base method.
+func (m *MockkeyValueLayer) Exists(ctx context.Context, keys []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, keys)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockkeyValueLayerMockRecorder) Exists(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockkeyValueLayer)(nil).Exists), ctx, keys)
+}
+
+// Get mocks base method.
+func (m *MockkeyValueLayer) Get(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockkeyValueLayerMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockkeyValueLayer)(nil).Get), ctx, key)
+}
+
+// MDel mocks base method.
+func (m *MockkeyValueLayer) MDel(ctx context.Context, keys []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MDel", ctx, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MDel indicates an expected call of MDel.
+func (mr *MockkeyValueLayerMockRecorder) MDel(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MDel", reflect.TypeOf((*MockkeyValueLayer)(nil).MDel), ctx, keys)
+}
+
+// MGet mocks base method.
+func (m *MockkeyValueLayer) MGet(ctx context.Context, keys []string) ([]*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MGet", ctx, keys)
+	ret0, _ := ret[0].([]*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockkeyValueLayerMockRecorder) MGet(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockkeyValueLayer)(nil).MGet), ctx, keys)
+}
+
+// MSet mocks base method.
+func (m *MockkeyValueLayer) MSet(ctx context.Context, pairs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MSet", ctx, pairs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MSet indicates an expected call of MSet.
+func (mr *MockkeyValueLayerMockRecorder) MSet(ctx, pairs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockkeyValueLayer)(nil).MSet), ctx, pairs)
+}
+
+// Set mocks base method.
+func (m *MockkeyValueLayer) Set(ctx context.Context, key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockkeyValueLayerMockRecorder) Set(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockkeyValueLayer)(nil).Set), ctx, key, value)
+}
+
+// SetWithTTL mocks base method.
+func (m *MockkeyValueLayer) SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithTTL", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithTTL indicates an expected call of SetWithTTL.
+func (mr *MockkeyValueLayerMockRecorder) SetWithTTL(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithTTL", reflect.TypeOf((*MockkeyValueLayer)(nil).SetWithTTL), ctx, key, value, ttl)
+}
+
+// MockstorageLayer is a mock of storageLayer interface.
+type MockstorageLayer struct {
+	ctrl     *gomock.Controller
+	recorder *MockstorageLayerMockRecorder
+}
+
+// MockstorageLayerMockRecorder is the mock recorder for MockstorageLayer.
+type MockstorageLayerMockRecorder struct {
+	mock *MockstorageLayer
+}
+
+// NewMockstorageLayer creates a new mock instance.
+func NewMockstorageLayer(ctrl *gomock.Controller) *MockstorageLayer {
+	mock := &MockstorageLayer{ctrl: ctrl}
+	mock.recorder = &MockstorageLayerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockstorageLayer) EXPECT() *MockstorageLayerMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockstorageLayer) Append(ctx context.Context, key, value string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, key, value)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockstorageLayerMockRecorder) Append(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockstorageLayer)(nil).Append), ctx, key, value)
+}
+
+// BGetDel mocks base method.
+func (m *MockstorageLayer) BGetDel(ctx context.Context, keys []string, timeout time.Duration) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BGetDel", ctx, keys, timeout)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BGetDel indicates an expected call of BGetDel.
+func (mr *MockstorageLayerMockRecorder) BGetDel(ctx, keys, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BGetDel", reflect.TypeOf((*MockstorageLayer)(nil).BGetDel), ctx, keys, timeout)
+}
+
+// Begin mocks base method.
+func (m *MockstorageLayer) Begin() *storage.Transaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin")
+	ret0, _ := ret[0].(*storage.Transaction)
+	return ret0
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockstorageLayerMockRecorder) Begin() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockstorageLayer)(nil).Begin))
+}
+
+// CompareAndSet mocks base method.
+func (m *MockstorageLayer) CompareAndSet(ctx context.Context, key, expected, value string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSet", ctx, key, expected, value)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareAndSet indicates an expected call of CompareAndSet.
+func (mr *MockstorageLayerMockRecorder) CompareAndSet(ctx, key, expected, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSet", reflect.TypeOf((*MockstorageLayer)(nil).CompareAndSet), ctx, key, expected, value)
+}
+
+// Del mocks base method.
+func (m *MockstorageLayer) Del(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockstorageLayerMockRecorder) Del(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockstorageLayer)(nil).Del), ctx, key)
+}
+
+// Eval mocks base method.
+func (m *MockstorageLayer) Eval(ctx context.Context, source string, keys, args []string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Eval", ctx, source, keys, args)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Eval indicates an expected call of Eval.
+func (mr *MockstorageLayerMockRecorder) Eval(ctx, source, keys, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Eval", reflect.TypeOf((*MockstorageLayer)(nil).Eval), ctx, source, keys, args)
+}
+
+// Exists mocks base method.
+func (m *MockstorageLayer) Exists(ctx context.Context, keys []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, keys)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockstorageLayerMockRecorder) Exists(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockstorageLayer)(nil).Exists), ctx, keys)
+}
+
+// Expire mocks base method.
+func (m *MockstorageLayer) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockstorageLayerMockRecorder) Expire(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockstorageLayer)(nil).Expire), ctx, key, ttl)
+}
+
+// Get mocks base method.
+func (m *MockstorageLayer) Get(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockstorageLayerMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockstorageLayer)(nil).Get), ctx, key)
+}
+
+// GetDel mocks base method.
+func (m *MockstorageLayer) GetDel(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDel", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDel indicates an expected call of GetDel.
+func (mr *MockstorageLayerMockRecorder) GetDel(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDel", reflect.TypeOf((*MockstorageLayer)(nil).GetDel), ctx, key)
+}
+
+// GetRange mocks base method.
+func (m *MockstorageLayer) GetRange(ctx context.Context, key string, start, end int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRange", ctx, key, start, end)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRange indicates an expected call of GetRange.
+func (mr *MockstorageLayerMockRecorder) GetRange(ctx, key, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockstorageLayer)(nil).GetRange), ctx, key, start, end)
+}
+
+// GetSet mocks base method.
+func (m *MockstorageLayer) GetSet(ctx context.Context, key, value string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSet", ctx, key, value)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSet indicates an expected call of GetSet.
+func (mr *MockstorageLayerMockRecorder) GetSet(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSet", reflect.TypeOf((*MockstorageLayer)(nil).GetSet), ctx, key, value)
+}
+
+// HDel mocks base method.
+func (m *MockstorageLayer) HDel(ctx context.Context, key string, fields []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HDel", ctx, key, fields)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockstorageLayerMockRecorder) HDel(ctx, key, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockstorageLayer)(nil).HDel), ctx, key, fields)
+}
+
+// HExists mocks base method.
+func (m *MockstorageLayer) HExists(ctx context.Context, key, field string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HExists", ctx, key, field)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HExists indicates an expected call of HExists.
+func (mr *MockstorageLayerMockRecorder) HExists(ctx, key, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HExists", reflect.TypeOf((*MockstorageLayer)(nil).HExists), ctx, key, field)
+}
+
+// HGet mocks base method.
+func (m *MockstorageLayer) HGet(ctx context.Context, key, field string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", ctx, key, field)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockstorageLayerMockRecorder) HGet(ctx, key, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockstorageLayer)(nil).HGet), ctx, key, field)
+}
+
+// HGetAll mocks base method.
+func (m *MockstorageLayer) HGetAll(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockstorageLayerMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockstorageLayer)(nil).HGetAll), ctx, key)
+}
+
+// HIncrBy mocks base method.
+func (m *MockstorageLayer) HIncrBy(ctx context.Context, key, field string, delta int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HIncrBy", ctx, key, field, delta)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HIncrBy indicates an expected call of HIncrBy.
+func (mr *MockstorageLayerMockRecorder) HIncrBy(ctx, key, field, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HIncrBy", reflect.TypeOf((*MockstorageLayer)(nil).HIncrBy), ctx, key, field, delta)
+}
+
+// HLen mocks base method.
+func (m *MockstorageLayer) HLen(ctx context.Context, key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HLen", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HLen indicates an expected call of HLen.
+func (mr *MockstorageLayerMockRecorder) HLen(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HLen", reflect.TypeOf((*MockstorageLayer)(nil).HLen), ctx, key)
+}
+
+// HSet mocks base method.
+func (m *MockstorageLayer) HSet(ctx context.Context, key string, pairs []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSet", ctx, key, pairs)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockstorageLayerMockRecorder) HSet(ctx, key, pairs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockstorageLayer)(nil).HSet), ctx, key, pairs)
+}
+
+// IncrBy mocks base method.
+func (m *MockstorageLayer) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", ctx, key, delta)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockstorageLayerMockRecorder) IncrBy(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockstorageLayer)(nil).IncrBy), ctx, key, delta)
+}
+
+// IncrByFloat mocks base method.
+func (m *MockstorageLayer) IncrByFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrByFloat", ctx, key, delta)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrByFloat indicates an expected call of IncrByFloat.
+func (mr *MockstorageLayerMockRecorder) IncrByFloat(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrByFloat", reflect.TypeOf((*MockstorageLayer)(nil).IncrByFloat), ctx, key, delta)
+}
+
+// Keys mocks base method.
+func (m *MockstorageLayer) Keys(ctx context.Context, pattern string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", ctx, pattern)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockstorageLayerMockRecorder) Keys(ctx, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockstorageLayer)(nil).Keys), ctx, pattern)
+}
+
+// LIndex mocks base method.
+func (m *MockstorageLayer) LIndex(ctx context.Context, key string, index int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LIndex", ctx, key, index)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LIndex indicates an expected call of LIndex.
+func (mr *MockstorageLayerMockRecorder) LIndex(ctx, key, index interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LIndex", reflect.TypeOf((*MockstorageLayer)(nil).LIndex), ctx, key, index)
+}
+
+// LLen mocks base method.
+func (m *MockstorageLayer) LLen(ctx context.Context, key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LLen", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LLen indicates an expected call of LLen.
+func (mr *MockstorageLayerMockRecorder) LLen(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LLen", reflect.TypeOf((*MockstorageLayer)(nil).LLen), ctx, key)
+}
+
+// LPop mocks base method.
+func (m *MockstorageLayer) LPop(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPop", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LPop indicates an expected call of LPop.
+func (mr *MockstorageLayerMockRecorder) LPop(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPop", reflect.TypeOf((*MockstorageLayer)(nil).LPop), ctx, key)
+}
+
+// LPush mocks base method.
+func (m *MockstorageLayer) LPush(ctx context.Context, key string, values []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPush", ctx, key, values)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockstorageLayerMockRecorder) LPush(ctx, key, values interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockstorageLayer)(nil).LPush), ctx, key, values)
+}
+
+// LRange mocks base method.
+func (m *MockstorageLayer) LRange(ctx context.Context, key string, start, stop int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LRange", ctx, key, start, stop)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LRange indicates an expected call of LRange.
+func (mr *MockstorageLayerMockRecorder) LRange(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LRange", reflect.TypeOf((*MockstorageLayer)(nil).LRange), ctx, key, start, stop)
+}
+
+// LTrim mocks base method.
+func (m *MockstorageLayer) LTrim(ctx context.Context, key string, start, stop int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LTrim", ctx, key, start, stop)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LTrim indicates an expected call of LTrim.
+func (mr *MockstorageLayerMockRecorder) LTrim(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LTrim", reflect.TypeOf((*MockstorageLayer)(nil).LTrim), ctx, key, start, stop)
+}
+
+// MDel mocks base method.
+func (m *MockstorageLayer) MDel(ctx context.Context, keys []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MDel", ctx, keys)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MDel indicates an expected call of MDel.
+func (mr *MockstorageLayerMockRecorder) MDel(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MDel", reflect.TypeOf((*MockstorageLayer)(nil).MDel), ctx, keys)
+}
+
+// MGet mocks base method.
+func (m *MockstorageLayer) MGet(ctx context.Context, keys []string) ([]*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MGet", ctx, keys)
+	ret0, _ := ret[0].([]*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockstorageLayerMockRecorder) MGet(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockstorageLayer)(nil).MGet), ctx, keys)
+}
+
+// MSet mocks base method.
+func (m *MockstorageLayer) MSet(ctx context.Context, pairs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MSet", ctx, pairs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MSet indicates an expected call of MSet.
+func (mr *MockstorageLayerMockRecorder) MSet(ctx, pairs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockstorageLayer)(nil).MSet), ctx, pairs)
+}
+
+// Persist mocks base method.
+func (m *MockstorageLayer) Persist(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persist", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockstorageLayerMockRecorder) Persist(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockstorageLayer)(nil).Persist), ctx, key)
+}
+
+// RPop mocks base method.
+func (m *MockstorageLayer) RPop(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPop", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RPop indicates an expected call of RPop.
+func (mr *MockstorageLayerMockRecorder) RPop(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPop", reflect.TypeOf((*MockstorageLayer)(nil).RPop), ctx, key)
+}
+
+// RPush mocks base method.
+func (m *MockstorageLayer) RPush(ctx context.Context, key string, values []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPush", ctx, key, values)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RPush indicates an expected call of RPush.
+func (mr *MockstorageLayerMockRecorder) RPush(ctx, key, values interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPush", reflect.TypeOf((*MockstorageLayer)(nil).RPush), ctx, key, values)
+}
+
+// SAdd mocks base method.
+func (m *MockstorageLayer) SAdd(ctx context.Context, key string, members []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SAdd", ctx, key, members)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockstorageLayerMockRecorder) SAdd(ctx, key, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockstorageLayer)(nil).SAdd), ctx, key, members)
+}
+
+// SCard mocks base method.
+func (m *MockstorageLayer) SCard(ctx context.Context, key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SCard", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SCard indicates an expected call of SCard.
+func (mr *MockstorageLayerMockRecorder) SCard(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SCard", reflect.TypeOf((*MockstorageLayer)(nil).SCard), ctx, key)
+}
+
+// SDiff mocks base method.
+func (m *MockstorageLayer) SDiff(ctx context.Context, keys []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SDiff", ctx, keys)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SDiff indicates an expected call of SDiff.
+func (mr *MockstorageLayerMockRecorder) SDiff(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SDiff", reflect.TypeOf((*MockstorageLayer)(nil).SDiff), ctx, keys)
+}
+
+// SInter mocks base method.
+func (m *MockstorageLayer) SInter(ctx context.Context, keys []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SInter", ctx, keys)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SInter indicates an expected call of SInter.
+func (mr *MockstorageLayerMockRecorder) SInter(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SInter", reflect.TypeOf((*MockstorageLayer)(nil).SInter), ctx, keys)
+}
+
+// SIsMember mocks base method.
+func (m *MockstorageLayer) SIsMember(ctx context.Context, key, member string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SIsMember", ctx, key, member)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SIsMember indicates an expected call of SIsMember.
+func (mr *MockstorageLayerMockRecorder) SIsMember(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SIsMember", reflect.TypeOf((*MockstorageLayer)(nil).SIsMember), ctx, key, member)
+}
+
+// SMembers mocks base method.
+func (m *MockstorageLayer) SMembers(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockstorageLayerMockRecorder) SMembers(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockstorageLayer)(nil).SMembers), ctx, key)
+}
+
+// SRem mocks base method.
+func (m *MockstorageLayer) SRem(ctx context.Context, key string, members []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SRem", ctx, key, members)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockstorageLayerMockRecorder) SRem(ctx, key, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockstorageLayer)(nil).SRem), ctx, key, members)
+}
+
+// SUnion mocks base method.
+func (m *MockstorageLayer) SUnion(ctx context.Context, keys []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SUnion", ctx, keys)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SUnion indicates an expected call of SUnion.
+func (mr *MockstorageLayerMockRecorder) SUnion(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SUnion", reflect.TypeOf((*MockstorageLayer)(nil).SUnion), ctx, keys)
+}
+
+// Scan mocks base method.
+func (m *MockstorageLayer) Scan(ctx context.Context, cursor, pattern string, count int) ([]string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, cursor, pattern, count)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockstorageLayerMockRecorder) Scan(ctx, cursor, pattern, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockstorageLayer)(nil).Scan), ctx, cursor, pattern, count)
+}
+
+// Set mocks base method.
+func (m *MockstorageLayer) Set(ctx context.Context, key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockstorageLayerMockRecorder) Set(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockstorageLayer)(nil).Set), ctx, key, value)
+}
+
+// SetIf mocks base method.
+func (m *MockstorageLayer) SetIf(ctx context.Context, key, value string, ttl time.Duration, exists bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIf", ctx, key, value, ttl, exists)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetIf indicates an expected call of SetIf.
+func (mr *MockstorageLayerMockRecorder) SetIf(ctx, key, value, ttl, exists interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIf", reflect.TypeOf((*MockstorageLayer)(nil).SetIf), ctx, key, value, ttl, exists)
+}
+
+// SetRange mocks base method.
+func (m *MockstorageLayer) SetRange(ctx context.Context, key string, offset int, value string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRange", ctx, key, offset, value)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRange indicates an expected call of SetRange.
+func (mr *MockstorageLayerMockRecorder) SetRange(ctx, key, offset, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRange", reflect.TypeOf((*MockstorageLayer)(nil).SetRange), ctx, key, offset, value)
+}
+
+// SetWithTTL mocks base method.
+func (m *MockstorageLayer) SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithTTL", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithTTL indicates an expected call of SetWithTTL.
+func (mr *MockstorageLayerMockRecorder) SetWithTTL(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithTTL", reflect.TypeOf((*MockstorageLayer)(nil).SetWithTTL), ctx, key, value, ttl)
+}
+
+// StrLen mocks base method.
+func (m *MockstorageLayer) StrLen(ctx context.Context, key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StrLen", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StrLen indicates an expected call of StrLen.
+func (mr *MockstorageLayerMockRecorder) StrLen(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StrLen", reflect.TypeOf((*MockstorageLayer)(nil).StrLen), ctx, key)
+}
+
+// TTL mocks base method.
+func (m *MockstorageLayer) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *MockstorageLayerMockRecorder) TTL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockstorageLayer)(nil).TTL), ctx, key)
+}
+
+// Version mocks base method.
+func (m *MockstorageLayer) Version(ctx context.Context, key string) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", ctx, key)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockstorageLayerMockRecorder) Version(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockstorageLayer)(nil).Version), ctx, key)
+}
+
+// ZAdd mocks base method.
+func (m *MockstorageLayer) ZAdd(ctx context.Context, key string, pairs []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZAdd", ctx, key, pairs)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockstorageLayerMockRecorder) ZAdd(ctx, key, pairs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockstorageLayer)(nil).ZAdd), ctx, key, pairs)
+}
+
+// ZCard mocks base method.
+func (m *MockstorageLayer) ZCard(ctx context.Context, key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZCard", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZCard indicates an expected call of ZCard.
+func (mr *MockstorageLayerMockRecorder) ZCard(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZCard", reflect.TypeOf((*MockstorageLayer)(nil).ZCard), ctx, key)
+}
+
+// ZRange mocks base method.
+func (m *MockstorageLayer) ZRange(ctx context.Context, key string, start, stop int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRange", ctx, key, start, stop)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRange indicates an expected call of ZRange.
+func (mr *MockstorageLayerMockRecorder) ZRange(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRange", reflect.TypeOf((*MockstorageLayer)(nil).ZRange), ctx, key, start, stop)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockstorageLayer) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, min, max)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockstorageLayerMockRecorder) ZRangeByScore(ctx, key, min, max interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockstorageLayer)(nil).ZRangeByScore), ctx, key, min, max)
+}
+
+// ZRank mocks base method.
+func (m *MockstorageLayer) ZRank(ctx context.Context, key, member string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRank", ctx, key, member)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRank indicates an expected call of ZRank.
+func (mr *MockstorageLayerMockRecorder) ZRank(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRank", reflect.TypeOf((*MockstorageLayer)(nil).ZRank), ctx, key, member)
+}
+
+// ZRem mocks base method.
+func (m *MockstorageLayer) ZRem(ctx context.Context, key string, members []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRem", ctx, key, members)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockstorageLayerMockRecorder) ZRem(ctx, key, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockstorageLayer)(nil).ZRem), ctx, key, members)
+}
+
+// ZScore mocks base method.
+func (m *MockstorageLayer) ZScore(ctx context.Context, key, member string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZScore", ctx, key, member)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZScore indicates an expected call of ZScore.
+func (mr *MockstorageLayerMockRecorder) ZScore(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScore", reflect.TypeOf((*MockstorageLayer)(nil).ZScore), ctx, key, member)
+}
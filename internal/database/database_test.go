@@ -1 +1,374 @@
 package database
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"github.com/passsquale/key-value-storage/internal/database/storage/engine/in_memory"
+	"github.com/passsquale/key-value-storage/internal/network"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// mockgen -source=database.go -destination=database_mock.go -package=database
+
+func TestNewDatabase(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	computeLayer := NewMockcomputeLayer(ctrl)
+	storageLayer := NewMockstorageLayer(ctrl)
+
+	database, err := NewDatabase(nil, nil, nil)
+	require.Error(t, err, "compute is invalid")
+	require.Nil(t, database)
+
+	database, err = NewDatabase(computeLayer, nil, nil)
+	require.Error(t, err, "storage is invalid")
+	require.Nil(t, database)
+
+	database, err = NewDatabase(computeLayer, storageLayer, nil)
+	require.Error(t, err, "logger is invalid")
+	require.Nil(t, database)
+
+	database, err = NewDatabase(computeLayer, storageLayer, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, database)
+}
+
+func TestHandleQueryWithComputeError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	computeLayer := NewMockcomputeLayer(ctrl)
+	computeLayer.EXPECT().
+		HandleQuery(gomock.Any(), "TRUNCATE").
+		Return(compute.Query{}, errors.New("invalid command"))
+	storageLayer := NewMockstorageLayer(ctrl)
+
+	database, err := NewDatabase(computeLayer, storageLayer, zap.NewNop())
+	require.NoError(t, err)
+
+	response := database.HandleQuery(context.Background(), "TRUNCATE")
+	require.Equal(t, "[error] invalid command", response)
+}
+
+func TestHandleGetQuery(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	computeLayer := NewMockcomputeLayer(ctrl)
+	computeLayer.EXPECT().
+		HandleQuery(gomock.Any(), "GET key_1").
+		Return(compute.NewQuery(compute.GetCommandID, []string{"key_1"}), nil)
+	computeLayer.EXPECT().
+		HandleQuery(gomock.Any(), "GET key_2").
+		Return(compute.NewQuery(compute.GetCommandID, []string{"key_2"}), nil)
+	computeLayer.EXPECT().
+		HandleQuery(gomock.Any(), "GET key_3").
+		Return(compute.NewQuery(compute.GetCommandID, []string{"key_3"}), nil)
+
+	storageLayer := NewMockstorageLayer(ctrl)
+	storageLayer.EXPECT().
+		Get(gomock.Any(), "key_1").
+		Return("value_1", nil)
+	storageLayer.EXPECT().
+		Get(gomock.Any(), "key_2").
+		Return("", nil)
+	storageLayer.EXPECT().
+		Get(gomock.Any(), "key_3").
+		Return("", storage.ErrNotFound)
+
+	database, err := NewDatabase(computeLayer, storageLayer, zap.NewNop())
+	require.NoError(t, err)
+
+	require.Equal(t, "[ok] value_1", database.HandleQuery(context.Background(), "GET key_1"))
+	require.Equal(t, "[ok] ", database.HandleQuery(context.Background(), "GET key_2"))
+	require.Equal(t, "[not_found]", database.HandleQuery(context.Background(), "GET key_3"))
+}
+
+func TestHandleMGetQuery(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	computeLayer := NewMockcomputeLayer(ctrl)
+	computeLayer.EXPECT().
+		HandleQuery(gomock.Any(), "MGET key_1 key_2").
+		Return(compute.NewQuery(compute.MGetCommandID, []string{"key_1", "key_2"}), nil)
+
+	value := "value 1"
+	storageLayer := NewMockstorageLayer(ctrl)
+	storageLayer.EXPECT().
+		MGet(gomock.Any(), []string{"key_1", "key_2"}).
+		Return([]*string{&value, nil}, nil)
+
+	database, err := NewDatabase(computeLayer, storageLayer, zap.NewNop())
+	require.NoError(t, err)
+
+	response := database.HandleQuery(context.Background(), "MGET key_1 key_2")
+	require.Equal(t, `[ok] "value 1" (nil)`, response)
+}
+
+func TestHandleExistsQuery(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	computeLayer := NewMockcomputeLayer(ctrl)
+	computeLayer.EXPECT().
+		HandleQuery(gomock.Any(), "EXISTS key_1 key_2").
+		Return(compute.NewQuery(compute.ExistsCommandID, []string{"key_1", "key_2"}), nil)
+
+	storageLayer := NewMockstorageLayer(ctrl)
+	storageLayer.EXPECT().
+		Exists(gomock.Any(), []string{"key_1", "key_2"}).
+		Return(1, nil)
+
+	database, err := NewDatabase(computeLayer, storageLayer, zap.NewNop())
+	require.NoError(t, err)
+
+	response := database.HandleQuery(context.Background(), "EXISTS key_1 key_2")
+	require.Equal(t, "[ok] 1", response)
+}
+
+func newTestDatabase(t *testing.T) *Database {
+	logger := zap.NewNop()
+	parser, err := compute.NewParser(logger)
+	require.NoError(t, err)
+	analyzer, err := compute.NewAnalyzer(logger)
+	require.NoError(t, err)
+	computeLayer, err := compute.NewCompute(parser, analyzer, logger)
+	require.NoError(t, err)
+
+	engine, err := in_memory.NewEngine(in_memory.HashTableBuilder, 1, logger)
+	require.NoError(t, err)
+	storageLayer, err := storage.NewStorage(engine, nil, nil, logger)
+	require.NoError(t, err)
+
+	database, err := NewDatabase(computeLayer, storageLayer, logger)
+	require.NoError(t, err)
+	return database
+}
+
+func TestHandleTransactionQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+
+	withoutSession := context.Background()
+	require.Equal(t, "[error] transactions require client session", database.HandleQuery(withoutSession, "BEGIN"))
+
+	first := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+	second := context.WithValue(context.Background(), "session", network.NewSession(2, 1))
+
+	require.Equal(t, "[error] transaction is not started", database.HandleQuery(first, "COMMIT"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "BEGIN"))
+	require.Equal(t, "[error] transaction is already started", database.HandleQuery(first, "BEGIN"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "SET key_1 value_1"))
+	require.Equal(t, "[error] command is not allowed in transaction", database.HandleQuery(first, "TTL key_1"))
+	require.Equal(t, "[ok] value_1", database.HandleQuery(first, "GET key_1"))
+	require.Equal(t, "[not_found]", database.HandleQuery(second, "GET key_1"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(first, "COMMIT"))
+	require.Equal(t, "[ok] value_1", database.HandleQuery(second, "GET key_1"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(second, "BEGIN"))
+	require.Equal(t, "[ok]", database.HandleQuery(second, "DEL key_1"))
+	require.Equal(t, "[not_found]", database.HandleQuery(second, "GET key_1"))
+	require.Equal(t, "[ok]", database.HandleQuery(second, "ROLLBACK"))
+	require.Equal(t, "[ok] value_1", database.HandleQuery(second, "GET key_1"))
+}
+
+func TestHandleWatchQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+
+	first := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+	second := context.WithValue(context.Background(), "session", network.NewSession(2, 1))
+
+	require.Equal(t, "[ok]", database.HandleQuery(first, "SET key_1 value_1"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "WATCH key_1 key_2"))
+	require.Equal(t, "[ok]", database.HandleQuery(second, "SET key_2 value_2"))
+	require.Equal(t, "[error] watched key has been changed", database.HandleQuery(first, "SET key_1 value_3"))
+	require.Equal(t, "[ok] value_1", database.HandleQuery(first, "GET key_1"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(first, "SET key_1 value_3"))
+	require.Equal(t, "[ok] value_3", database.HandleQuery(first, "GET key_1"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(first, "WATCH key_1"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "BEGIN"))
+	require.Equal(t, "[error] command is not allowed in transaction", database.HandleQuery(first, "WATCH key_2"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "DEL key_1"))
+	require.Equal(t, "[ok]", database.HandleQuery(second, "SET key_1 value_4"))
+	require.Equal(t, "[error] watched key has been changed", database.HandleQuery(first, "COMMIT"))
+	require.Equal(t, "[ok] value_4", database.HandleQuery(first, "GET key_1"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(first, "WATCH key_1"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "UNWATCH"))
+	require.Equal(t, "[ok]", database.HandleQuery(second, "SET key_1 value_5"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "DEL key_1"))
+	require.Equal(t, "[not_found]", database.HandleQuery(first, "GET key_1"))
+
+	// the missing key created and removed again is changed
+	require.Equal(t, "[ok]", database.HandleQuery(first, "WATCH key_1"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "BEGIN"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "SET key_1 value_6"))
+	require.Equal(t, "[ok]", database.HandleQuery(second, "SET key_1 value_7"))
+	require.Equal(t, "[ok]", database.HandleQuery(second, "DEL key_1"))
+	require.Equal(t, "[error] watched key has been changed", database.HandleQuery(first, "COMMIT"))
+	require.Equal(t, "[not_found]", database.HandleQuery(first, "GET key_1"))
+
+	// writes, which can't be checked against the watched keys, are rejected
+	require.Equal(t, "[ok]", database.HandleQuery(first, "SET key_1 1"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "WATCH key_1"))
+	for _, query := range []string{"INCR key_1", "APPEND key_1 2", "SETRANGE key_1 0 2", "SET key_1 2 XX", "HSET key_2 field value", "EVAL 'return 1' 0"} {
+		require.Equal(t, "[error] command is not allowed while keys are watched", database.HandleQuery(first, query), query)
+	}
+
+	require.Equal(t, "[ok] 1", database.HandleQuery(first, "GET key_1"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(first, "PUBLISH channel message"))
+	require.Equal(t, "[ok]", database.HandleQuery(first, "UNWATCH"))
+	require.Equal(t, "[ok] 2", database.HandleQuery(first, "INCR key_1"))
+}
+
+func TestHandleCASQuery(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "CAS key_1 value_1 value_2"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET key_1 value_1"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "CAS key_1 value_2 value_3"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "CAS key_1 value_1 value_3"))
+	require.Equal(t, "[ok] value_3", database.HandleQuery(ctx, "GET key_1"))
+}
+
+func TestHandleIncrQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "INCR key_1"))
+	require.Equal(t, "[ok] 11", database.HandleQuery(ctx, "INCRBY key_1 10"))
+	require.Equal(t, "[ok] 10", database.HandleQuery(ctx, "DECR key_1"))
+	require.Equal(t, "[ok] -5", database.HandleQuery(ctx, "DECRBY key_1 15"))
+	require.Equal(t, "[ok] -4.5", database.HandleQuery(ctx, "INCRBYFLOAT key_1 0.5"))
+	require.Equal(t, "[ok] -4.5", database.HandleQuery(ctx, "GET key_1"))
+	require.Equal(t, "[error] value is not an integer or out of range", database.HandleQuery(ctx, "INCR key_1"))
+	require.Equal(t, "[error] value is not an integer or out of range", database.HandleQuery(ctx, "INCRBY key_2 ten"))
+	require.Equal(t, "[error] value is not a valid float", database.HandleQuery(ctx, "INCRBYFLOAT key_2 NaN"))
+}
+
+func TestHandleStringQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 5", database.HandleQuery(ctx, "APPEND key_1 hello"))
+	require.Equal(t, "[ok] 11", database.HandleQuery(ctx, `APPEND key_1 " world"`))
+	require.Equal(t, "[ok] 11", database.HandleQuery(ctx, "STRLEN key_1"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "STRLEN key_2"))
+	require.Equal(t, "[ok] world", database.HandleQuery(ctx, "GETRANGE key_1 -5 -1"))
+	require.Equal(t, "[ok] 11", database.HandleQuery(ctx, "SETRANGE key_1 6 redis"))
+	require.Equal(t, "[error] offset is out of range", database.HandleQuery(ctx, "SETRANGE key_1 -1 redis"))
+	require.Equal(t, "[error] offset is out of range", database.HandleQuery(ctx, "SETRANGE key_1 536870912 redis"))
+	require.Equal(t, "[error] offset is out of range", database.HandleQuery(ctx, "SETRANGE key_1 9223372036854775807 x"))
+	require.Equal(t, "[ok] hello redis", database.HandleQuery(ctx, "GETSET key_1 value_1"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "GETSET key_2 value_2"))
+	require.Equal(t, "[ok] value_2", database.HandleQuery(ctx, "GETDEL key_2"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "GETDEL key_2"))
+	require.Equal(t, "[ok] value_1", database.HandleQuery(ctx, "GET key_1"))
+}
+
+func TestHandleConditionalSetQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "SET key_1 value_1 XX"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "GET key_1"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "SET key_1 value_1 NX EX 100"))
+	require.Equal(t, "[ok] 100", database.HandleQuery(ctx, "TTL key_1"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "SETNX key_1 value_2"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "SET key_1 value_2 XX"))
+	require.Equal(t, "[ok] value_2", database.HandleQuery(ctx, "GET key_1"))
+	require.Equal(t, "[ok] -1", database.HandleQuery(ctx, "TTL key_1"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "SETNX key_2 value_2"))
+
+	session := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+	require.Equal(t, "[ok]", database.HandleQuery(session, "BEGIN"))
+	require.Equal(t, "[error] command is not allowed in transaction", database.HandleQuery(session, "SET key_3 value_3 NX"))
+	require.Equal(t, "[ok]", database.HandleQuery(session, "ROLLBACK"))
+}
+
+func TestHandleExpirationOverflow(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET key_1 value_1"))
+	require.Equal(t, "[error] invalid expiration time", database.HandleQuery(ctx, "EXPIRE key_1 99999999999999"))
+	require.Equal(t, "[error] invalid expiration time", database.HandleQuery(ctx, "EXPIRE key_1 -99999999999999"))
+	require.Equal(t, "[error] invalid expiration time", database.HandleQuery(ctx, "SET key_1 value_2 EX 99999999999999"))
+	require.Equal(t, "[error] invalid expiration time", database.HandleQuery(ctx, "SET key_1 value_2 PX 9223372036854775"))
+	require.Equal(t, "[ok] -1", database.HandleQuery(ctx, "TTL key_1"))
+	require.Equal(t, "[ok] value_1", database.HandleQuery(ctx, "GET key_1"))
+
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "EXPIRE key_1 9223372036"))
+	require.Equal(t, "[ok] 9223372036", database.HandleQuery(ctx, "TTL key_1"))
+}
+
+func TestHandleScanQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "KEYS *"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "MSET user:1 a user:2 b session:1 c"))
+	require.Equal(t, `[ok] "user:1" "user:2"`, database.HandleQuery(ctx, "KEYS user:*"))
+	require.Equal(t, `[ok] 0 "session:1"`, database.HandleQuery(ctx, "SCAN 0 MATCH session:*"))
+	require.Equal(t, `[ok] 0:73657373696f6e3a31 "session:1"`, database.HandleQuery(ctx, "SCAN 0 COUNT 1"))
+	require.Equal(t, `[ok] 0 "user:1" "user:2"`, database.HandleQuery(ctx, "SCAN 0:73657373696f6e3a31"))
+	require.Equal(t, "[error] count must be positive integer", database.HandleQuery(ctx, "SCAN 0 COUNT 0"))
+	require.Equal(t, "[error] invalid cursor", database.HandleQuery(ctx, "SCAN 1"))
+}
+
+func TestHandleBGetDelQuery(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET job_2 payload"))
+	require.Equal(t, `[ok] "job_2" "payload"`, database.HandleQuery(ctx, "BGETDEL job_1 job_2 1"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "BGETDEL job_1 job_2 0.01"))
+	require.Equal(t, "[error] timeout is not a float or out of range", database.HandleQuery(ctx, "BGETDEL job_1 -1"))
+	require.Equal(t, "[error] timeout is not a float or out of range", database.HandleQuery(ctx, "BGETDEL job_1 1e300"))
+
+	responses := make(chan string, 1)
+	go func() {
+		responses <- database.HandleQuery(ctx, "BGETDEL job_1 0")
+	}()
+
+	require.Never(t, func() bool { return len(responses) != 0 }, 50*time.Millisecond, 10*time.Millisecond)
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET job_1 payload"))
+	require.Equal(t, `[ok] "job_1" "payload"`, <-responses)
+
+	canceled, cancel := context.WithCancel(ctx)
+	go func() {
+		responses <- database.HandleQuery(canceled, "BGETDEL job_1 0")
+	}()
+
+	cancel()
+	require.Equal(t, "[error] context canceled", <-responses)
+}
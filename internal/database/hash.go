@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"strconv"
+)
+
+func (d *Database) handleHSetQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	added, err := d.storageLayer.HSet(ctx, arguments[0], arguments[1:])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", added)
+}
+
+func (d *Database) handleHGetQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	value, err := d.storageLayer.HGet(ctx, arguments[0], arguments[1])
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", value)
+}
+
+func (d *Database) handleHDelQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	deleted, err := d.storageLayer.HDel(ctx, arguments[0], arguments[1:])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", deleted)
+}
+
+func (d *Database) handleHGetAllQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	pairs, err := d.storageLayer.HGetAll(ctx, arguments[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if len(pairs) == 0 {
+		return "[ok]"
+	}
+
+	return fmt.Sprintf("[ok] %s", quoteValues(pairs))
+}
+
+func (d *Database) handleHLenQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	length, err := d.storageLayer.HLen(ctx, arguments[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+func (d *Database) handleHExistsQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	found, err := d.storageLayer.HExists(ctx, arguments[0], arguments[1])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", boolToInt(found))
+}
+
+func (d *Database) handleHIncrByQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	delta, err := strconv.ParseInt(arguments[2], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", errInvalidInteger.Error())
+	}
+
+	value, err := d.storageLayer.HIncrBy(ctx, arguments[0], arguments[1], delta)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", value)
+}
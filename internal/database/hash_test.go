@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHandleHashQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 2", database.HandleQuery(ctx, `HSET user:1 name "John Doe" age 30`))
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "HSET user:1 age 31"))
+	require.Equal(t, "[ok] John Doe", database.HandleQuery(ctx, "HGET user:1 name"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "HGET user:1 email"))
+	require.Equal(t, "[ok] 2", database.HandleQuery(ctx, "HLEN user:1"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "HEXISTS user:1 age"))
+	require.Equal(t, "[ok] 32", database.HandleQuery(ctx, "HINCRBY user:1 age 1"))
+	require.Equal(t, `[ok] "age" "32" "name" "John Doe"`, database.HandleQuery(ctx, "HGETALL user:1"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "HDEL user:1 age email"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "EXISTS user:1"))
+
+	wrongType := "[error] WRONGTYPE operation against a key holding the wrong kind of value"
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "GET user:1"))
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "INCR user:1"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET key_1 value_1"))
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "HGET key_1 field"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "DEL user:1"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "HGETALL user:1"))
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// httpResponse mirrors statuses of the text protocol,
+// value is set only if the response has been with value
+type httpResponse struct {
+	Status string  `json:"status"`
+	Value  *string `json:"value,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+type httpQueryRequest struct {
+	Query string `json:"query"`
+}
+
+// HTTPHandler returns JSON gateway of the database: GET, PUT and DELETE
+// of /v1/keys/{key} work with string values, the value of PUT is the
+// request body, POST /v1/query handles queries like HandleQuery,
+// bodies larger than maxBodySize are rejected; if ACL is enabled,
+// requests are authenticated by basic authentication
+func (d *Database) HTTPHandler(maxBodySize int) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		query := compute.NewQuery(compute.GetCommandID, []string{r.PathValue("key")})
+		d.writeHTTPResponse(w, d.handleHTTPQuery(r.Context(), query))
+	})
+
+	mux.HandleFunc("PUT /v1/keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		value, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(maxBodySize)))
+		if err != nil {
+			d.writeHTTPError(w, err)
+			return
+		}
+
+		query := compute.NewQuery(compute.SetCommandID, []string{r.PathValue("key"), string(value)})
+		d.writeHTTPResponse(w, d.handleHTTPQuery(r.Context(), query))
+	})
+
+	mux.HandleFunc("DELETE /v1/keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		query := compute.NewQuery(compute.DelCommandID, []string{r.PathValue("key")})
+		d.writeHTTPResponse(w, d.handleHTTPQuery(r.Context(), query))
+	})
+
+	mux.HandleFunc("POST /v1/query", func(w http.ResponseWriter, r *http.Request) {
+		var request httpQueryRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, int64(maxBodySize)))
+		if err := decoder.Decode(&request); err != nil {
+			d.writeHTTPError(w, err)
+			return
+		}
+
+		ctx, query, err := d.parseQuery(r.Context(), request.Query)
+		if err != nil {
+			d.writeJSON(w, http.StatusBadRequest, httpResponse{Status: "error", Error: err.Error()})
+			return
+		}
+
+		d.writeHTTPResponse(w, d.handleQuery(ctx, query))
+	})
+
+	return d.authenticateHTTP(mux)
+}
+
+// authenticateHTTP passes the user authenticated by the
+// request to the handler in the context under "user" key
+func (d *Database) authenticateHTTP(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d.acl == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		err := errAuthRequired
+		if name, password, ok := r.BasicAuth(); ok {
+			var user *acl.User
+			if user, err = d.acl.Authenticate(name, password); err == nil {
+				handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "user", user.Name())))
+				return
+			}
+		}
+
+		w.Header().Set("WWW-Authenticate", `Basic realm="kv-storage"`)
+		d.writeJSON(w, http.StatusUnauthorized, httpResponse{Status: "error", Error: err.Error()})
+	})
+}
+
+func (d *Database) handleHTTPQuery(ctx context.Context, query compute.Query) string {
+	txID := d.idGenerator.Generate()
+	ctx = context.WithValue(ctx, "tx", txID)
+
+	d.logger.Debug(
+		"handling http query",
+		zap.Int64("tx", txID),
+		zap.Int("command", query.CommandID()),
+		zap.Strings("arguments", query.Arguments()),
+	)
+
+	return d.handleQuery(ctx, query)
+}
+
+// writeHTTPResponse converts the text response, errors of queries
+// are reported as unprocessable by the request, except denied ones
+func (d *Database) writeHTTPResponse(w http.ResponseWriter, response string) {
+	switch {
+	case response == "[ok]":
+		d.writeJSON(w, http.StatusOK, httpResponse{Status: "ok"})
+	case response == "[not_found]":
+		d.writeJSON(w, http.StatusNotFound, httpResponse{Status: "not_found"})
+	case strings.HasPrefix(response, "[ok] "):
+		value := strings.TrimPrefix(response, "[ok] ")
+		d.writeJSON(w, http.StatusOK, httpResponse{Status: "ok", Value: &value})
+	case strings.HasPrefix(response, "[error] "+errPermissionDenied.Error()):
+		message := strings.TrimPrefix(response, "[error] ")
+		d.writeJSON(w, http.StatusForbidden, httpResponse{Status: "error", Error: message})
+	case strings.HasPrefix(response, "[error] "):
+		message := strings.TrimPrefix(response, "[error] ")
+		d.writeJSON(w, http.StatusUnprocessableEntity, httpResponse{Status: "error", Error: message})
+	default:
+		d.writeJSON(w, http.StatusOK, httpResponse{Status: "ok", Value: &response})
+	}
+}
+
+func (d *Database) writeHTTPError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		message := fmt.Sprintf("body is larger than %d bytes", maxBytesErr.Limit)
+		d.writeJSON(w, http.StatusRequestEntityTooLarge, httpResponse{Status: "error", Error: message})
+		return
+	}
+
+	d.writeJSON(w, http.StatusBadRequest, httpResponse{Status: "error", Error: err.Error()})
+}
+
+func (d *Database) writeJSON(w http.ResponseWriter, statusCode int, response httpResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		d.logger.Warn("failed to write http response", zap.Error(err))
+	}
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestDatabase(t).HTTPHandler(32)
+
+	serve := func(method, target, body string) (int, string) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+		require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		return recorder.Code, strings.TrimSpace(recorder.Body.String())
+	}
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+
+		code     int
+		response string
+	}{
+		{http.MethodGet, "/v1/keys/key", "", http.StatusNotFound, `{"status":"not_found"}`},
+		{http.MethodPut, "/v1/keys/key", "value with spaces", http.StatusOK, `{"status":"ok"}`},
+		{http.MethodGet, "/v1/keys/key", "", http.StatusOK, `{"status":"ok","value":"value with spaces"}`},
+		{http.MethodPut, "/v1/keys/key", "value over the limit of the request body", http.StatusRequestEntityTooLarge, `{"status":"error","error":"body is larger than 32 bytes"}`},
+		{http.MethodPost, "/v1/query", `{"query":"HSET hash a 1"}`, http.StatusOK, `{"status":"ok","value":"1"}`},
+		{http.MethodGet, "/v1/keys/hash", "", http.StatusUnprocessableEntity, `{"status":"error","error":"WRONGTYPE operation against a key holding the wrong kind of value"}`},
+		{http.MethodPost, "/v1/query", `{"query":"GET"}`, http.StatusBadRequest, `{"status":"error","error":"invalid arguments"}`},
+		{http.MethodPost, "/v1/query", `{"query":`, http.StatusBadRequest, `{"status":"error","error":"unexpected EOF"}`},
+		{http.MethodDelete, "/v1/keys/key", "", http.StatusOK, `{"status":"ok"}`},
+		{http.MethodGet, "/v1/keys/key", "", http.StatusNotFound, `{"status":"not_found"}`},
+	}
+
+	for _, test := range tests {
+		code, response := serve(test.method, test.target, test.body)
+		require.Equal(t, test.code, code, "%s %s", test.method, test.target)
+		require.Equal(t, test.response, response, "%s %s", test.method, test.target)
+	}
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"strconv"
+)
+
+func (d *Database) handlePushQuery(ctx context.Context, query compute.Query) string {
+	push := d.storageLayer.RPush
+	if query.CommandID() == compute.LPushCommandID {
+		push = d.storageLayer.LPush
+	}
+
+	arguments := query.Arguments()
+	length, err := push(ctx, arguments[0], arguments[1:])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+func (d *Database) handlePopQuery(ctx context.Context, query compute.Query) string {
+	pop := d.storageLayer.RPop
+	if query.CommandID() == compute.LPopCommandID {
+		pop = d.storageLayer.LPop
+	}
+
+	value, err := pop(ctx, query.Arguments()[0])
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", value)
+}
+
+func (d *Database) handleLLenQuery(ctx context.Context, query compute.Query) string {
+	length, err := d.storageLayer.LLen(ctx, query.Arguments()[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+func (d *Database) handleLRangeQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	start, stop, err := parseListRange(arguments[1], arguments[2])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	values, err := d.storageLayer.LRange(ctx, arguments[0], start, stop)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if len(values) == 0 {
+		return "[ok]"
+	}
+
+	return fmt.Sprintf("[ok] %s", quoteValues(values))
+}
+
+func (d *Database) handleLIndexQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	index, err := strconv.Atoi(arguments[1])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", errInvalidInteger.Error())
+	}
+
+	value, err := d.storageLayer.LIndex(ctx, arguments[0], index)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", value)
+}
+
+func (d *Database) handleLTrimQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	start, stop, err := parseListRange(arguments[1], arguments[2])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if err := d.storageLayer.LTrim(ctx, arguments[0], start, stop); err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return "[ok]"
+}
+
+func parseListRange(startArgument, stopArgument string) (int, int, error) {
+	start, err := strconv.Atoi(startArgument)
+	if err != nil {
+		return 0, 0, errInvalidInteger
+	}
+
+	stop, err := strconv.Atoi(stopArgument)
+	if err != nil {
+		return 0, 0, errInvalidInteger
+	}
+
+	return start, stop, nil
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHandleListQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 2", database.HandleQuery(ctx, "RPUSH jobs job_2 job_3"))
+	require.Equal(t, "[ok] 3", database.HandleQuery(ctx, "LPUSH jobs job_1"))
+	require.Equal(t, `[ok] "job_1" "job_2" "job_3"`, database.HandleQuery(ctx, "LRANGE jobs 0 -1"))
+	require.Equal(t, "[ok] job_3", database.HandleQuery(ctx, "LINDEX jobs -1"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "LINDEX jobs 3"))
+	require.Equal(t, "[ok] job_1", database.HandleQuery(ctx, "LPOP jobs"))
+	require.Equal(t, "[ok] job_3", database.HandleQuery(ctx, "RPOP jobs"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "LLEN jobs"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "LTRIM jobs 1 -1"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "LLEN jobs"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "RPOP jobs"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "LRANGE jobs 0 -1"))
+
+	require.Equal(t, "[error] value is not an integer or out of range", database.HandleQuery(ctx, "LRANGE jobs 0 end"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET key_1 value_1"))
+	wrongType := "[error] WRONGTYPE operation against a key holding the wrong kind of value"
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "LPUSH key_1 value"))
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "LLEN key_1"))
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// HandlePipeline handles newline separated queries of the request in order
+// and returns their responses, each of them is framed by the line with its
+// length in bytes and followed by newline, so values with newlines can't be
+// confused with other responses, the request is a complete frame, so the last
+// query doesn't need newline, newlines inside quoted values don't separate
+// queries, requests of a single query are handled like by HandleQuery for
+// compatibility
+func (d *Database) HandlePipeline(ctx context.Context, request string) string {
+	queries := splitQueries(request)
+	if len(queries) == 1 {
+		return d.HandleQuery(ctx, request)
+	}
+
+	var responses strings.Builder
+	for _, query := range queries {
+		query = strings.TrimSuffix(query, "\r")
+		if strings.TrimSpace(query) == "" {
+			continue
+		}
+
+		response := d.HandleQuery(ctx, query)
+		responses.WriteString("$" + strconv.Itoa(len(response)) + "\n")
+		responses.WriteString(response)
+		responses.WriteByte('\n')
+	}
+
+	return responses.String()
+}
+
+// splitQueries splits the request by newlines outside of quoted values,
+// quotes are recognized like by the parser: they open only at the
+// beginning of an argument and backslash escapes the next symbol
+func splitQueries(request string) []string {
+	var queries []string
+	var quote byte
+
+	start := 0
+	argumentStart := true
+	for idx := 0; idx < len(request); idx++ {
+		symbol := request[idx]
+		switch {
+		case quote != 0 && symbol == '\\':
+			idx++
+		case quote != 0:
+			if symbol == quote {
+				quote = 0
+			}
+		case symbol == '\n':
+			queries = append(queries, request[start:idx])
+			start = idx + 1
+		case argumentStart && (symbol == '"' || symbol == '\''):
+			quote = symbol
+		}
+
+		argumentStart = quote == 0 && (symbol == '\t' || symbol == '\n' || symbol == ' ')
+	}
+
+	return append(queries, request[start:])
+}
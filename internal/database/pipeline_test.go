@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/network"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHandlePipeline(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok]", database.HandlePipeline(ctx, "SET key_1 value_1"))
+	require.Equal(t, "$4\n[ok]\n$4\n[ok]\n$4\n[ok]\n$12\n[ok] value_2\n", database.HandlePipeline(ctx, "SET a 1\r\nSET b 2\n\nSET key_2 value_2\nGET key_2\n"))
+	require.Equal(t, "$6\n[ok] 1\n$6\n[ok] 2\n", database.HandlePipeline(ctx, "GET a\nGET b"))
+	require.Equal(t, "$12\n[ok] value_1\n$11\n[not_found]\n", database.HandlePipeline(ctx, "GET key_1\nGET key_3\n"))
+}
+
+func TestHandlePipelineLastQuery(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	session := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+
+	require.Equal(t, "$4\n[ok]\n$4\n[ok]\n", database.HandlePipeline(session, "SET key_1 value_1\nSET key_2 value_2"))
+	require.Equal(t, "$12\n[ok] value_1\n$12\n[ok] value_2\n", database.HandlePipeline(session, "GET key_1\nGET key_2"))
+
+	require.Equal(t, "$4\n[ok]\n$4\n[ok]\n", database.HandlePipeline(session, "BEGIN\nSET key_3 value_3\n"))
+	require.Equal(t, "$4\n[ok]\n$12\n[ok] value_3\n", database.HandlePipeline(session, "COMMIT\nGET key_3"))
+}
+
+func TestHandlePipelineQuotedNewlines(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok]", database.HandlePipeline(ctx, "SET key_1 'line_1\nline_2'"))
+	require.Equal(t, "$4\n[ok]\n$18\n[ok] line_1\nline_2\n", database.HandlePipeline(ctx, "SET key_2 \"a\\\"\nb\"\nGET key_1\n"))
+	require.Equal(t, "$9\n[ok] a\"\nb\n$22\n[error] invalid symbol\n$34\n[error] unterminated quoted string\n", database.HandlePipeline(ctx, "GET key_2\nGET key_1 key'\n'"))
+
+	require.Equal(t, "$4\n[ok]\n$13\n[ok] a\n[ok] b\n$11\n[not_found]\n", database.HandlePipeline(ctx, "SET key_3 'a\n[ok] b'\nGET key_3\nGET key_4"))
+}
@@ -0,0 +1,232 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+)
+
+var (
+	errSubscriberRequired = errors.New("subscriptions require client session")
+	errKeyspaceChannel    = errors.New("keyspace channels are reserved for events")
+)
+
+// keyspaceChannelPrefix is prefix of channels of keyspace events,
+// events of the key are published to the prefix followed by the key
+const keyspaceChannelPrefix = "__keyspace__:"
+
+// subscriber is a client connection able to receive messages
+// out of request-response order, it's provided by the network
+// layer in the context like connection
+type subscriber interface {
+	Push([]byte) error
+	Done() <-chan struct{}
+	SetPersistent(bool)
+}
+
+// subscription receives events of keyspace channels only
+// of keys allowed by canAccessKey, if it's set
+type subscription struct {
+	client       subscriber
+	channels     map[string]struct{}
+	patterns     map[string]struct{}
+	canAccessKey func(string) bool
+}
+
+func (s *subscription) receives(channel string) bool {
+	key, found := strings.CutPrefix(channel, keyspaceChannelPrefix)
+	return !found || s.canAccessKey == nil || s.canAccessKey(key)
+}
+
+func (s *subscription) count() int {
+	return len(s.channels) + len(s.patterns)
+}
+
+// broker delivers published messages to subscribers of channels
+// and patterns, subscribers whose output buffer is overflowed are
+// disconnected by the network layer and removed when it's done
+type broker struct {
+	mutex    sync.RWMutex
+	channels map[string]map[*subscription]struct{}
+	patterns map[string]map[*subscription]struct{}
+	logger   *zap.Logger
+}
+
+func newBroker(logger *zap.Logger) *broker {
+	return &broker{
+		channels: make(map[string]map[*subscription]struct{}),
+		patterns: make(map[string]map[*subscription]struct{}),
+		logger:   logger,
+	}
+}
+
+// subscribe adds channels or patterns to the subscription
+// and returns number of its channels and patterns
+func (b *broker) subscribe(sub *subscription, names []string, pattern bool) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	subscribed, index := sub.channels, b.channels
+	if pattern {
+		subscribed, index = sub.patterns, b.patterns
+	}
+
+	for _, name := range names {
+		subscribed[name] = struct{}{}
+		if index[name] == nil {
+			index[name] = make(map[*subscription]struct{})
+		}
+
+		index[name][sub] = struct{}{}
+	}
+
+	sub.client.SetPersistent(sub.count() != 0)
+	return sub.count()
+}
+
+// unsubscribe removes channels or patterns from the subscription, all of
+// them are removed if names are empty, returns number of the rest ones
+func (b *broker) unsubscribe(sub *subscription, names []string, pattern bool) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	subscribed, index := sub.channels, b.channels
+	if pattern {
+		subscribed, index = sub.patterns, b.patterns
+	}
+
+	if len(names) == 0 {
+		for name := range subscribed {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		delete(subscribed, name)
+		delete(index[name], sub)
+		if len(index[name]) == 0 {
+			delete(index, name)
+		}
+	}
+
+	sub.client.SetPersistent(sub.count() != 0)
+	return sub.count()
+}
+
+// publish pushes message to subscribers of the channel and subscribers
+// of matching patterns, returns number of clients received the message
+func (b *broker) publish(channel, message string) int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	received := 0
+	for sub := range b.channels[channel] {
+		if sub.receives(channel) && b.push(sub, quoteValues([]string{channel, message}), "[message]") {
+			received++
+		}
+	}
+
+	for pattern, subs := range b.patterns {
+		if !tools.MatchPattern(pattern, channel) {
+			continue
+		}
+
+		for sub := range subs {
+			if sub.receives(channel) && b.push(sub, quoteValues([]string{pattern, channel, message}), "[pmessage]") {
+				received++
+			}
+		}
+	}
+
+	return received
+}
+
+func (b *broker) push(sub *subscription, payload, kind string) bool {
+	if err := sub.client.Push([]byte(kind + " " + payload)); err != nil {
+		b.logger.Debug("failed to push message", zap.Error(err))
+		return false
+	}
+
+	return true
+}
+
+// release removes all subscriptions of the closed client
+func (b *broker) release(sub *subscription) {
+	b.unsubscribe(sub, nil, false)
+	b.unsubscribe(sub, nil, true)
+}
+
+// PublishEvent publishes keyspace event to the channel of the key,
+// the message consists of the command and LSN separated by space
+func (d *Database) PublishEvent(event storage.Event) {
+	message := fmt.Sprintf("%s %d", event.Command, event.LSN)
+	d.broker.publish(keyspaceChannelPrefix+event.Key, message)
+}
+
+// handlePublishQuery publishes the message, keyspace channels
+// are published only by the database, so events can't be faked
+func (d *Database) handlePublishQuery(query compute.Query) string {
+	arguments := query.Arguments()
+	if strings.HasPrefix(arguments[0], keyspaceChannelPrefix) {
+		return fmt.Sprintf("[error] %s", errKeyspaceChannel.Error())
+	}
+
+	received := d.broker.publish(arguments[0], arguments[1])
+	return fmt.Sprintf("[ok] %d", received)
+}
+
+func (d *Database) handleSubscribeQuery(ctx context.Context, query compute.Query) string {
+	sub := d.subscriptionFromContext(ctx)
+	if sub == nil {
+		return fmt.Sprintf("[error] %s", errSubscriberRequired.Error())
+	}
+
+	pattern := query.CommandID() == compute.PSubscribeCommandID
+	count := d.broker.subscribe(sub, query.Arguments(), pattern)
+	return fmt.Sprintf("[ok] %d", count)
+}
+
+func (d *Database) handleUnsubscribeQuery(ctx context.Context, query compute.Query) string {
+	sub := d.subscriptionFromContext(ctx)
+	if sub == nil {
+		return fmt.Sprintf("[error] %s", errSubscriberRequired.Error())
+	}
+
+	pattern := query.CommandID() == compute.PUnsubscribeCommandID
+	count := d.broker.unsubscribe(sub, query.Arguments(), pattern)
+	return fmt.Sprintf("[ok] %d", count)
+}
+
+// subscriptionFromContext returns subscription of the client, it's created
+// on the first call and released by the broker when the client is closed
+func (d *Database) subscriptionFromContext(ctx context.Context) *subscription {
+	client, ok := ctx.Value("session").(subscriber)
+	if !ok {
+		return nil
+	}
+
+	session := sessionFromContext(ctx)
+	if session.subscription == nil {
+		session.subscription = &subscription{
+			client:   client,
+			channels: make(map[string]struct{}),
+			patterns: make(map[string]struct{}),
+			canAccessKey: func(key string) bool {
+				return d.canReceiveEvents(client, key)
+			},
+		}
+
+		go func(sub *subscription) {
+			<-client.Done()
+			d.broker.release(sub)
+		}(session.subscription)
+	}
+
+	return session.subscription
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSubscriber struct {
+	mutex      sync.Mutex
+	values     map[string]interface{}
+	user       string
+	messages   []string
+	limit      int
+	persistent bool
+	done       chan struct{}
+}
+
+func newTestSubscriber(limit int) *testSubscriber {
+	return &testSubscriber{
+		values: make(map[string]interface{}),
+		limit:  limit,
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *testSubscriber) Value(key string) (interface{}, bool) {
+	value, found := s.values[key]
+	return value, found
+}
+
+func (s *testSubscriber) SetValue(key string, value interface{}) {
+	s.values[key] = value
+}
+
+func (s *testSubscriber) User() string {
+	return s.user
+}
+
+func (s *testSubscriber) SetUser(user string) {
+	s.user = user
+}
+
+func (s *testSubscriber) Push(message []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.messages) == s.limit {
+		return errors.New("output buffer is overflowed")
+	}
+
+	s.messages = append(s.messages, string(message))
+	return nil
+}
+
+func (s *testSubscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *testSubscriber) SetPersistent(persistent bool) {
+	s.persistent = persistent
+}
+
+func (s *testSubscriber) received() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]string(nil), s.messages...)
+}
+
+func TestHandlePubSubQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	publisher := context.Background()
+
+	require.Equal(t, "[error] subscriptions require client session", database.HandleQuery(publisher, "SUBSCRIBE news"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(publisher, "PUBLISH news hello"))
+
+	first, second := newTestSubscriber(10), newTestSubscriber(10)
+	firstCtx := context.WithValue(context.Background(), "session", first)
+	secondCtx := context.WithValue(context.Background(), "session", second)
+
+	require.Equal(t, "[ok] 2", database.HandleQuery(firstCtx, "SUBSCRIBE news sport"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(secondCtx, "PSUBSCRIBE news.*"))
+	require.True(t, first.persistent)
+
+	require.Equal(t, "[ok] 1", database.HandleQuery(publisher, `PUBLISH news "hello world"`))
+	require.Equal(t, "[ok] 1", database.HandleQuery(publisher, "PUBLISH news.local update"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(publisher, "PUBLISH weather rain"))
+	require.Equal(t, "[error] keyspace channels are reserved for events", database.HandleQuery(publisher, "PUBLISH __keyspace__:news fake"))
+
+	require.Equal(t, []string{`[message] "news" "hello world"`}, first.received())
+	require.Equal(t, []string{`[pmessage] "news.*" "news.local" "update"`}, second.received())
+
+	require.Equal(t, "[ok] 1", database.HandleQuery(firstCtx, "UNSUBSCRIBE news"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(publisher, "PUBLISH news again"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(firstCtx, "UNSUBSCRIBE"))
+	require.False(t, first.persistent)
+
+	require.Equal(t, "[ok] 0", database.HandleQuery(secondCtx, "PUNSUBSCRIBE news.*"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(publisher, "PUBLISH news.local again"))
+}
+
+func TestPubSubSlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	publisher := context.Background()
+
+	slow, fast := newTestSubscriber(1), newTestSubscriber(10)
+	require.Equal(t, "[ok] 1", database.HandleQuery(context.WithValue(context.Background(), "session", slow), "SUBSCRIBE news"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(context.WithValue(context.Background(), "session", fast), "SUBSCRIBE news"))
+
+	require.Equal(t, "[ok] 2", database.HandleQuery(publisher, "PUBLISH news first"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(publisher, "PUBLISH news second"))
+
+	close(slow.done)
+	close(fast.done)
+	require.Eventually(t, func() bool {
+		return database.HandleQuery(publisher, "PUBLISH news third") == "[ok] 0"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPublishEvent(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	client := newTestSubscriber(10)
+	ctx := context.WithValue(context.Background(), "session", client)
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "PSUBSCRIBE __keyspace__:user:*"))
+
+	database.PublishEvent(storage.Event{Key: "user:1", Command: "SET", LSN: 42})
+	database.PublishEvent(storage.Event{Key: "order:1", Command: "DEL", LSN: 43})
+
+	expected := `[pmessage] "__keyspace__:user:*" "__keyspace__:user:1" "SET 42"`
+	require.Equal(t, []string{expected}, client.received())
+}
+
+func TestPublishEventWithACL(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	database.EnableACL(newTestACL(t))
+
+	reader, admin := newTestSubscriber(10), newTestSubscriber(10)
+	readerCtx := context.WithValue(context.Background(), "session", reader)
+	adminCtx := context.WithValue(context.Background(), "session", admin)
+	require.Equal(t, "[ok]", database.HandleQuery(readerCtx, "AUTH reader password"))
+	require.Equal(t, "[ok]", database.HandleQuery(adminCtx, "AUTH admin secret"))
+
+	denied := "[error] permission denied: user reader has no access to key config"
+	require.Equal(t, denied, database.HandleQuery(readerCtx, "SUBSCRIBE __keyspace__:config"))
+	require.Equal(t, denied, database.HandleQuery(readerCtx, "SUBSCRIBE news __keyspace__:user:1 __keyspace__:config"))
+	require.Equal(t, "[error] permission denied: user reader has no access to key *", database.HandleQuery(readerCtx, "PSUBSCRIBE __keyspace__:*"))
+	require.Equal(t, "[error] keyspace channels are reserved for events", database.HandleQuery(adminCtx, "PUBLISH __keyspace__:user:1 fake"))
+
+	// patterns matching keyspace channels receive only events of allowed keys
+	require.Equal(t, "[ok] 1", database.HandleQuery(readerCtx, "SUBSCRIBE __keyspace__:user:1"))
+	require.Equal(t, "[ok] 2", database.HandleQuery(readerCtx, "PSUBSCRIBE *"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(adminCtx, "PSUBSCRIBE __keyspace__:*"))
+
+	database.PublishEvent(storage.Event{Key: "user:1", Command: "SET", LSN: 42})
+	database.PublishEvent(storage.Event{Key: "config", Command: "DEL", LSN: 43})
+
+	require.Equal(t, []string{
+		`[message] "__keyspace__:user:1" "SET 42"`,
+		`[pmessage] "*" "__keyspace__:user:1" "SET 42"`,
+	}, reader.received())
+	require.Equal(t, []string{
+		`[pmessage] "__keyspace__:*" "__keyspace__:user:1" "SET 42"`,
+		`[pmessage] "__keyspace__:*" "__keyspace__:config" "DEL 43"`,
+	}, admin.received())
+}
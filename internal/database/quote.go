@@ -0,0 +1,158 @@
+package database
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var errInvalidQuotedValues = errors.New("invalid quoted values")
+
+// quoteValues formats values as double-quoted tokens separated by spaces,
+// using the same escape sequences the query parser accepts
+func quoteValues(values []string) string {
+	var sb strings.Builder
+	for idx, value := range values {
+		if idx != 0 {
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(quoteValue(value))
+	}
+
+	return sb.String()
+}
+
+// quoteNullableValues formats values like quoteValues,
+// writing missing values as unquoted (nil)
+func quoteNullableValues(values []*string) string {
+	var sb strings.Builder
+	for idx, value := range values {
+		if idx != 0 {
+			sb.WriteByte(' ')
+		}
+
+		if value == nil {
+			sb.WriteString("(nil)")
+		} else {
+			sb.WriteString(quoteValue(*value))
+		}
+	}
+
+	return sb.String()
+}
+
+// unquoteValues parses values formatted by quoteNullableValues,
+// unquoted tokens are kept as is except (nil), which is missing value
+func unquoteValues(text string) ([]*string, error) {
+	var values []*string
+	for idx := 0; idx < len(text); idx++ {
+		if text[idx] == ' ' {
+			continue
+		}
+
+		if text[idx] != '"' {
+			end := strings.IndexByte(text[idx:], ' ')
+			if end < 0 {
+				end = len(text) - idx
+			}
+
+			token := text[idx : idx+end]
+			idx += end
+			if token == "(nil)" {
+				values = append(values, nil)
+			} else {
+				values = append(values, &token)
+			}
+
+			continue
+		}
+
+		value, end, err := unquoteValue(text, idx)
+		if err != nil {
+			return nil, err
+		}
+
+		idx = end
+		values = append(values, &value)
+	}
+
+	return values, nil
+}
+
+// unquoteValue parses the value quoted by quoteValue, which starts
+// at the index, and returns it with the index of the closing quote
+func unquoteValue(text string, idx int) (string, int, error) {
+	var sb strings.Builder
+	for idx++; idx < len(text); idx++ {
+		symbol := text[idx]
+		if symbol == '"' {
+			return sb.String(), idx, nil
+		}
+
+		if symbol != '\\' {
+			sb.WriteByte(symbol)
+			continue
+		}
+
+		if idx++; idx == len(text) {
+			break
+		}
+
+		switch text[idx] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'x':
+			if idx+2 >= len(text) {
+				return "", 0, errInvalidQuotedValues
+			}
+
+			code, err := strconv.ParseUint(text[idx+1:idx+3], 16, 8)
+			if err != nil {
+				return "", 0, errInvalidQuotedValues
+			}
+
+			sb.WriteByte(byte(code))
+			idx += 2
+		default:
+			sb.WriteByte(text[idx])
+		}
+	}
+
+	return "", 0, errInvalidQuotedValues
+}
+
+func quoteValue(value string) string {
+	const hexDigits = "0123456789abcdef"
+
+	var sb strings.Builder
+	sb.Grow(len(value) + 2)
+	sb.WriteByte('"')
+	for idx := 0; idx < len(value); idx++ {
+		symbol := value[idx]
+		switch {
+		case symbol == '"' || symbol == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(symbol)
+		case symbol == '\n':
+			sb.WriteString(`\n`)
+		case symbol == '\t':
+			sb.WriteString(`\t`)
+		case symbol == '\r':
+			sb.WriteString(`\r`)
+		case symbol < ' ' || symbol == 0x7f:
+			sb.WriteString(`\x`)
+			sb.WriteByte(hexDigits[symbol>>4])
+			sb.WriteByte(hexDigits[symbol&0x0f])
+		default:
+			sb.WriteByte(symbol)
+		}
+	}
+
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestQuoteValues(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `""`, quoteValues([]string{""}))
+	require.Equal(t, `"value_1" "value 2"`, quoteValues([]string{"value_1", "value 2"}))
+	require.Equal(t, `"{\"name\": \"Ann\"}\n\\\x01"`, quoteValues([]string{"{\"name\": \"Ann\"}\n\\\x01"}))
+}
+
+func TestQuoteNullableValues(t *testing.T) {
+	t.Parallel()
+
+	value := "value"
+	require.Equal(t, `"value" (nil)`, quoteNullableValues([]*string{&value, nil}))
+}
+
+func TestQuotedValuesParsing(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	parser, err := compute.NewParser(zap.NewNop())
+	require.NoError(t, err)
+
+	values := []string{"", "value", "a \"b\" c", "line\nbreak\t\x00\x7f", "字文下"}
+	tokens, err := parser.ParseQuery(ctx, quoteValues(values))
+	require.NoError(t, err)
+	require.Equal(t, values, tokens)
+}
+
+func TestUnquoteValues(t *testing.T) {
+	t.Parallel()
+
+	value, empty := "value", ""
+	unquoted, err := unquoteValues(quoteNullableValues([]*string{&value, nil, &empty}))
+	require.NoError(t, err)
+	require.Equal(t, []*string{&value, nil, &empty}, unquoted)
+
+	cursor, special := "15", "{\"name\": \"Ann\"}\n\\\x01\t\r\x7f字文下"
+	unquoted, err = unquoteValues(cursor + " " + quoteValue(special))
+	require.NoError(t, err)
+	require.Equal(t, []*string{&cursor, &special}, unquoted)
+
+	unquoted, err = unquoteValues("")
+	require.NoError(t, err)
+	require.Empty(t, unquoted)
+
+	for _, text := range []string{`"value`, `"value\"`, `"\x1"`, `"\xzz"`} {
+		_, err = unquoteValues(text)
+		require.ErrorIs(t, err, errInvalidQuotedValues, text)
+	}
+}
@@ -0,0 +1,316 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/network"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+)
+
+// commands handled only by RESP protocol, since they
+// negotiate the connection and don't work with data
+const (
+	pingCommand  = "PING"
+	helloCommand = "HELLO"
+)
+
+// respServerName is reported to clients by HELLO
+const respServerName = "key-value-storage"
+
+var (
+	errRESPSubscriptions = errors.New("subscriptions aren't supported by RESP protocol")
+	errRESPArguments     = errors.New("invalid arguments")
+)
+
+// respProtocolVersions are versions of protocols requested by HELLO
+var respProtocolVersions = map[string]int{
+	network.RESP2Protocol: 2,
+	network.RESP3Protocol: 3,
+}
+
+// respReply is the type of RESP reply to the value of [ok] response
+type respReply int
+
+const (
+	// bulkReply is bulk string
+	bulkReply respReply = iota
+	// integerReply is integer
+	integerReply
+	// arrayReply is array of values quoted like by quoteNullableValues
+	arrayReply
+	// mapReply is array of quoted fields and values, which is map in RESP3
+	mapReply
+	// pairReply is array like arrayReply, but its missing value is null array
+	pairReply
+	// scanReply is cursor and array of quoted keys
+	scanReply
+	// conditionalReply is OK if the write has happened or null otherwise
+	conditionalReply
+	// scriptReply is bulk string, which is null if there is no result
+	scriptReply
+)
+
+// respReplies lists commands, whose values aren't replied by bulk strings
+var respReplies = map[int]respReply{
+	compute.DelCommandID:           integerReply,
+	compute.MDelCommandID:          integerReply,
+	compute.ExistsCommandID:        integerReply,
+	compute.TTLCommandID:           integerReply,
+	compute.PTTLCommandID:          integerReply,
+	compute.ExpireCommandID:        integerReply,
+	compute.PersistCommandID:       integerReply,
+	compute.CASCommandID:           integerReply,
+	compute.SetNXCommandID:         integerReply,
+	compute.IncrCommandID:          integerReply,
+	compute.DecrCommandID:          integerReply,
+	compute.IncrByCommandID:        integerReply,
+	compute.DecrByCommandID:        integerReply,
+	compute.AppendCommandID:        integerReply,
+	compute.StrLenCommandID:        integerReply,
+	compute.SetRangeCommandID:      integerReply,
+	compute.HSetCommandID:          integerReply,
+	compute.HDelCommandID:          integerReply,
+	compute.HLenCommandID:          integerReply,
+	compute.HExistsCommandID:       integerReply,
+	compute.HIncrByCommandID:       integerReply,
+	compute.LPushCommandID:         integerReply,
+	compute.RPushCommandID:         integerReply,
+	compute.LLenCommandID:          integerReply,
+	compute.SAddCommandID:          integerReply,
+	compute.SRemCommandID:          integerReply,
+	compute.SIsMemberCommandID:     integerReply,
+	compute.SCardCommandID:         integerReply,
+	compute.ZAddCommandID:          integerReply,
+	compute.ZRemCommandID:          integerReply,
+	compute.ZRankCommandID:         integerReply,
+	compute.ZCardCommandID:         integerReply,
+	compute.PublishCommandID:       integerReply,
+	compute.MGetCommandID:          arrayReply,
+	compute.KeysCommandID:          arrayReply,
+	compute.LRangeCommandID:        arrayReply,
+	compute.SMembersCommandID:      arrayReply,
+	compute.SInterCommandID:        arrayReply,
+	compute.SUnionCommandID:        arrayReply,
+	compute.SDiffCommandID:         arrayReply,
+	compute.ZRangeCommandID:        arrayReply,
+	compute.ZRangeByScoreCommandID: arrayReply,
+	compute.HGetAllCommandID:       mapReply,
+	compute.BGetDelCommandID:       pairReply,
+	compute.ScanCommandID:          scanReply,
+	compute.EvalCommandID:          scriptReply,
+}
+
+// HandleRESPCommand handles the command split into arguments by RESP
+// protocol and encodes the response by the protocol, command names and
+// options are case-insensitive like in Redis, subscriptions aren't
+// supported, since pushed messages are formatted by the text protocol
+func (d *Database) HandleRESPCommand(ctx context.Context, arguments []string, protocol string) []byte {
+	txID := d.idGenerator.Generate()
+	ctx = context.WithValue(ctx, "tx", txID)
+
+	d.logger.Debug(
+		"handling command",
+		zap.Int64("tx", txID),
+		zap.Strings("arguments", compute.RedactTokens(arguments)),
+	)
+
+	tokens := arguments
+	if len(tokens) != 0 {
+		tokens = append([]string{strings.ToUpper(tokens[0])}, tokens[1:]...)
+
+		switch tokens[0] {
+		case pingCommand:
+			return d.handlePingCommand(ctx, tokens[1:], protocol)
+		case helloCommand:
+			return d.handleHelloCommand(ctx, tokens[1:], protocol)
+		}
+	}
+
+	query, err := d.computeLayer.HandleTokens(ctx, tokens)
+	if err != nil {
+		return encodeRESP(fmt.Sprintf("[error] %s", err.Error()), bulkReply, protocol)
+	}
+
+	switch query.CommandID() {
+	case compute.SubscribeCommandID, compute.PSubscribeCommandID:
+		return encodeRESP(fmt.Sprintf("[error] %s", errRESPSubscriptions.Error()), bulkReply, protocol)
+	}
+
+	return encodeRESP(d.handleQueryBy(ctx, query, d.dispatchRESPQuery), respReplyOf(query), protocol)
+}
+
+// handlePingCommand replies PONG or the message, authentication
+// is required like for other commands if ACL is enabled
+func (d *Database) handlePingCommand(ctx context.Context, arguments []string, protocol string) []byte {
+	if d.acl != nil && d.userFromContext(ctx) == nil {
+		return encodeRESP(fmt.Sprintf("[error] %s", errAuthRequired.Error()), bulkReply, protocol)
+	}
+
+	switch len(arguments) {
+	case 0:
+		return network.AppendSimpleString(nil, "PONG")
+	case 1:
+		return network.AppendBulkString(nil, arguments[0])
+	default:
+		return encodeRESP(fmt.Sprintf("[error] %s", errRESPArguments.Error()), bulkReply, protocol)
+	}
+}
+
+// handleHelloCommand handles HELLO [protover [AUTH username password]],
+// the protocol is chosen by the configuration of the server, so only its
+// version is accepted, the reply describes the server like in Redis
+func (d *Database) handleHelloCommand(ctx context.Context, arguments []string, protocol string) []byte {
+	version := respProtocolVersions[protocol]
+	if len(arguments) != 0 {
+		if requested, err := strconv.Atoi(arguments[0]); err != nil || requested != version {
+			return network.AppendError(nil, "NOPROTO unsupported protocol version")
+		}
+
+		arguments = arguments[1:]
+	}
+
+	if len(arguments) != 0 {
+		if strings.ToUpper(arguments[0]) != compute.AuthCommand {
+			return encodeRESP(fmt.Sprintf("[error] %s", errRESPArguments.Error()), bulkReply, protocol)
+		}
+
+		// AUTH option is analyzed like the command to check its arity
+		query, err := d.computeLayer.HandleTokens(ctx, append([]string{compute.AuthCommand}, arguments[1:]...))
+		if err != nil {
+			return encodeRESP(fmt.Sprintf("[error] %s", err.Error()), bulkReply, protocol)
+		}
+
+		if response := d.handleQuery(ctx, query); response != "[ok]" {
+			return encodeRESP(response, bulkReply, protocol)
+		}
+	}
+
+	reply := network.AppendMapHeader(nil, 3, protocol)
+	reply = network.AppendBulkString(reply, "server")
+	reply = network.AppendBulkString(reply, respServerName)
+	reply = network.AppendBulkString(reply, "proto")
+	reply = network.AppendInteger(reply, int64(version))
+	reply = network.AppendBulkString(reply, "mode")
+	return network.AppendBulkString(reply, "standalone")
+}
+
+// dispatchRESPQuery handles the query like dispatchQuery, but replies
+// to DEL and MDEL with number of deleted keys like Redis does
+func (d *Database) dispatchRESPQuery(ctx context.Context, query compute.Query) string {
+	switch query.CommandID() {
+	case compute.DelCommandID:
+		deleted, err := d.keyValueLayer(ctx).Del(ctx, query.Arguments()[0])
+		if err != nil {
+			return fmt.Sprintf("[error] %s", err.Error())
+		}
+
+		return fmt.Sprintf("[ok] %d", boolToInt(deleted))
+	case compute.MDelCommandID:
+		deleted, err := d.keyValueLayer(ctx).MDel(ctx, query.Arguments())
+		if err != nil {
+			return fmt.Sprintf("[error] %s", err.Error())
+		}
+
+		return fmt.Sprintf("[ok] %d", deleted)
+	}
+
+	return d.dispatchQuery(ctx, query)
+}
+
+func respReplyOf(query compute.Query) respReply {
+	if query.CommandID() == compute.SetCommandID && isConditionalSet(query) {
+		return conditionalReply
+	}
+
+	return respReplies[query.CommandID()]
+}
+
+// encodeRESP converts the text response to the reply of the type: [ok]
+// without value is encoded as OK simple string (or empty array or map,
+// or null for scripts), [not_found] as null and errors keep their codes like
+// WRONGTYPE or get ERR one; values, which don't fit the type, are
+// encoded as bulk strings
+func encodeRESP(response string, reply respReply, protocol string) []byte {
+	switch {
+	case response == "[not_found]":
+		if reply == pairReply {
+			return network.AppendNullArray(nil, protocol)
+		}
+
+		return network.AppendNull(nil, protocol)
+	case strings.HasPrefix(response, "[error] "):
+		return network.AppendError(nil, respError(strings.TrimPrefix(response, "[error] ")))
+	case response == "[ok]":
+		switch reply {
+		case arrayReply:
+			return network.AppendArrayHeader(nil, 0)
+		case mapReply:
+			return network.AppendMapHeader(nil, 0, protocol)
+		case scriptReply:
+			return network.AppendNull(nil, protocol)
+		default:
+			return network.AppendSimpleString(nil, "OK")
+		}
+	case !strings.HasPrefix(response, "[ok] "):
+		return network.AppendBulkString(nil, response)
+	}
+
+	value := strings.TrimPrefix(response, "[ok] ")
+	switch reply {
+	case integerReply:
+		if integer, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return network.AppendInteger(nil, integer)
+		}
+	case conditionalReply:
+		if value == "0" {
+			return network.AppendNull(nil, protocol)
+		}
+
+		return network.AppendSimpleString(nil, "OK")
+	case arrayReply, pairReply:
+		if values, err := unquoteValues(value); err == nil {
+			return appendRESPValues(network.AppendArrayHeader(nil, len(values)), values, protocol)
+		}
+	case mapReply:
+		if values, err := unquoteValues(value); err == nil && len(values)%2 == 0 {
+			return appendRESPValues(network.AppendMapHeader(nil, len(values)/2, protocol), values, protocol)
+		}
+	case scanReply:
+		cursor, keys, _ := strings.Cut(value, " ")
+		if values, err := unquoteValues(keys); err == nil {
+			encoded := network.AppendArrayHeader(nil, 2)
+			encoded = network.AppendBulkString(encoded, cursor)
+			return appendRESPValues(network.AppendArrayHeader(encoded, len(values)), values, protocol)
+		}
+	}
+
+	return network.AppendBulkString(nil, value)
+}
+
+// appendRESPValues appends elements of array, missing values are nulls
+func appendRESPValues(dst []byte, values []*string, protocol string) []byte {
+	for _, value := range values {
+		if value == nil {
+			dst = network.AppendNull(dst, protocol)
+		} else {
+			dst = network.AppendBulkString(dst, *value)
+		}
+	}
+
+	return dst
+}
+
+// respError prefixes the message by ERR code unless
+// it starts with its own code like WRONGTYPE one
+func respError(message string) string {
+	code, _, _ := strings.Cut(message, " ")
+	if code != "" && code == strings.ToUpper(code) && code != strings.ToLower(code) {
+		return message
+	}
+
+	return "ERR " + message
+}
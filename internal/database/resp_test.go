@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/network"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleRESPCommand(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+
+	handle := func(protocol string, arguments ...string) string {
+		return string(database.HandleRESPCommand(ctx, arguments, protocol))
+	}
+
+	require.Equal(t, "+OK\r\n", handle(network.RESP2Protocol, "set", "key", "value with spaces\r\n"))
+	require.Equal(t, "$19\r\nvalue with spaces\r\n\r\n", handle(network.RESP2Protocol, "GET", "key"))
+	require.Equal(t, "$-1\r\n", handle(network.RESP2Protocol, "GET", "missing"))
+	require.Equal(t, "_\r\n", handle(network.RESP3Protocol, "GET", "missing"))
+	require.Equal(t, ":1\r\n", handle(network.RESP2Protocol, "INCR", "counter"))
+	require.Equal(t, "-ERR invalid command\r\n", handle(network.RESP2Protocol, "UNKNOWN"))
+	require.Equal(t, "-ERR subscriptions aren't supported by RESP protocol\r\n", handle(network.RESP2Protocol, "SUBSCRIBE", "channel"))
+
+	// options are case-insensitive
+	require.Equal(t, "+OK\r\n", handle(network.RESP2Protocol, "SET", "expiring", "value", "ex", "100"))
+	require.Equal(t, ":100\r\n", handle(network.RESP2Protocol, "TTL", "expiring"))
+	require.Equal(t, "$-1\r\n", handle(network.RESP2Protocol, "SET", "expiring", "value", "nx"))
+	require.Equal(t, "+OK\r\n", handle(network.RESP2Protocol, "SET", "expiring", "value", "xx"))
+
+	require.Equal(t, "*3\r\n$19\r\nvalue with spaces\r\n\r\n$-1\r\n$1\r\n1\r\n", handle(network.RESP2Protocol, "MGET", "key", "missing", "counter"))
+	require.Equal(t, "*2\r\n$1\r\n1\r\n_\r\n", handle(network.RESP3Protocol, "MGET", "counter", "missing"))
+	require.Equal(t, ":2\r\n", handle(network.RESP2Protocol, "RPUSH", "list", "a \"b\"", "c\n"))
+	require.Equal(t, "*2\r\n$5\r\na \"b\"\r\n$2\r\nc\n\r\n", handle(network.RESP2Protocol, "LRANGE", "list", "0", "-1"))
+	require.Equal(t, "*0\r\n", handle(network.RESP2Protocol, "LRANGE", "missing", "0", "-1"))
+	require.Equal(t, ":1\r\n", handle(network.RESP2Protocol, "HSET", "hash", "field", "value"))
+	require.Equal(t, "*2\r\n$5\r\nfield\r\n$5\r\nvalue\r\n", handle(network.RESP2Protocol, "HGETALL", "hash"))
+	require.Equal(t, "%1\r\n$5\r\nfield\r\n$5\r\nvalue\r\n", handle(network.RESP3Protocol, "HGETALL", "hash"))
+	require.Equal(t, "*0\r\n", handle(network.RESP2Protocol, "HGETALL", "missing"))
+	require.Equal(t, "%0\r\n", handle(network.RESP3Protocol, "HGETALL", "missing"))
+	require.Equal(t, "*2\r\n$1\r\n0\r\n*1\r\n$4\r\nhash\r\n", handle(network.RESP2Protocol, "SCAN", "0", "match", "h*"))
+	require.Equal(t, "*-1\r\n", handle(network.RESP2Protocol, "BGETDEL", "missing", "0.01"))
+	require.Equal(t, "$-1\r\n", handle(network.RESP2Protocol, "EVAL", "return nil", "0"))
+
+	// errors with their own codes aren't prefixed
+	require.Equal(t, "-WRONGTYPE operation against a key holding the wrong kind of value\r\n", handle(network.RESP2Protocol, "GET", "list"))
+
+	// deletions reply number of deleted keys
+	require.Equal(t, ":1\r\n", handle(network.RESP2Protocol, "DEL", "list"))
+	require.Equal(t, ":0\r\n", handle(network.RESP2Protocol, "DEL", "list"))
+	require.Equal(t, ":2\r\n", handle(network.RESP2Protocol, "MDEL", "key", "hash", "key", "missing"))
+
+	require.Equal(t, "+OK\r\n", handle(network.RESP2Protocol, "BEGIN"))
+	require.Equal(t, ":1\r\n", handle(network.RESP2Protocol, "DEL", "counter"))
+	require.Equal(t, "+OK\r\n", handle(network.RESP2Protocol, "COMMIT"))
+	require.Equal(t, ":0\r\n", handle(network.RESP2Protocol, "EXISTS", "counter"))
+}
+
+func TestHandleRESPConnectionCommands(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.WithValue(context.Background(), "session", network.NewSession(1, 1))
+
+	handle := func(protocol string, arguments ...string) string {
+		return string(database.HandleRESPCommand(ctx, arguments, protocol))
+	}
+
+	require.Equal(t, "+PONG\r\n", handle(network.RESP2Protocol, "ping"))
+	require.Equal(t, "$5\r\nhello\r\n", handle(network.RESP2Protocol, "PING", "hello"))
+	require.Equal(t, "-ERR invalid arguments\r\n", handle(network.RESP2Protocol, "PING", "a", "b"))
+
+	hello := "$6\r\nserver\r\n$17\r\nkey-value-storage\r\n$5\r\nproto\r\n:%s\r\n$4\r\nmode\r\n$10\r\nstandalone\r\n"
+	require.Equal(t, "*6\r\n"+strings.Replace(hello, "%s", "2", 1), handle(network.RESP2Protocol, "HELLO"))
+	require.Equal(t, "*6\r\n"+strings.Replace(hello, "%s", "2", 1), handle(network.RESP2Protocol, "hello", "2"))
+	require.Equal(t, "%3\r\n"+strings.Replace(hello, "%s", "3", 1), handle(network.RESP3Protocol, "HELLO", "3"))
+	require.Equal(t, "-NOPROTO unsupported protocol version\r\n", handle(network.RESP2Protocol, "HELLO", "3"))
+	require.Equal(t, "-NOPROTO unsupported protocol version\r\n", handle(network.RESP3Protocol, "HELLO", "three"))
+	require.Equal(t, "-ERR invalid arguments\r\n", handle(network.RESP2Protocol, "HELLO", "2", "SETNAME", "name"))
+
+	database.EnableACL(newTestACL(t))
+	require.Equal(t, "-ERR authentication required\r\n", handle(network.RESP2Protocol, "PING"))
+	require.Equal(t, "-ERR invalid username or password\r\n", handle(network.RESP2Protocol, "HELLO", "2", "AUTH", "reader", "secret"))
+	require.Equal(t, "-ERR invalid arguments\r\n", handle(network.RESP2Protocol, "HELLO", "2", "AUTH", "reader"))
+	require.Equal(t, "-ERR invalid arguments\r\n", handle(network.RESP2Protocol, "HELLO", "2", "AUTH", "reader", "password", "SETNAME", "name"))
+	require.Equal(t, "-ERR authentication required\r\n", handle(network.RESP2Protocol, "PING"))
+	require.Equal(t, "*6\r\n"+strings.Replace(hello, "%s", "2", 1), handle(network.RESP2Protocol, "HELLO", "2", "auth", "reader", "password"))
+	require.Equal(t, "+PONG\r\n", handle(network.RESP2Protocol, "PING"))
+}
+
+func TestRESPClient(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		protocol string
+		version  int
+		address  string
+	}{
+		"resp2": {protocol: network.RESP2Protocol, version: 2, address: "localhost:20012"},
+		"resp3": {protocol: network.RESP3Protocol, version: 3, address: "localhost:20013"},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			database := newTestDatabase(t)
+			server, err := network.NewTCPServerWithProtocol(test.address, 10, 2048, time.Minute, 16, test.protocol, nil, zap.NewNop())
+			require.NoError(t, err)
+
+			go func() {
+				require.NoError(t, server.HandleCommands(ctx, func(ctx context.Context, arguments []string) []byte {
+					return database.HandleRESPCommand(ctx, arguments, test.protocol)
+				}))
+			}()
+
+			client := redis.NewClient(&redis.Options{Addr: test.address, Protocol: test.version, MaxRetries: -1})
+			defer client.Close()
+
+			require.Eventually(t, func() bool {
+				return client.Ping(ctx).Err() == nil
+			}, time.Second, time.Millisecond*10)
+
+			require.NoError(t, client.Set(ctx, "key", "value", time.Minute).Err())
+			require.Equal(t, "value", client.Get(ctx, "key").Val())
+			require.Equal(t, time.Minute, client.TTL(ctx, "key").Val())
+			require.ErrorIs(t, client.Get(ctx, "missing").Err(), redis.Nil)
+
+			require.Equal(t, []interface{}{"value", nil}, client.MGet(ctx, "key", "missing").Val())
+			require.Equal(t, int64(1), client.Incr(ctx, "counter").Val())
+			require.True(t, client.SetNX(ctx, "other", "value", 0).Val())
+			require.False(t, client.SetNX(ctx, "other", "value", 0).Val())
+
+			require.Equal(t, int64(3), client.RPush(ctx, "list", "a", "b", "c").Val())
+			require.Equal(t, []string{"a", "b", "c"}, client.LRange(ctx, "list", 0, -1).Val())
+			require.Equal(t, int64(1), client.HSet(ctx, "hash", "field", "value").Val())
+			require.Equal(t, map[string]string{"field": "value"}, client.HGetAll(ctx, "hash").Val())
+
+			err = client.Get(ctx, "list").Err()
+			require.Error(t, err)
+			require.True(t, strings.HasPrefix(err.Error(), "WRONGTYPE "), err.Error())
+
+			keys, cursor, err := client.Scan(ctx, 0, "l*", 100).Result()
+			require.NoError(t, err)
+			require.Equal(t, []string{"list"}, keys)
+			require.Equal(t, uint64(0), cursor)
+
+			require.ErrorIs(t, client.Eval(ctx, "return nil", nil).Err(), redis.Nil)
+			require.Equal(t, int64(1), client.Del(ctx, "list").Val())
+			require.Equal(t, int64(0), client.Exists(ctx, "list").Val())
+		})
+	}
+}
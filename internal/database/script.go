@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"strconv"
+)
+
+var errInvalidKeysNumber = errors.New("number of keys is not an integer or out of range")
+
+// handleEvalQuery runs the script with the keys, which follow
+// their number, the rest of arguments are passed in ARGV
+func (d *Database) handleEvalQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	keys, args, err := evalKeys(arguments)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	result, found, err := d.storageLayer.Eval(ctx, arguments[0], keys, args)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if !found {
+		return "[ok]"
+	}
+
+	return fmt.Sprintf("[ok] %s", result)
+}
+
+// evalKeys splits arguments of EVAL after the script
+// by the number of keys into the keys and arguments
+func evalKeys(arguments []string) ([]string, []string, error) {
+	keysNumber, err := strconv.Atoi(arguments[1])
+	if err != nil || keysNumber < 0 || keysNumber > len(arguments)-2 {
+		return nil, nil, errInvalidKeysNumber
+	}
+
+	return arguments[2 : 2+keysNumber], arguments[2+keysNumber:], nil
+}
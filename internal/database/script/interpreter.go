@@ -0,0 +1,512 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"math"
+	"strconv"
+)
+
+var (
+	ErrStepBudgetExceeded = errors.New("script step budget is exceeded")
+	ErrSizeBudgetExceeded = errors.New("script string size budget is exceeded")
+	ErrUndeclaredKey      = errors.New("script accessed undeclared key")
+)
+
+// sizeBudget limits total size of strings built by concatenations of
+// one run, so a script can't exhaust memory or copy huge strings while
+// it holds partition locks, the result of concatenation is limited too
+const sizeBudget = 64 << 20
+
+// Storage is accessed by scripts through builtin functions,
+// implementations are not required to be safe for concurrent use
+type Storage interface {
+	Get(key string) (string, bool, error)
+	Set(key, value string)
+	Del(key string) (bool, error)
+}
+
+type builtin struct {
+	arguments int
+	call      func(*interpreter, []interface{}) (interface{}, error)
+}
+
+// builtins are the only functions available to scripts, keys
+// passed to storage functions must be declared in KEYS
+var builtins = map[string]builtin{
+	"get": {arguments: 1, call: func(i *interpreter, arguments []interface{}) (interface{}, error) {
+		key, err := i.key(arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		value, found, err := i.storage.Get(key)
+		if err != nil || !found {
+			return nil, err
+		}
+
+		return value, nil
+	}},
+	"set": {arguments: 2, call: func(i *interpreter, arguments []interface{}) (interface{}, error) {
+		key, err := i.key(arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if arguments[1] == nil {
+			return nil, errors.New("set: value is nil")
+		}
+
+		i.storage.Set(key, toString(arguments[1]))
+		return true, nil
+	}},
+	"del": {arguments: 1, call: func(i *interpreter, arguments []interface{}) (interface{}, error) {
+		key, err := i.key(arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return i.storage.Del(key)
+	}},
+	"exists": {arguments: 1, call: func(i *interpreter, arguments []interface{}) (interface{}, error) {
+		key, err := i.key(arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		_, found, err := i.storage.Get(key)
+		return found, err
+	}},
+	"incrby": {arguments: 2, call: func(i *interpreter, arguments []interface{}) (interface{}, error) {
+		key, err := i.key(arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		delta, ok := toNumber(arguments[1])
+		if !ok || delta != math.Trunc(delta) || delta < math.MinInt64 || delta >= math.MaxInt64 {
+			return nil, errors.New("incrby: delta is not an integer or out of range")
+		}
+
+		current, found, err := i.storage.Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		var value int64
+		if found {
+			if value, err = strconv.ParseInt(current, 10, 64); err != nil {
+				return nil, errors.New("incrby: value is not an integer")
+			}
+		}
+
+		value, ok = tools.AddInt64(value, int64(delta))
+		if !ok {
+			return nil, errors.New("incrby: increment would overflow")
+		}
+
+		i.storage.Set(key, strconv.FormatInt(value, 10))
+		return float64(value), nil
+	}},
+	"tonumber": {arguments: 1, call: func(_ *interpreter, arguments []interface{}) (interface{}, error) {
+		if number, ok := toNumber(arguments[0]); ok {
+			return number, nil
+		}
+
+		return nil, nil
+	}},
+	"tostring": {arguments: 1, call: func(_ *interpreter, arguments []interface{}) (interface{}, error) {
+		return toString(arguments[0]), nil
+	}},
+}
+
+// returned is used to unwind evaluation of nested blocks by return
+type returned struct {
+	value interface{}
+}
+
+type interpreter struct {
+	storage   Storage
+	keys      []string
+	args      []string
+	declared  map[string]struct{}
+	variables map[string]interface{}
+	steps     int
+	size      int
+}
+
+// Run executes the script, every evaluated statement and expression takes
+// a step of the budget, so runaway scripts are stopped, concatenations take
+// sizes of their results from the size budget, the result is the returned
+// value formatted as string and false if nil is returned
+func (s *Script) Run(storage Storage, keys, args []string, budget int) (string, bool, error) {
+	i := &interpreter{
+		storage:   storage,
+		keys:      keys,
+		args:      args,
+		declared:  make(map[string]struct{}, len(keys)),
+		variables: make(map[string]interface{}),
+		steps:     budget,
+		size:      sizeBudget,
+	}
+
+	for _, key := range keys {
+		i.declared[key] = struct{}{}
+	}
+
+	result, err := i.executeBlock(s.statements)
+	if err != nil {
+		return "", false, err
+	}
+
+	if result == nil || result.value == nil {
+		return "", false, nil
+	}
+
+	return toString(result.value), true, nil
+}
+
+func (i *interpreter) step() error {
+	if i.steps <= 0 {
+		return ErrStepBudgetExceeded
+	}
+
+	i.steps--
+	return nil
+}
+
+// allocate takes size of the built string from the size budget
+func (i *interpreter) allocate(size int) error {
+	if size > i.size {
+		return ErrSizeBudgetExceeded
+	}
+
+	i.size -= size
+	return nil
+}
+
+func (i *interpreter) executeBlock(statements []statement) (*returned, error) {
+	for _, current := range statements {
+		result, err := i.execute(current)
+		if err != nil || result != nil {
+			return result, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (i *interpreter) execute(current statement) (*returned, error) {
+	if err := i.step(); err != nil {
+		return nil, err
+	}
+
+	switch current := current.(type) {
+	case *assignStatement:
+		value, err := i.evaluate(current.value)
+		if err != nil {
+			return nil, err
+		}
+
+		i.variables[current.name] = value
+	case *ifStatement:
+		for idx, condition := range current.conditions {
+			value, err := i.evaluate(condition)
+			if err != nil {
+				return nil, err
+			}
+
+			if isTrue(value) {
+				return i.executeBlock(current.blocks[idx])
+			}
+		}
+
+		return i.executeBlock(current.otherwise)
+	case *whileStatement:
+		for {
+			value, err := i.evaluate(current.condition)
+			if err != nil || !isTrue(value) {
+				return nil, err
+			}
+
+			result, err := i.executeBlock(current.body)
+			if err != nil || result != nil {
+				return result, err
+			}
+		}
+	case *returnStatement:
+		if current.value == nil {
+			return &returned{}, nil
+		}
+
+		value, err := i.evaluate(current.value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &returned{value: value}, nil
+	case *callStatement:
+		if _, err := i.evaluate(current.call); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (i *interpreter) evaluate(current expression) (interface{}, error) {
+	if err := i.step(); err != nil {
+		return nil, err
+	}
+
+	switch current := current.(type) {
+	case *literalExpression:
+		return current.value, nil
+	case *variableExpression:
+		return i.variables[current.name], nil
+	case *indexExpression:
+		return i.evaluateIndex(current)
+	case *callExpression:
+		return i.evaluateCall(current)
+	case *unaryExpression:
+		operand, err := i.evaluate(current.operand)
+		if err != nil {
+			return nil, err
+		}
+
+		if current.operator == "not" {
+			return !isTrue(operand), nil
+		}
+
+		number, ok := toNumber(operand)
+		if !ok {
+			return nil, fmt.Errorf("attempt to negate %s value", typeName(operand))
+		}
+
+		return -number, nil
+	case *binaryExpression:
+		return i.evaluateBinary(current)
+	}
+
+	return nil, fmt.Errorf("unknown expression %T", current)
+}
+
+func (i *interpreter) evaluateIndex(current *indexExpression) (interface{}, error) {
+	index, err := i.evaluate(current.index)
+	if err != nil {
+		return nil, err
+	}
+
+	values := i.args
+	if current.name == "KEYS" {
+		values = i.keys
+	}
+
+	number, ok := toNumber(index)
+	if !ok || number != math.Trunc(number) || number < 1 || int(number) > len(values) {
+		return nil, nil
+	}
+
+	return values[int(number)-1], nil
+}
+
+func (i *interpreter) evaluateCall(current *callExpression) (interface{}, error) {
+	function := builtins[current.name]
+	if len(current.arguments) != function.arguments {
+		return nil, fmt.Errorf("%s: expected %d arguments", current.name, function.arguments)
+	}
+
+	arguments := make([]interface{}, 0, len(current.arguments))
+	for _, argument := range current.arguments {
+		value, err := i.evaluate(argument)
+		if err != nil {
+			return nil, err
+		}
+
+		arguments = append(arguments, value)
+	}
+
+	return function.call(i, arguments)
+}
+
+func (i *interpreter) evaluateBinary(current *binaryExpression) (interface{}, error) {
+	left, err := i.evaluate(current.left)
+	if err != nil {
+		return nil, err
+	}
+
+	// logical operators are short-circuited and return one of operands
+	switch current.operator {
+	case "and":
+		if !isTrue(left) {
+			return left, nil
+		}
+
+		return i.evaluate(current.right)
+	case "or":
+		if isTrue(left) {
+			return left, nil
+		}
+
+		return i.evaluate(current.right)
+	}
+
+	right, err := i.evaluate(current.right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch current.operator {
+	case "==":
+		return left == right, nil
+	case "~=":
+		return left != right, nil
+	case "..":
+		for _, operand := range []interface{}{left, right} {
+			if !isConcatenable(operand) {
+				return nil, fmt.Errorf("attempt to concatenate %s value", typeName(operand))
+			}
+		}
+
+		leftString, rightString := toString(left), toString(right)
+		if err := i.allocate(len(leftString) + len(rightString)); err != nil {
+			return nil, err
+		}
+
+		return leftString + rightString, nil
+	case "<", "<=", ">", ">=":
+		return compare(current.operator, left, right)
+	}
+
+	return arithmetic(current.operator, left, right)
+}
+
+func (i *interpreter) key(value interface{}) (string, error) {
+	key, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("key must be string, got %s", typeName(value))
+	}
+
+	if _, declared := i.declared[key]; !declared {
+		return "", fmt.Errorf("%w: %s", ErrUndeclaredKey, key)
+	}
+
+	return key, nil
+}
+
+func arithmetic(operator string, left, right interface{}) (interface{}, error) {
+	x, ok := toNumber(left)
+	if !ok {
+		return nil, fmt.Errorf("attempt to perform arithmetic on %s value", typeName(left))
+	}
+
+	y, ok := toNumber(right)
+	if !ok {
+		return nil, fmt.Errorf("attempt to perform arithmetic on %s value", typeName(right))
+	}
+
+	switch operator {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		return x / y, nil
+	default:
+		return x - math.Floor(x/y)*y, nil
+	}
+}
+
+// compare compares numbers or strings, strings looking like
+// numbers are compared with numbers numerically
+func compare(operator string, left, right interface{}) (bool, error) {
+	var result int
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+
+	if leftIsString && rightIsString {
+		switch {
+		case leftString < rightString:
+			result = -1
+		case leftString > rightString:
+			result = 1
+		}
+	} else {
+		x, leftOk := toNumber(left)
+		y, rightOk := toNumber(right)
+		if !leftOk || !rightOk {
+			return false, fmt.Errorf("attempt to compare %s with %s", typeName(left), typeName(right))
+		}
+
+		switch {
+		case x < y:
+			result = -1
+		case x > y:
+			result = 1
+		}
+	}
+
+	switch operator {
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	default:
+		return result >= 0, nil
+	}
+}
+
+func isTrue(value interface{}) bool {
+	return value != nil && value != false
+}
+
+func isConcatenable(value interface{}) bool {
+	switch value.(type) {
+	case string, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case string:
+		return parseNumber(value)
+	default:
+		return 0, false
+	}
+}
+
+func toString(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return value.(string)
+	}
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,217 @@
+package script
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type mapStorage map[string]string
+
+func (s mapStorage) Get(key string) (string, bool, error) {
+	value, found := s[key]
+	return value, found, nil
+}
+
+func (s mapStorage) Set(key, value string) {
+	s[key] = value
+}
+
+func (s mapStorage) Del(key string) (bool, error) {
+	_, found := s[key]
+	delete(s, key)
+	return found, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		source string
+		keys   []string
+		args   []string
+		data   mapStorage
+
+		result   string
+		found    bool
+		expected mapStorage
+	}{
+		"empty script": {
+			source: "",
+		},
+		"arithmetic with precedence": {
+			source: "return 1 + 2 * 3 - 4 / 2 % 3",
+			result: "5",
+			found:  true,
+		},
+		"unary operators": {
+			source: "return -(2 + 3) .. tostring(not nil)",
+			result: "-5true",
+			found:  true,
+		},
+		"concatenation with arguments": {
+			source: `local name = ARGV[1] return "hello, " .. name .. "!"`,
+			args:   []string{"world"},
+			result: "hello, world!",
+			found:  true,
+		},
+		"missing argument is nil": {
+			source: "return ARGV[2]",
+			args:   []string{"value"},
+		},
+		"conditional set": {
+			source: `
+				-- set b only if a is greater than 5
+				if get(KEYS[1]) > 5 then
+					set(KEYS[2], ARGV[1])
+					return "updated"
+				elseif exists(KEYS[2]) then
+					return "kept"
+				else
+					return nil
+				end`,
+			keys:     []string{"a", "b"},
+			args:     []string{"value"},
+			data:     mapStorage{"a": "10"},
+			result:   "updated",
+			found:    true,
+			expected: mapStorage{"a": "10", "b": "value"},
+		},
+		"while loop": {
+			source: `
+				local counter = 0
+				while counter < 5 do
+					counter = counter + 1
+					incrby(KEYS[1], 2)
+				end
+				return get(KEYS[1])`,
+			keys:     []string{"counter"},
+			data:     mapStorage{},
+			result:   "10",
+			found:    true,
+			expected: mapStorage{"counter": "10"},
+		},
+		"logical operators return operands": {
+			source: `return get(KEYS[1]) or "default"`,
+			keys:   []string{"missing"},
+			data:   mapStorage{},
+			result: "default",
+			found:  true,
+		},
+		"delete key": {
+			source:   "return tostring(del(KEYS[1])) .. tostring(del(KEYS[1]))",
+			keys:     []string{"key"},
+			data:     mapStorage{"key": "value"},
+			result:   "truefalse",
+			found:    true,
+			expected: mapStorage{},
+		},
+		"string comparison": {
+			source: `return "abc" < "abd" and tonumber("x") == nil`,
+			result: "true",
+			found:  true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := Parse(test.source)
+			require.NoError(t, err)
+
+			result, found, err := parsed.Run(test.data, test.keys, test.args, 1000)
+			require.NoError(t, err)
+			require.Equal(t, test.result, result)
+			require.Equal(t, test.found, found)
+
+			if test.expected != nil {
+				require.Equal(t, test.expected, test.data)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	sources := map[string]string{
+		"unknown function":    "os_execute()",
+		"unfinished if":       "if true then return 1",
+		"missing then":        "if true return 1 end",
+		"unfinished string":   `return "value`,
+		"unexpected symbol":   "return 1 & 2",
+		"assignment to KEYS":  "KEYS = 1",
+		"dangling operator":   "return 1 +",
+		"unexpected keyword":  "end",
+		"malformed number":    "return 1.2.3",
+		"missing parenthesis": "return (1 + 2",
+	}
+
+	for name, source := range sources {
+		source := source
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := Parse(source)
+			require.Error(t, err)
+			require.Nil(t, parsed)
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		source string
+		err    error
+	}{
+		"infinite loop": {
+			source: "while true do end",
+			err:    ErrStepBudgetExceeded,
+		},
+		"undeclared key": {
+			source: `return get("other")`,
+			err:    ErrUndeclaredKey,
+		},
+		"arithmetic on nil": {
+			source: "return get(KEYS[1]) + 1",
+		},
+		"comparison of nil": {
+			source: "return get(KEYS[1]) > 5",
+		},
+		"wrong number of arguments": {
+			source: "set(KEYS[1])",
+		},
+		"set nil value": {
+			source: "set(KEYS[1], nil)",
+		},
+		"incrby overflow": {
+			source: `set(KEYS[1], "9223372036854775807") incrby(KEYS[1], 1)`,
+		},
+		"incrby delta out of range": {
+			source: "incrby(KEYS[1], 9223372036854775808)",
+		},
+		"doubling string": {
+			source: `local s = "x" while true do s = s .. s end`,
+			err:    ErrSizeBudgetExceeded,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := Parse(test.source)
+			require.NoError(t, err)
+
+			_, _, err = parsed.Run(mapStorage{}, []string{"key"}, nil, 1000)
+			require.Error(t, err)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+}
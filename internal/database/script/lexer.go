@@ -0,0 +1,167 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	eofToken = iota
+	numberToken
+	stringToken
+	identifierToken
+	keywordToken
+	symbolToken
+)
+
+var keywords = map[string]struct{}{
+	"if":     {},
+	"then":   {},
+	"elseif": {},
+	"else":   {},
+	"end":    {},
+	"while":  {},
+	"do":     {},
+	"local":  {},
+	"return": {},
+	"and":    {},
+	"or":     {},
+	"not":    {},
+	"nil":    {},
+	"true":   {},
+	"false":  {},
+}
+
+// symbols are ordered, so two-character symbols are matched first
+var symbols = []string{
+	"==", "~=", "<=", ">=", "..",
+	"+", "-", "*", "/", "%", "<", ">", "=", "(", ")", "[", "]", ",", ";",
+}
+
+type token struct {
+	kind  int
+	text  string
+	value float64
+	line  int
+}
+
+func (t token) is(kind int, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) String() string {
+	if t.kind == eofToken {
+		return "end of script"
+	}
+
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// tokenize splits the source into tokens, comments
+// start with "--" and last until the end of the line
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	line := 1
+
+	for idx := 0; idx < len(source); {
+		symbol := source[idx]
+		switch {
+		case symbol == '\n':
+			line++
+			idx++
+		case symbol == ' ' || symbol == '\t' || symbol == '\r':
+			idx++
+		case strings.HasPrefix(source[idx:], "--"):
+			for idx < len(source) && source[idx] != '\n' {
+				idx++
+			}
+		case isDigit(symbol):
+			start := idx
+			for idx < len(source) && (isDigit(source[idx]) || source[idx] == '.') {
+				idx++
+			}
+
+			value, ok := parseNumber(source[start:idx])
+			if !ok {
+				return nil, fmt.Errorf("line %d: malformed number '%s'", line, source[start:idx])
+			}
+
+			tokens = append(tokens, token{kind: numberToken, text: source[start:idx], value: value, line: line})
+		case symbol == '"' || symbol == '\'':
+			text, next, err := readString(source, idx)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			tokens = append(tokens, token{kind: stringToken, text: text, line: line})
+			idx = next
+		case isIdentifierStart(symbol):
+			start := idx
+			for idx < len(source) && (isIdentifierStart(source[idx]) || isDigit(source[idx])) {
+				idx++
+			}
+
+			kind := identifierToken
+			if _, found := keywords[source[start:idx]]; found {
+				kind = keywordToken
+			}
+
+			tokens = append(tokens, token{kind: kind, text: source[start:idx], line: line})
+		default:
+			matched := false
+			for _, candidate := range symbols {
+				if strings.HasPrefix(source[idx:], candidate) {
+					tokens = append(tokens, token{kind: symbolToken, text: candidate, line: line})
+					idx += len(candidate)
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				return nil, fmt.Errorf("line %d: unexpected symbol '%c'", line, symbol)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: eofToken, line: line}), nil
+}
+
+// readString reads string literal starting with the quote at idx and
+// returns its value with index of the next symbol after the literal
+func readString(source string, idx int) (string, int, error) {
+	quote := source[idx]
+	var sb strings.Builder
+
+	for idx++; idx < len(source); idx++ {
+		symbol := source[idx]
+		switch {
+		case symbol == quote:
+			return sb.String(), idx + 1, nil
+		case symbol == '\n':
+			return "", 0, fmt.Errorf("unfinished string")
+		case symbol == '\\' && idx+1 < len(source):
+			idx++
+			switch source[idx] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(source[idx])
+			}
+		default:
+			sb.WriteByte(symbol)
+		}
+	}
+
+	return "", 0, fmt.Errorf("unfinished string")
+}
+
+func isDigit(symbol byte) bool {
+	return symbol >= '0' && symbol <= '9'
+}
+
+func isIdentifierStart(symbol byte) bool {
+	return (symbol >= 'a' && symbol <= 'z') || (symbol >= 'A' && symbol <= 'Z') || symbol == '_'
+}
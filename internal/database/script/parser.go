@@ -0,0 +1,438 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+type statement interface{}
+
+type assignStatement struct {
+	name  string
+	value expression
+}
+
+type ifStatement struct {
+	conditions []expression
+	blocks     [][]statement
+	otherwise  []statement
+}
+
+type whileStatement struct {
+	condition expression
+	body      []statement
+}
+
+type returnStatement struct {
+	value expression
+}
+
+type callStatement struct {
+	call *callExpression
+}
+
+type expression interface{}
+
+type literalExpression struct {
+	value interface{}
+}
+
+type variableExpression struct {
+	name string
+}
+
+// indexExpression reads one based element of KEYS or ARGV
+type indexExpression struct {
+	name  string
+	index expression
+}
+
+type callExpression struct {
+	name      string
+	arguments []expression
+}
+
+type unaryExpression struct {
+	operator string
+	operand  expression
+}
+
+type binaryExpression struct {
+	operator    string
+	left, right expression
+}
+
+// binaryPrecedence of operators, concatenation is right associative
+var binaryPrecedence = map[string]int{
+	"or":  1,
+	"and": 2,
+	"==":  3, "~=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"..": 4,
+	"+":  5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+const unaryPrecedence = 7
+
+// Script is a parsed program, which can be run many times
+type Script struct {
+	statements []statement
+}
+
+// Parse parses the source of a script in a small subset of Lua: variables,
+// if/elseif/else, while loops, return, arithmetic, comparisons, string
+// concatenation, KEYS[n], ARGV[n] and calls of builtin functions
+func Parse(source string) (*Script, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	statements, err := p.parseBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != eofToken {
+		return nil, p.unexpected(next)
+	}
+
+	return &Script{statements: statements}, nil
+}
+
+type parser struct {
+	tokens []token
+	idx    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.idx]
+}
+
+func (p *parser) next() token {
+	current := p.tokens[p.idx]
+	if current.kind != eofToken {
+		p.idx++
+	}
+
+	return current
+}
+
+func (p *parser) accept(kind int, text string) bool {
+	if p.peek().is(kind, text) {
+		p.idx++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(kind int, text string) error {
+	if !p.accept(kind, text) {
+		return p.unexpected(p.peek())
+	}
+
+	return nil
+}
+
+func (p *parser) unexpected(current token) error {
+	return fmt.Errorf("line %d: unexpected %s", current.line, current)
+}
+
+// parseBlock parses statements until a keyword closing the block
+func (p *parser) parseBlock() ([]statement, error) {
+	var statements []statement
+	for {
+		current := p.peek()
+		if current.kind == eofToken || current.is(keywordToken, "end") ||
+			current.is(keywordToken, "else") || current.is(keywordToken, "elseif") {
+			return statements, nil
+		}
+
+		if p.accept(symbolToken, ";") {
+			continue
+		}
+
+		parsed, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, parsed)
+	}
+}
+
+func (p *parser) parseStatement() (statement, error) {
+	current := p.next()
+	switch {
+	case current.is(keywordToken, "if"):
+		return p.parseIf()
+	case current.is(keywordToken, "while"):
+		return p.parseWhile()
+	case current.is(keywordToken, "return"):
+		next := p.peek()
+		if next.kind == eofToken || next.is(keywordToken, "end") || next.is(symbolToken, ";") ||
+			next.is(keywordToken, "else") || next.is(keywordToken, "elseif") {
+			return &returnStatement{}, nil
+		}
+
+		value, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+
+		return &returnStatement{value: value}, nil
+	case current.is(keywordToken, "local"):
+		name := p.next()
+		if name.kind != identifierToken {
+			return nil, p.unexpected(name)
+		}
+
+		return p.parseAssignment(name.text)
+	case current.kind == identifierToken:
+		if p.peek().is(symbolToken, "(") {
+			call, err := p.parseCall(current.text)
+			if err != nil {
+				return nil, err
+			}
+
+			return &callStatement{call: call}, nil
+		}
+
+		return p.parseAssignment(current.text)
+	default:
+		return nil, p.unexpected(current)
+	}
+}
+
+func (p *parser) parseAssignment(name string) (statement, error) {
+	if name == "KEYS" || name == "ARGV" {
+		return nil, fmt.Errorf("line %d: %s is read only", p.peek().line, name)
+	}
+
+	if err := p.expect(symbolToken, "="); err != nil {
+		return nil, err
+	}
+
+	value, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &assignStatement{name: name, value: value}, nil
+}
+
+func (p *parser) parseIf() (statement, error) {
+	parsed := &ifStatement{}
+	for {
+		condition, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(keywordToken, "then"); err != nil {
+			return nil, err
+		}
+
+		block, err := p.parseBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		parsed.conditions = append(parsed.conditions, condition)
+		parsed.blocks = append(parsed.blocks, block)
+
+		if !p.accept(keywordToken, "elseif") {
+			break
+		}
+	}
+
+	if p.accept(keywordToken, "else") {
+		otherwise, err := p.parseBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		parsed.otherwise = otherwise
+	}
+
+	if err := p.expect(keywordToken, "end"); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+func (p *parser) parseWhile() (statement, error) {
+	condition, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(keywordToken, "do"); err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(keywordToken, "end"); err != nil {
+		return nil, err
+	}
+
+	return &whileStatement{condition: condition, body: body}, nil
+}
+
+// parseExpression parses binary operators with precedence
+// greater than minPrecedence by precedence climbing
+func (p *parser) parseExpression(minPrecedence int) (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		current := p.peek()
+		if current.kind != symbolToken && current.kind != keywordToken {
+			return left, nil
+		}
+
+		precedence, found := binaryPrecedence[current.text]
+		if !found || precedence <= minPrecedence {
+			return left, nil
+		}
+
+		p.next()
+		nextPrecedence := precedence
+		if current.text == ".." {
+			nextPrecedence--
+		}
+
+		right, err := p.parseExpression(nextPrecedence)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpression{operator: current.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	if p.accept(keywordToken, "not") {
+		operand, err := p.parseExpression(unaryPrecedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryExpression{operator: "not", operand: operand}, nil
+	}
+
+	if p.accept(symbolToken, "-") {
+		operand, err := p.parseExpression(unaryPrecedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryExpression{operator: "-", operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	current := p.next()
+	switch {
+	case current.kind == numberToken:
+		return &literalExpression{value: current.value}, nil
+	case current.kind == stringToken:
+		return &literalExpression{value: current.text}, nil
+	case current.is(keywordToken, "nil"):
+		return &literalExpression{value: nil}, nil
+	case current.is(keywordToken, "true"):
+		return &literalExpression{value: true}, nil
+	case current.is(keywordToken, "false"):
+		return &literalExpression{value: false}, nil
+	case current.is(symbolToken, "("):
+		inner, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(symbolToken, ")"); err != nil {
+			return nil, err
+		}
+
+		return inner, nil
+	case current.kind == identifierToken:
+		if p.peek().is(symbolToken, "(") {
+			return p.parseCall(current.text)
+		}
+
+		if current.text == "KEYS" || current.text == "ARGV" {
+			return p.parseIndex(current.text)
+		}
+
+		return &variableExpression{name: current.text}, nil
+	default:
+		return nil, p.unexpected(current)
+	}
+}
+
+func (p *parser) parseIndex(name string) (expression, error) {
+	if err := p.expect(symbolToken, "["); err != nil {
+		return nil, err
+	}
+
+	index, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(symbolToken, "]"); err != nil {
+		return nil, err
+	}
+
+	return &indexExpression{name: name, index: index}, nil
+}
+
+func (p *parser) parseCall(name string) (*callExpression, error) {
+	line := p.peek().line
+	if _, found := builtins[name]; !found {
+		return nil, fmt.Errorf("line %d: unknown function '%s'", line, name)
+	}
+
+	if err := p.expect(symbolToken, "("); err != nil {
+		return nil, err
+	}
+
+	call := &callExpression{name: name}
+	if p.accept(symbolToken, ")") {
+		return call, nil
+	}
+
+	for {
+		argument, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+
+		call.arguments = append(call.arguments, argument)
+		if p.accept(symbolToken, ")") {
+			return call, nil
+		}
+
+		if err := p.expect(symbolToken, ","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseNumber parses finite decimal numbers only
+func parseNumber(text string) (float64, bool) {
+	value, err := strconv.ParseFloat(text, 64)
+	return value, err == nil && !math.IsInf(value, 0) && !math.IsNaN(value)
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHandleEvalQuery(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	conditionalSet := `"if tonumber(get(KEYS[1])) > 5 then set(KEYS[2], ARGV[1]) return 'updated' end"`
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET a 3"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "EVAL "+conditionalSet+" 2 a b value"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "GET b"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET a 10"))
+	require.Equal(t, "[ok] updated", database.HandleQuery(ctx, "EVAL "+conditionalSet+" 2 a b value"))
+	require.Equal(t, "[ok] value", database.HandleQuery(ctx, "GET b"))
+
+	require.Equal(t, "[ok] 3", database.HandleQuery(ctx, `EVAL "del(KEYS[1]) return incrby(KEYS[2], 3)" 2 a counter`))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "GET a"))
+
+	require.Equal(t, "[error] number of keys is not an integer or out of range", database.HandleQuery(ctx, `EVAL "return 1" 2 a`))
+	require.Equal(t, "[error] number of keys is not an integer or out of range", database.HandleQuery(ctx, `EVAL "return 1" -1`))
+	require.Equal(t, "[error] script accessed undeclared key: b", database.HandleQuery(ctx, `EVAL "return get('b')" 0`))
+	require.Equal(t, "[error] script step budget is exceeded", database.HandleQuery(ctx, `EVAL "while true do set(KEYS[1], 1) end" 1 b`))
+	require.Equal(t, "[error] script string size budget is exceeded", database.HandleQuery(ctx, `EVAL "local s = 'x' while true do s = s .. s end" 0`))
+	require.Equal(t, "[ok] value", database.HandleQuery(ctx, "GET b"))
+
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "HSET hash field value"))
+	wrongType := "[error] WRONGTYPE operation against a key holding the wrong kind of value"
+	require.Equal(t, wrongType, database.HandleQuery(ctx, `EVAL "return get(KEYS[1])" 1 hash`))
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+)
+
+const sessionKey = "database"
+
+// connection is a state holder of a client connection,
+// which is provided by the network layer in the context
+type connection interface {
+	Value(string) (interface{}, bool)
+	SetValue(string, interface{})
+}
+
+// authenticatedConnection keeps the user authenticated
+// by AUTH command until the connection is closed
+type authenticatedConnection interface {
+	User() string
+	SetUser(string)
+}
+
+type session struct {
+	transaction  *storage.Transaction
+	watches      map[string]int64
+	subscription *subscription
+}
+
+func sessionFromContext(ctx context.Context) *session {
+	conn, ok := ctx.Value("session").(connection)
+	if !ok {
+		return nil
+	}
+
+	if value, found := conn.Value(sessionKey); found {
+		return value.(*session)
+	}
+
+	s := &session{}
+	conn.SetValue(sessionKey, s)
+	return s
+}
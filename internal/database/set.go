@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+)
+
+func (d *Database) handleSAddQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	added, err := d.storageLayer.SAdd(ctx, arguments[0], arguments[1:])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", added)
+}
+
+func (d *Database) handleSRemQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	removed, err := d.storageLayer.SRem(ctx, arguments[0], arguments[1:])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", removed)
+}
+
+func (d *Database) handleSIsMemberQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	found, err := d.storageLayer.SIsMember(ctx, arguments[0], arguments[1])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", boolToInt(found))
+}
+
+func (d *Database) handleSCardQuery(ctx context.Context, query compute.Query) string {
+	length, err := d.storageLayer.SCard(ctx, query.Arguments()[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+// handleMembersQuery handles all commands returning members of sets
+func (d *Database) handleMembersQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+
+	var members []string
+	var err error
+	switch query.CommandID() {
+	case compute.SMembersCommandID:
+		members, err = d.storageLayer.SMembers(ctx, arguments[0])
+	case compute.SInterCommandID:
+		members, err = d.storageLayer.SInter(ctx, arguments)
+	case compute.SUnionCommandID:
+		members, err = d.storageLayer.SUnion(ctx, arguments)
+	case compute.SDiffCommandID:
+		members, err = d.storageLayer.SDiff(ctx, arguments)
+	}
+
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if len(members) == 0 {
+		return "[ok]"
+	}
+
+	return fmt.Sprintf("[ok] %s", quoteValues(members))
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHandleSetQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 3", database.HandleQuery(ctx, "SADD tags:1 go redis db"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "SADD tags:1 go cache"))
+	require.Equal(t, "[ok] 2", database.HandleQuery(ctx, "SADD tags:2 go rust"))
+	require.Equal(t, "[ok] 4", database.HandleQuery(ctx, "SCARD tags:1"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "SISMEMBER tags:1 redis"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "SISMEMBER tags:2 redis"))
+	require.Equal(t, `[ok] "cache" "db" "go" "redis"`, database.HandleQuery(ctx, "SMEMBERS tags:1"))
+	require.Equal(t, `[ok] "go"`, database.HandleQuery(ctx, "SINTER tags:1 tags:2"))
+	require.Equal(t, `[ok] "cache" "db" "go" "redis" "rust"`, database.HandleQuery(ctx, "SUNION tags:1 tags:2"))
+	require.Equal(t, `[ok] "cache" "db" "redis"`, database.HandleQuery(ctx, "SDIFF tags:1 tags:2"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SINTER tags:1 tags:3"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "SREM tags:2 rust python"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET key_1 value_1"))
+	wrongType := "[error] WRONGTYPE operation against a key holding the wrong kind of value"
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "SADD key_1 member"))
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "SUNION tags:1 key_1"))
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var errInvalidScoreBound = errors.New("min or max is not a float")
+
+func (d *Database) handleZAddQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	added, err := d.storageLayer.ZAdd(ctx, arguments[0], arguments[1:])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", added)
+}
+
+func (d *Database) handleZRemQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	removed, err := d.storageLayer.ZRem(ctx, arguments[0], arguments[1:])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", removed)
+}
+
+func (d *Database) handleZScoreQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	score, err := d.storageLayer.ZScore(ctx, arguments[0], arguments[1])
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %s", strconv.FormatFloat(score, 'f', -1, 64))
+}
+
+func (d *Database) handleZRankQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	rank, err := d.storageLayer.ZRank(ctx, arguments[0], arguments[1])
+	if errors.Is(err, storage.ErrNotFound) {
+		return "[not_found]"
+	} else if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", rank)
+}
+
+func (d *Database) handleZCardQuery(ctx context.Context, query compute.Query) string {
+	length, err := d.storageLayer.ZCard(ctx, query.Arguments()[0])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return fmt.Sprintf("[ok] %d", length)
+}
+
+func (d *Database) handleZRangeQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	start, stop, err := parseListRange(arguments[1], arguments[2])
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	pairs, err := d.storageLayer.ZRange(ctx, arguments[0], start, stop)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return formatScoredMembers(query, pairs)
+}
+
+func (d *Database) handleZRangeByScoreQuery(ctx context.Context, query compute.Query) string {
+	arguments := query.Arguments()
+	min, minReachable, err := parseScoreBound(arguments[1], math.Inf(1))
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	max, maxReachable, err := parseScoreBound(arguments[2], math.Inf(-1))
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	if !minReachable || !maxReachable {
+		return "[ok]"
+	}
+
+	pairs, err := d.storageLayer.ZRangeByScore(ctx, arguments[0], min, max)
+	if err != nil {
+		return fmt.Sprintf("[error] %s", err.Error())
+	}
+
+	return formatScoredMembers(query, pairs)
+}
+
+// parseScoreBound parses inclusive bound or exclusive one prefixed by "(",
+// which is converted to the closest inclusive bound in the direction,
+// reachable is false if there are no scores beyond the exclusive bound
+func parseScoreBound(bound string, direction float64) (float64, bool, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	score, err := strconv.ParseFloat(strings.TrimPrefix(bound, "("), 64)
+	if err != nil || math.IsNaN(score) {
+		return 0, false, errInvalidScoreBound
+	}
+
+	if !exclusive {
+		return score, true, nil
+	}
+
+	if score == direction {
+		return 0, false, nil
+	}
+
+	return math.Nextafter(score, direction), true, nil
+}
+
+// formatScoredMembers drops scores from members followed
+// by their scores unless WITHSCORES option is set
+func formatScoredMembers(query compute.Query, pairs []string) string {
+	if len(pairs) == 0 {
+		return "[ok]"
+	}
+
+	if _, withScores := query.Option(compute.WithScoresOption); withScores {
+		return fmt.Sprintf("[ok] %s", quoteValues(pairs))
+	}
+
+	members := make([]string, 0, len(pairs)/2)
+	for idx := 0; idx < len(pairs); idx += 2 {
+		members = append(members, pairs[idx])
+	}
+
+	return fmt.Sprintf("[ok] %s", quoteValues(members))
+}
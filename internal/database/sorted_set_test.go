@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHandleSortedSetQueries(t *testing.T) {
+	t.Parallel()
+
+	database := newTestDatabase(t)
+	ctx := context.Background()
+
+	require.Equal(t, "[ok] 3", database.HandleQuery(ctx, "ZADD board 100 alice 85.5 bob 120 carol"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "ZADD board 90 alice 70 dave"))
+	require.Equal(t, "[ok] 4", database.HandleQuery(ctx, "ZCARD board"))
+	require.Equal(t, "[ok] 85.5", database.HandleQuery(ctx, "ZSCORE board bob"))
+	require.Equal(t, "[not_found]", database.HandleQuery(ctx, "ZSCORE board erin"))
+	require.Equal(t, "[ok] 2", database.HandleQuery(ctx, "ZRANK board alice"))
+	require.Equal(t, `[ok] "dave" "bob" "alice" "carol"`, database.HandleQuery(ctx, "ZRANGE board 0 -1"))
+	require.Equal(t, `[ok] "alice" "90" "carol" "120"`, database.HandleQuery(ctx, "ZRANGE board -2 -1 WITHSCORES"))
+	require.Equal(t, `[ok] "bob" "alice"`, database.HandleQuery(ctx, "ZRANGEBYSCORE board 85.5 (120"))
+	require.Equal(t, `[ok] "alice" "carol"`, database.HandleQuery(ctx, "ZRANGEBYSCORE board (85.5 +inf"))
+	require.Equal(t, `[ok] "dave" "70"`, database.HandleQuery(ctx, "ZRANGEBYSCORE board -inf 80 WITHSCORES"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "ZRANGEBYSCORE board 200 300"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "ZADD board +inf erin"))
+	require.Equal(t, `[ok] "erin"`, database.HandleQuery(ctx, "ZRANGEBYSCORE board (120 +inf"))
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "ZRANGEBYSCORE board (+inf +inf"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "ZREM board erin"))
+	require.Equal(t, "[ok] 1", database.HandleQuery(ctx, "ZREM board dave erin"))
+	require.Equal(t, "[ok] 0", database.HandleQuery(ctx, "ZRANK board bob"))
+
+	require.Equal(t, "[error] score is not a valid float", database.HandleQuery(ctx, "ZADD board high erin"))
+	require.Equal(t, "[error] min or max is not a float", database.HandleQuery(ctx, "ZRANGEBYSCORE board (a 10"))
+
+	require.Equal(t, "[ok]", database.HandleQuery(ctx, "SET key_1 value_1"))
+	wrongType := "[error] WRONGTYPE operation against a key holding the wrong kind of value"
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "ZADD key_1 1 member"))
+	require.Equal(t, wrongType, database.HandleQuery(ctx, "ZRANGE key_1 0 -1"))
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// waiter is a client blocked until one of its keys is set, ready is
+// buffered, so notifications never block writers, signaled reports that
+// the waiter has been woken up after the last check of its keys
+type waiter struct {
+	keys     []string
+	ready    chan struct{}
+	signaled bool
+}
+
+// waiters keeps FIFO queue of blocked clients for every key, only the
+// first waiter of the key is woken up by a write and only it is allowed
+// to take the key, so waiters are served in the order they were blocked
+type waiters struct {
+	mutex  sync.Mutex
+	queues map[string][]*waiter
+	count  atomic.Int64
+}
+
+func newWaiters() *waiters {
+	return &waiters{
+		queues: make(map[string][]*waiter),
+	}
+}
+
+func (w *waiters) register(keys []string) *waiter {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	blocked := &waiter{
+		keys:  keys,
+		ready: make(chan struct{}, 1),
+	}
+
+	for _, key := range keys {
+		w.queues[key] = append(w.queues[key], blocked)
+	}
+
+	w.count.Add(1)
+	return blocked
+}
+
+// unregister removes the waiter from all queues, if the waiter has been
+// served or woken up after the last check of its keys, the next waiters
+// of its keys are woken up instead of it, so writes it has been notified
+// about but hasn't consumed aren't lost
+func (w *waiters) unregister(blocked *waiter, served bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, key := range blocked.keys {
+		queue := w.queues[key]
+		for idx, current := range queue {
+			if current == blocked {
+				queue = append(queue[:idx], queue[idx+1:]...)
+				break
+			}
+		}
+
+		if len(queue) == 0 {
+			delete(w.queues, key)
+		} else {
+			w.queues[key] = queue
+		}
+	}
+
+	w.count.Add(-1)
+	if served || blocked.signaled {
+		for _, key := range blocked.keys {
+			w.signal(key)
+		}
+	}
+}
+
+// rearm resets the signal of the waiter before its keys are checked,
+// so the waiter, which has lost the keys to another client, doesn't
+// pass stale notifications to the next waiters
+func (w *waiters) rearm(blocked *waiter) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	blocked.signaled = false
+}
+
+// waiting reports whether any client is blocked by one of the keys
+func (w *waiters) waiting(keys []string) bool {
+	if w.count.Load() == 0 {
+		return false
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, key := range keys {
+		if len(w.queues[key]) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// heads returns keys of the waiter, which it is the first waiter of
+func (w *waiters) heads(blocked *waiter) []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	keys := make([]string, 0, len(blocked.keys))
+	for _, key := range blocked.keys {
+		if queue := w.queues[key]; len(queue) != 0 && queue[0] == blocked {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// notify wakes up the first waiter of the key,
+// must be called after the key has been written
+func (w *waiters) notify(key string) {
+	if w == nil || w.count.Load() == 0 {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.signal(key)
+}
+
+func (w *waiters) signal(key string) {
+	queue := w.queues[key]
+	if len(queue) == 0 {
+		return
+	}
+
+	first := queue[0]
+	first.signaled = true
+	select {
+	case first.ready <- struct{}{}:
+	default:
+	}
+}
+
+// BGetDel deletes the first existing key and returns it with its
+// value, if none of the keys exists, the caller is blocked until one
+// of them is set, timeout passes or context is done, zero timeout
+// means waiting without a limit, the caller is queued behind clients
+// already blocked by the keys even if they exist
+func (s *Storage) BGetDel(ctx context.Context, keys []string, timeout time.Duration) (string, string, error) {
+	if s.stream != nil {
+		return "", "", ErrSlaveWrite
+	}
+
+	if !s.waiters.waiting(keys) {
+		key, value, err := s.getDelFirst(ctx, keys)
+		if !errors.Is(err, ErrNotFound) {
+			return key, value, err
+		}
+	}
+
+	served := false
+	blocked := s.waiters.register(keys)
+	defer func() {
+		s.waiters.unregister(blocked, served)
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		// keys are checked again after registration, so writes
+		// made before it aren't missed, the keys are taken only
+		// when no one else has been blocked by them before
+		s.waiters.rearm(blocked)
+		key, value, err := s.getDelFirst(ctx, s.waiters.heads(blocked))
+		if !errors.Is(err, ErrNotFound) {
+			served = true
+			return key, value, err
+		}
+
+		select {
+		case <-blocked.ready:
+		case <-expired:
+			return "", "", ErrNotFound
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		}
+	}
+}
+
+func (s *Storage) getDelFirst(ctx context.Context, keys []string) (string, string, error) {
+	for _, key := range keys {
+		value, err := s.GetDel(ctx, key)
+		if !errors.Is(err, ErrNotFound) {
+			return key, value, err
+		}
+	}
+
+	return "", "", ErrNotFound
+}
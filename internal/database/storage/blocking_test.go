@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/storage/engine/in_memory"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newBlockingTestStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	engine, err := in_memory.NewEngine(in_memory.HashTableBuilder, 4, zap.NewNop())
+	require.NoError(t, err)
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+	return storage
+}
+
+func waitForWaiters(t *testing.T, storage *Storage, count int64) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return storage.waiters.count.Load() == count
+	}, time.Second, time.Millisecond)
+}
+
+func TestBGetDelExistingKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	storage := newBlockingTestStorage(t)
+	require.NoError(t, storage.Set(ctx, "key_2", "value_2"))
+
+	key, value, err := storage.BGetDel(ctx, []string{"key_1", "key_2"}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "key_2", key)
+	require.Equal(t, "value_2", value)
+
+	_, err = storage.Get(ctx, "key_2")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBGetDelWaitsForWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	storage := newBlockingTestStorage(t)
+
+	type result struct {
+		key   string
+		value string
+		err   error
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		key, value, err := storage.BGetDel(ctx, []string{"key_1", "key_2"}, 0)
+		results <- result{key: key, value: value, err: err}
+	}()
+
+	waitForWaiters(t, storage, 1)
+	_, err := storage.Append(ctx, "key_2", "value_2")
+	require.NoError(t, err)
+
+	received := <-results
+	require.NoError(t, received.err)
+	require.Equal(t, "key_2", received.key)
+	require.Equal(t, "value_2", received.value)
+	waitForWaiters(t, storage, 0)
+}
+
+func TestBGetDelFIFO(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	storage := newBlockingTestStorage(t)
+
+	first := make(chan string, 1)
+	second := make(chan string, 1)
+	go func() {
+		_, value, _ := storage.BGetDel(ctx, []string{"queue"}, 0)
+		first <- value
+	}()
+
+	waitForWaiters(t, storage, 1)
+	go func() {
+		_, value, _ := storage.BGetDel(ctx, []string{"queue"}, 0)
+		second <- value
+	}()
+
+	waitForWaiters(t, storage, 2)
+	require.NoError(t, storage.Set(ctx, "queue", "job_1"))
+	require.Equal(t, "job_1", <-first)
+
+	require.NoError(t, storage.MSet(ctx, []string{"queue", "job_2"}))
+	require.Equal(t, "job_2", <-second)
+}
+
+func TestBGetDelQueuesBehindWaiters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	storage := newBlockingTestStorage(t)
+
+	first := make(chan string, 1)
+	go func() {
+		_, value, _ := storage.BGetDel(ctx, []string{"queue"}, 0)
+		first <- value
+	}()
+
+	waitForWaiters(t, storage, 1)
+
+	// the key is written without waking up the waiter, the new
+	// caller must not take it while the waiter is blocked
+	storage.engine.Set(ctx, "queue", "job_1")
+	_, _, err := storage.BGetDel(ctx, []string{"queue"}, 10*time.Millisecond)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	storage.waiters.notify("queue")
+	require.Equal(t, "job_1", <-first)
+	waitForWaiters(t, storage, 0)
+}
+
+func TestBGetDelCanceledWaiterLeavesValue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	canceledCtx, cancel := context.WithCancel(ctx)
+	storage := newBlockingTestStorage(t)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := storage.BGetDel(canceledCtx, []string{"queue"}, 0)
+		errs <- err
+	}()
+
+	waitForWaiters(t, storage, 1)
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+
+	require.NoError(t, storage.Set(ctx, "queue", "job_1"))
+	value, err := storage.Get(ctx, "queue")
+	require.NoError(t, err)
+	require.Equal(t, "job_1", value)
+}
+
+func TestBGetDelTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	storage := newBlockingTestStorage(t)
+
+	_, _, err := storage.BGetDel(ctx, []string{"key_1"}, 10*time.Millisecond)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, int64(0), storage.waiters.count.Load())
+}
+
+func TestBGetDelCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), "tx", int64(555)))
+	storage := newBlockingTestStorage(t)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := storage.BGetDel(ctx, []string{"key_1"}, 0)
+		errs <- err
+	}()
+
+	waitForWaiters(t, storage, 1)
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestBGetDelPassesNotification(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	storage := newBlockingTestStorage(t)
+
+	first := make(chan string, 1)
+	go func() {
+		key, _, _ := storage.BGetDel(ctx, []string{"key_1", "key_2"}, 0)
+		first <- key
+	}()
+
+	waitForWaiters(t, storage, 1)
+	second := make(chan string, 1)
+	go func() {
+		key, _, _ := storage.BGetDel(ctx, []string{"key_2"}, 0)
+		second <- key
+	}()
+
+	waitForWaiters(t, storage, 2)
+
+	// both writes may wake up the first waiter, which consumes only
+	// one of the keys and must pass the other one to the next waiter
+	tx := storage.Begin()
+	require.NoError(t, tx.MSet(ctx, []string{"key_1", "value_1", "key_2", "value_2"}))
+	require.NoError(t, tx.Commit(ctx))
+
+	require.Equal(t, "key_1", <-first)
+	require.Equal(t, "key_2", <-second)
+}
+
+func TestWaitersRearm(t *testing.T) {
+	t.Parallel()
+
+	woken := func(blocked *waiter) bool {
+		select {
+		case <-blocked.ready:
+			return true
+		default:
+			return false
+		}
+	}
+
+	w := newWaiters()
+	first := w.register([]string{"key"})
+	second := w.register([]string{"key"})
+
+	// the waiter has lost the written key to another client and
+	// is woken up again by the next write after it's rearmed
+	w.notify("key")
+	require.True(t, woken(first))
+	w.rearm(first)
+	w.notify("key")
+	require.True(t, woken(first))
+	require.False(t, woken(second))
+
+	// the consumed notification isn't passed to the next waiter
+	w.rearm(first)
+	w.unregister(first, false)
+	require.False(t, woken(second))
+
+	third := w.register([]string{"key"})
+	w.notify("key")
+	w.unregister(second, false)
+	require.True(t, woken(third))
+
+	fourth := w.register([]string{"key"})
+	w.unregister(third, true)
+	require.True(t, woken(fourth))
+}
@@ -2,15 +2,80 @@ package in_memory
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"go.uber.org/zap"
 	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const maxExpiredKeysPerSweep = 1000
+
+// initialCursor starts and finishes scan iteration
+const initialCursor = "0"
+
+var errInvalidCursor = errors.New("invalid cursor")
+
 type hashTable interface {
 	Set(string, string)
+	SetWithExpiration(string, string, int64)
 	Get(string) (string, bool)
 	Del(string)
+	Expire(string, int64, func() error) (bool, error)
+	Persist(string, func() error) (bool, error)
+	ExpiresAt(string) (int64, bool)
+	DeleteExpired(int) []string
+	CompareAndSet(string, string, string, func() error) (bool, error)
+	SetIf(string, string, int64, bool, func(string, int64) error) (bool, error)
+	Version(string) int64
+	Scan(*string, int) ([]string, bool)
+	Keys(func(string) bool) []string
+	Type(string) string
+	HSet(string, []string, func() error) (int, error)
+	HGet(string, string) (string, bool, error)
+	HDel(string, []string, func() error) (int, error)
+	HGetAll(string) ([]string, error)
+	HLen(string) (int, error)
+	HExists(string, string) (bool, error)
+	HIncrBy(string, string, int64, func(string) error) (int64, error)
+	LPush(string, []string, func() error) (int, error)
+	RPush(string, []string, func() error) (int, error)
+	LPop(string, func() error) (string, bool, error)
+	RPop(string, func() error) (string, bool, error)
+	LLen(string) (int, error)
+	LRange(string, int, int) ([]string, error)
+	LIndex(string, int) (string, bool, error)
+	LTrim(string, int, int, func() error) error
+	SAdd(string, []string, func() error) (int, error)
+	SRem(string, []string, func() error) (int, error)
+	SIsMember(string, string) (bool, error)
+	SMembers(string) ([]string, error)
+	SCard(string) (int, error)
+	ZAdd(string, map[string]float64, func() error) (int, error)
+	ZRem(string, []string, func() error) (int, error)
+	ZScore(string, string) (float64, bool, error)
+	ZRank(string, string) (int, bool, error)
+	ZRange(string, int, int) ([]string, error)
+	ZRangeByScore(string, float64, float64) ([]string, error)
+	ZCard(string) (int, error)
+	IncrBy(string, int64, func(string, int64) error) (int64, error)
+	IncrByFloat(string, float64, func(string, int64) error) (float64, error)
+	Append(string, string, func(string, int64) error) (int, error)
+	SetRange(string, int, string, func(string, int64) error) (int, error)
+	GetSet(string, string, func(string, int64) error) (string, bool, error)
+	GetDel(string, func() error) (string, bool, error)
+
+	lock()
+	unlock()
+	members(string) (map[string]struct{}, error)
+	version(string) int64
+	exists(string) bool
+	expiration(string) int64
+	read(string) (string, bool, error)
+	write(string, *string, int64)
 }
 
 type Engine struct {
@@ -55,6 +120,15 @@ func (e *Engine) Set(ctx context.Context, key, value string) {
 	e.logger.Debug("success set query", zap.Int64("tx", txID))
 }
 
+func (e *Engine) SetWithExpiration(ctx context.Context, key, value string, expiresAt int64) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	partition.SetWithExpiration(key, value, expiresAt)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success set query", zap.Int64("tx", txID))
+}
+
 func (e *Engine) Get(ctx context.Context, key string) (string, bool) {
 	idx := e.partitionIdx(key)
 	partition := e.partitions[idx]
@@ -74,8 +148,695 @@ func (e *Engine) Del(ctx context.Context, key string) {
 	e.logger.Debug("success del query", zap.Int64("tx", txID))
 }
 
+func (e *Engine) Expire(ctx context.Context, key string, expiresAt int64, log func() error) (bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	updated, err := partition.Expire(key, expiresAt, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success expire query", zap.Int64("tx", txID))
+	return updated, err
+}
+
+func (e *Engine) Persist(ctx context.Context, key string, log func() error) (bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	updated, err := partition.Persist(key, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success persist query", zap.Int64("tx", txID))
+	return updated, err
+}
+
+func (e *Engine) ExpiresAt(ctx context.Context, key string) (int64, bool) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	expiresAt, found := partition.ExpiresAt(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success ttl query", zap.Int64("tx", txID))
+	return expiresAt, found
+}
+
+func (e *Engine) CompareAndSet(ctx context.Context, key, expected, value string, log func() error) (bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	updated, err := partition.CompareAndSet(key, expected, value, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success cas query", zap.Int64("tx", txID))
+	return updated, err
+}
+
+func (e *Engine) IncrBy(ctx context.Context, key string, delta int64, log func(string, int64) error) (int64, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, err := partition.IncrBy(key, delta, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success incrby query", zap.Int64("tx", txID))
+	return value, err
+}
+
+func (e *Engine) IncrByFloat(ctx context.Context, key string, delta float64, log func(string, int64) error) (float64, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, err := partition.IncrByFloat(key, delta, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success incrbyfloat query", zap.Int64("tx", txID))
+	return value, err
+}
+
+func (e *Engine) Append(ctx context.Context, key, value string, log func(string, int64) error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.Append(key, value, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success append query", zap.Int64("tx", txID))
+	return length, err
+}
+
+func (e *Engine) SetRange(ctx context.Context, key string, offset int, value string, log func(string, int64) error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.SetRange(key, offset, value, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success setrange query", zap.Int64("tx", txID))
+	return length, err
+}
+
+func (e *Engine) GetSet(ctx context.Context, key, value string, log func(string, int64) error) (string, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	previous, found, err := partition.GetSet(key, value, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success getset query", zap.Int64("tx", txID))
+	return previous, found, err
+}
+
+func (e *Engine) GetDel(ctx context.Context, key string, log func() error) (string, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, found, err := partition.GetDel(key, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success getdel query", zap.Int64("tx", txID))
+	return value, found, err
+}
+
+func (e *Engine) SetIf(
+	ctx context.Context,
+	key, value string,
+	expiresAt int64,
+	exists bool,
+	log func(string, int64) error,
+) (bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	updated, err := partition.SetIf(key, value, expiresAt, exists, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success set query", zap.Int64("tx", txID))
+	return updated, err
+}
+
+func (e *Engine) Version(ctx context.Context, key string) int64 {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	version := partition.Version(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success watch query", zap.Int64("tx", txID))
+	return version
+}
+
+// Commit applies values (nil value means deletion) only if versions of the
+// watched keys are not changed, partitions of all keys are locked in ascending
+// order to avoid deadlocks and log is called before the values are applied,
+// keys deleted by the commit, which have existed, are returned
+func (e *Engine) Commit(
+	ctx context.Context,
+	watches map[string]int64,
+	values map[string]*string,
+	expirations map[string]int64,
+	log func() error,
+) (bool, []string, error) {
+	keys := make([]string, 0, len(watches)+len(values))
+	for key := range watches {
+		keys = append(keys, key)
+	}
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	unlock := e.lockPartitions(keys)
+	defer unlock()
+
+	txID := ctx.Value("tx").(int64)
+	for key, version := range watches {
+		if e.partitions[e.partitionIdx(key)].version(key) != version {
+			e.logger.Debug("watched key changed", zap.Int64("tx", txID), zap.String("key", key))
+			return false, nil, nil
+		}
+	}
+
+	if err := log(); err != nil {
+		return false, nil, err
+	}
+
+	var removed []string
+	for key, value := range values {
+		partition := e.partitions[e.partitionIdx(key)]
+		if value == nil && partition.exists(key) {
+			removed = append(removed, key)
+		}
+
+		partition.write(key, value, expirations[key])
+	}
+
+	e.logger.Debug("success commit query", zap.Int64("tx", txID))
+	return true, removed, nil
+}
+
+// Execute locks partitions of the keys for the duration of run, which
+// reads string values of the keys with their expiration times without
+// locking and returns values to be written (nil value means deletion)
+// with their expiration times, writes are applied only if run succeeds,
+// so it must log them before returning
+func (e *Engine) Execute(
+	ctx context.Context,
+	keys []string,
+	run func(read func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error),
+) error {
+	unlock := e.lockPartitions(keys)
+	defer unlock()
+
+	values, expirations, err := run(func(key string) (string, int64, bool, error) {
+		partition := e.partitions[e.partitionIdx(key)]
+		value, found, err := partition.read(key)
+		if !found || err != nil {
+			return "", 0, found, err
+		}
+
+		return value, partition.expiration(key), true, nil
+	})
+
+	txID := ctx.Value("tx").(int64)
+	if err != nil {
+		e.logger.Debug("failed execute query", zap.Int64("tx", txID), zap.Error(err))
+		return err
+	}
+
+	for key, value := range values {
+		e.partitions[e.partitionIdx(key)].write(key, value, expirations[key])
+	}
+
+	e.logger.Debug("success execute query", zap.Int64("tx", txID))
+	return nil
+}
+
+// Scan iterates keys of partitions one by one, so only one partition
+// is locked at a time, count limits number of examined keys and the
+// returned cursor continues iteration or equals to "0" at the end
+func (e *Engine) Scan(ctx context.Context, cursor string, count int, match func(string) bool) ([]string, string, error) {
+	idx, after, err := parseCursor(cursor, len(e.partitions))
+	if err != nil {
+		return nil, "", err
+	}
+
+	txID := ctx.Value("tx").(int64)
+	defer e.logger.Debug("success scan query", zap.Int64("tx", txID))
+
+	result := make([]string, 0)
+	for examined := 0; examined < count; {
+		keys, done := e.partitions[idx].Scan(after, count-examined)
+		examined += len(keys)
+		for _, key := range keys {
+			if match(key) {
+				result = append(result, key)
+			}
+		}
+
+		if !done {
+			return result, formatCursor(idx, &keys[len(keys)-1]), nil
+		}
+
+		idx, after = idx+1, nil
+		if idx == len(e.partitions) {
+			return result, initialCursor, nil
+		}
+	}
+
+	return result, formatCursor(idx, after), nil
+}
+
+// Keys returns sorted keys accepted by match, partitions
+// are locked one by one and never at the same time
+func (e *Engine) Keys(ctx context.Context, match func(string) bool) []string {
+	keys := make([]string, 0)
+	for _, partition := range e.partitions {
+		keys = append(keys, partition.Keys(match)...)
+	}
+
+	sort.Strings(keys)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success keys query", zap.Int64("tx", txID))
+	return keys
+}
+
+func (e *Engine) Type(ctx context.Context, key string) string {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	valueType := partition.Type(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success type query", zap.Int64("tx", txID))
+	return valueType
+}
+
+func (e *Engine) HSet(ctx context.Context, key string, pairs []string, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	added, err := partition.HSet(key, pairs, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success hset query", zap.Int64("tx", txID))
+	return added, err
+}
+
+func (e *Engine) HGet(ctx context.Context, key, field string) (string, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, found, err := partition.HGet(key, field)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success hget query", zap.Int64("tx", txID))
+	return value, found, err
+}
+
+func (e *Engine) HDel(ctx context.Context, key string, fields []string, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	deleted, err := partition.HDel(key, fields, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success hdel query", zap.Int64("tx", txID))
+	return deleted, err
+}
+
+func (e *Engine) HGetAll(ctx context.Context, key string) ([]string, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	pairs, err := partition.HGetAll(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success hgetall query", zap.Int64("tx", txID))
+	return pairs, err
+}
+
+func (e *Engine) HLen(ctx context.Context, key string) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.HLen(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success hlen query", zap.Int64("tx", txID))
+	return length, err
+}
+
+func (e *Engine) HExists(ctx context.Context, key, field string) (bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	found, err := partition.HExists(key, field)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success hexists query", zap.Int64("tx", txID))
+	return found, err
+}
+
+func (e *Engine) HIncrBy(ctx context.Context, key, field string, delta int64, log func(string) error) (int64, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, err := partition.HIncrBy(key, field, delta, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success hincrby query", zap.Int64("tx", txID))
+	return value, err
+}
+
+func (e *Engine) LPush(ctx context.Context, key string, values []string, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.LPush(key, values, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success lpush query", zap.Int64("tx", txID))
+	return length, err
+}
+
+func (e *Engine) RPush(ctx context.Context, key string, values []string, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.RPush(key, values, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success rpush query", zap.Int64("tx", txID))
+	return length, err
+}
+
+func (e *Engine) LPop(ctx context.Context, key string, log func() error) (string, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, found, err := partition.LPop(key, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success lpop query", zap.Int64("tx", txID))
+	return value, found, err
+}
+
+func (e *Engine) RPop(ctx context.Context, key string, log func() error) (string, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, found, err := partition.RPop(key, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success rpop query", zap.Int64("tx", txID))
+	return value, found, err
+}
+
+func (e *Engine) LLen(ctx context.Context, key string) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.LLen(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success llen query", zap.Int64("tx", txID))
+	return length, err
+}
+
+func (e *Engine) LRange(ctx context.Context, key string, start, stop int) ([]string, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	values, err := partition.LRange(key, start, stop)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success lrange query", zap.Int64("tx", txID))
+	return values, err
+}
+
+func (e *Engine) LIndex(ctx context.Context, key string, index int) (string, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	value, found, err := partition.LIndex(key, index)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success lindex query", zap.Int64("tx", txID))
+	return value, found, err
+}
+
+func (e *Engine) LTrim(ctx context.Context, key string, start, stop int, log func() error) error {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	err := partition.LTrim(key, start, stop, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success ltrim query", zap.Int64("tx", txID))
+	return err
+}
+
+func (e *Engine) SAdd(ctx context.Context, key string, members []string, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	added, err := partition.SAdd(key, members, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success sadd query", zap.Int64("tx", txID))
+	return added, err
+}
+
+func (e *Engine) SRem(ctx context.Context, key string, members []string, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	removed, err := partition.SRem(key, members, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success srem query", zap.Int64("tx", txID))
+	return removed, err
+}
+
+func (e *Engine) SIsMember(ctx context.Context, key, member string) (bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	found, err := partition.SIsMember(key, member)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success sismember query", zap.Int64("tx", txID))
+	return found, err
+}
+
+func (e *Engine) SMembers(ctx context.Context, key string) ([]string, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	members, err := partition.SMembers(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success smembers query", zap.Int64("tx", txID))
+	return members, err
+}
+
+func (e *Engine) SCard(ctx context.Context, key string) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.SCard(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success scard query", zap.Int64("tx", txID))
+	return length, err
+}
+
+func (e *Engine) ZAdd(ctx context.Context, key string, scores map[string]float64, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	added, err := partition.ZAdd(key, scores, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success zadd query", zap.Int64("tx", txID))
+	return added, err
+}
+
+func (e *Engine) ZRem(ctx context.Context, key string, members []string, log func() error) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	removed, err := partition.ZRem(key, members, log)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success zrem query", zap.Int64("tx", txID))
+	return removed, err
+}
+
+func (e *Engine) ZScore(ctx context.Context, key, member string) (float64, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	score, found, err := partition.ZScore(key, member)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success zscore query", zap.Int64("tx", txID))
+	return score, found, err
+}
+
+func (e *Engine) ZRank(ctx context.Context, key, member string) (int, bool, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	rank, found, err := partition.ZRank(key, member)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success zrank query", zap.Int64("tx", txID))
+	return rank, found, err
+}
+
+func (e *Engine) ZRange(ctx context.Context, key string, start, stop int) ([]string, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	pairs, err := partition.ZRange(key, start, stop)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success zrange query", zap.Int64("tx", txID))
+	return pairs, err
+}
+
+func (e *Engine) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]string, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	pairs, err := partition.ZRangeByScore(key, min, max)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success zrangebyscore query", zap.Int64("tx", txID))
+	return pairs, err
+}
+
+func (e *Engine) ZCard(ctx context.Context, key string) (int, error) {
+	idx := e.partitionIdx(key)
+	partition := e.partitions[idx]
+	length, err := partition.ZCard(key)
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success zcard query", zap.Int64("tx", txID))
+	return length, err
+}
+
+// SInter returns members existing in all of the sets,
+// missing keys are considered to be empty sets
+func (e *Engine) SInter(ctx context.Context, keys []string) ([]string, error) {
+	return e.combineSets(ctx, "sinter", keys, func(result, set map[string]struct{}) {
+		for member := range result {
+			if _, found := set[member]; !found {
+				delete(result, member)
+			}
+		}
+	})
+}
+
+// SUnion returns members existing in any of the sets
+func (e *Engine) SUnion(ctx context.Context, keys []string) ([]string, error) {
+	return e.combineSets(ctx, "sunion", keys, func(result, set map[string]struct{}) {
+		for member := range set {
+			result[member] = struct{}{}
+		}
+	})
+}
+
+// SDiff returns members of the first set which
+// don't exist in any of the following sets
+func (e *Engine) SDiff(ctx context.Context, keys []string) ([]string, error) {
+	return e.combineSets(ctx, "sdiff", keys, func(result, set map[string]struct{}) {
+		for member := range set {
+			delete(result, member)
+		}
+	})
+}
+
+// combineSets locks partitions of all keys, so the sets are read
+// atomically, and merges every following set into copy of the first one
+func (e *Engine) combineSets(
+	ctx context.Context,
+	name string,
+	keys []string,
+	combine func(map[string]struct{}, map[string]struct{}),
+) ([]string, error) {
+	unlock := e.lockPartitions(keys)
+	defer unlock()
+
+	result := make(map[string]struct{})
+	for idx, key := range keys {
+		set, err := e.partitions[e.partitionIdx(key)].members(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if idx == 0 {
+			for member := range set {
+				result[member] = struct{}{}
+			}
+		} else {
+			combine(result, set)
+		}
+	}
+
+	txID := ctx.Value("tx").(int64)
+	e.logger.Debug("success "+name+" query", zap.Int64("tx", txID))
+	return sortedMembers(result), nil
+}
+
+// StartExpiration runs background sweeper for every partition, expired
+// is called with every deleted key, sweepers are stopped when context is done
+func (e *Engine) StartExpiration(ctx context.Context, interval time.Duration, expired func(string)) {
+	for _, partition := range e.partitions {
+		go func(partition hashTable) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					deleted := partition.DeleteExpired(maxExpiredKeysPerSweep)
+					if len(deleted) != 0 {
+						e.logger.Debug("expired keys deleted", zap.Int("count", len(deleted)))
+					}
+
+					for _, key := range deleted {
+						expired(key)
+					}
+				}
+			}
+		}(partition)
+	}
+}
+
+// lockPartitions locks partitions of the keys in ascending order
+// of their indexes, so concurrent callers can't deadlock each other,
+// the returned function unlocks them in reverse order
+func (e *Engine) lockPartitions(keys []string) func() {
+	indexes := make(map[int]struct{})
+	for _, key := range keys {
+		indexes[e.partitionIdx(key)] = struct{}{}
+	}
+
+	locked := make([]int, 0, len(indexes))
+	for idx := range indexes {
+		locked = append(locked, idx)
+	}
+
+	sort.Ints(locked)
+	for _, idx := range locked {
+		e.partitions[idx].lock()
+	}
+
+	return func() {
+		for i := len(locked) - 1; i >= 0; i-- {
+			e.partitions[locked[i]].unlock()
+		}
+	}
+}
+
 func (e *Engine) partitionIdx(key string) int {
 	hash := fnv.New32a()
 	_, _ = hash.Write([]byte(key))
 	return int(hash.Sum32()) % len(e.partitions)
 }
+
+// parseCursor decodes partition index and the last scanned key of the
+// partition, the key is absent at the beginning of the partition
+func parseCursor(cursor string, partitionsNumber int) (int, *string, error) {
+	encodedIdx, encodedKey, withKey := strings.Cut(cursor, ":")
+	idx, err := strconv.Atoi(encodedIdx)
+	if err != nil || idx < 0 || idx >= partitionsNumber {
+		return 0, nil, errInvalidCursor
+	}
+
+	if !withKey {
+		return idx, nil, nil
+	}
+
+	key, err := hex.DecodeString(encodedKey)
+	if err != nil {
+		return 0, nil, errInvalidCursor
+	}
+
+	after := string(key)
+	return idx, &after, nil
+}
+
+func formatCursor(idx int, after *string) string {
+	if after == nil {
+		return strconv.Itoa(idx)
+	}
+
+	return strconv.Itoa(idx) + ":" + hex.EncodeToString([]byte(*after))
+}
@@ -33,6 +33,36 @@ func (m *MockhashTable) EXPECT() *MockhashTableMockRecorder {
 	return m.recorder
 }
 
+// Append mocks base method.
+func (m *MockhashTable) Append(arg0, arg1 string, arg2 func(string, int64) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockhashTableMockRecorder) Append(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockhashTable)(nil).Append), arg0, arg1, arg2)
+}
+
+// CompareAndSet mocks base method.
+func (m *MockhashTable) CompareAndSet(arg0, arg1, arg2 string, arg3 func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSet", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareAndSet indicates an expected call of CompareAndSet.
+func (mr *MockhashTableMockRecorder) CompareAndSet(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSet", reflect.TypeOf((*MockhashTable)(nil).CompareAndSet), arg0, arg1, arg2, arg3)
+}
+
 // Del mocks base method.
 func (m *MockhashTable) Del(arg0 string) {
 	m.ctrl.T.Helper()
@@ -45,6 +75,50 @@ func (mr *MockhashTableMockRecorder) Del(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockhashTable)(nil).Del), arg0)
 }
 
+// DeleteExpired mocks base method.
+func (m *MockhashTable) DeleteExpired(arg0 int) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", arg0)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockhashTableMockRecorder) DeleteExpired(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockhashTable)(nil).DeleteExpired), arg0)
+}
+
+// Expire mocks base method.
+func (m *MockhashTable) Expire(arg0 string, arg1 int64, arg2 func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockhashTableMockRecorder) Expire(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockhashTable)(nil).Expire), arg0, arg1, arg2)
+}
+
+// ExpiresAt mocks base method.
+func (m *MockhashTable) ExpiresAt(arg0 string) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpiresAt", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// ExpiresAt indicates an expected call of ExpiresAt.
+func (mr *MockhashTableMockRecorder) ExpiresAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpiresAt", reflect.TypeOf((*MockhashTable)(nil).ExpiresAt), arg0)
+}
+
 // Get mocks base method.
 func (m *MockhashTable) Get(arg0 string) (string, bool) {
 	m.ctrl.T.Helper()
@@ -60,6 +134,415 @@ func (mr *MockhashTableMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockhashTable)(nil).Get), arg0)
 }
 
+// GetDel mocks base method.
+func (m *MockhashTable) GetDel(arg0 string, arg1 func() error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDel", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDel indicates an expected call of GetDel.
+func (mr *MockhashTableMockRecorder) GetDel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDel", reflect.TypeOf((*MockhashTable)(nil).GetDel), arg0, arg1)
+}
+
+// GetSet mocks base method.
+func (m *MockhashTable) GetSet(arg0, arg1 string, arg2 func(string, int64) error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSet indicates an expected call of GetSet.
+func (mr *MockhashTableMockRecorder) GetSet(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSet", reflect.TypeOf((*MockhashTable)(nil).GetSet), arg0, arg1, arg2)
+}
+
+// HDel mocks base method.
+func (m *MockhashTable) HDel(arg0 string, arg1 []string, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HDel", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockhashTableMockRecorder) HDel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockhashTable)(nil).HDel), arg0, arg1, arg2)
+}
+
+// HExists mocks base method.
+func (m *MockhashTable) HExists(arg0, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HExists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HExists indicates an expected call of HExists.
+func (mr *MockhashTableMockRecorder) HExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HExists", reflect.TypeOf((*MockhashTable)(nil).HExists), arg0, arg1)
+}
+
+// HGet mocks base method.
+func (m *MockhashTable) HGet(arg0, arg1 string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockhashTableMockRecorder) HGet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockhashTable)(nil).HGet), arg0, arg1)
+}
+
+// HGetAll mocks base method.
+func (m *MockhashTable) HGetAll(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockhashTableMockRecorder) HGetAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockhashTable)(nil).HGetAll), arg0)
+}
+
+// HIncrBy mocks base method.
+func (m *MockhashTable) HIncrBy(arg0, arg1 string, arg2 int64, arg3 func(string) error) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HIncrBy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HIncrBy indicates an expected call of HIncrBy.
+func (mr *MockhashTableMockRecorder) HIncrBy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HIncrBy", reflect.TypeOf((*MockhashTable)(nil).HIncrBy), arg0, arg1, arg2, arg3)
+}
+
+// HLen mocks base method.
+func (m *MockhashTable) HLen(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HLen", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HLen indicates an expected call of HLen.
+func (mr *MockhashTableMockRecorder) HLen(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HLen", reflect.TypeOf((*MockhashTable)(nil).HLen), arg0)
+}
+
+// HSet mocks base method.
+func (m *MockhashTable) HSet(arg0 string, arg1 []string, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockhashTableMockRecorder) HSet(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockhashTable)(nil).HSet), arg0, arg1, arg2)
+}
+
+// IncrBy mocks base method.
+func (m *MockhashTable) IncrBy(arg0 string, arg1 int64, arg2 func(string, int64) error) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockhashTableMockRecorder) IncrBy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockhashTable)(nil).IncrBy), arg0, arg1, arg2)
+}
+
+// IncrByFloat mocks base method.
+func (m *MockhashTable) IncrByFloat(arg0 string, arg1 float64, arg2 func(string, int64) error) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrByFloat", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrByFloat indicates an expected call of IncrByFloat.
+func (mr *MockhashTableMockRecorder) IncrByFloat(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrByFloat", reflect.TypeOf((*MockhashTable)(nil).IncrByFloat), arg0, arg1, arg2)
+}
+
+// Keys mocks base method.
+func (m *MockhashTable) Keys(arg0 func(string) bool) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", arg0)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockhashTableMockRecorder) Keys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockhashTable)(nil).Keys), arg0)
+}
+
+// LIndex mocks base method.
+func (m *MockhashTable) LIndex(arg0 string, arg1 int) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LIndex", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LIndex indicates an expected call of LIndex.
+func (mr *MockhashTableMockRecorder) LIndex(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LIndex", reflect.TypeOf((*MockhashTable)(nil).LIndex), arg0, arg1)
+}
+
+// LLen mocks base method.
+func (m *MockhashTable) LLen(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LLen", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LLen indicates an expected call of LLen.
+func (mr *MockhashTableMockRecorder) LLen(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LLen", reflect.TypeOf((*MockhashTable)(nil).LLen), arg0)
+}
+
+// LPop mocks base method.
+func (m *MockhashTable) LPop(arg0 string, arg1 func() error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPop", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LPop indicates an expected call of LPop.
+func (mr *MockhashTableMockRecorder) LPop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPop", reflect.TypeOf((*MockhashTable)(nil).LPop), arg0, arg1)
+}
+
+// LPush mocks base method.
+func (m *MockhashTable) LPush(arg0 string, arg1 []string, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPush", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockhashTableMockRecorder) LPush(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockhashTable)(nil).LPush), arg0, arg1, arg2)
+}
+
+// LRange mocks base method.
+func (m *MockhashTable) LRange(arg0 string, arg1, arg2 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LRange", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LRange indicates an expected call of LRange.
+func (mr *MockhashTableMockRecorder) LRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LRange", reflect.TypeOf((*MockhashTable)(nil).LRange), arg0, arg1, arg2)
+}
+
+// LTrim mocks base method.
+func (m *MockhashTable) LTrim(arg0 string, arg1, arg2 int, arg3 func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LTrim", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LTrim indicates an expected call of LTrim.
+func (mr *MockhashTableMockRecorder) LTrim(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LTrim", reflect.TypeOf((*MockhashTable)(nil).LTrim), arg0, arg1, arg2, arg3)
+}
+
+// Persist mocks base method.
+func (m *MockhashTable) Persist(arg0 string, arg1 func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persist", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockhashTableMockRecorder) Persist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockhashTable)(nil).Persist), arg0, arg1)
+}
+
+// RPop mocks base method.
+func (m *MockhashTable) RPop(arg0 string, arg1 func() error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPop", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RPop indicates an expected call of RPop.
+func (mr *MockhashTableMockRecorder) RPop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPop", reflect.TypeOf((*MockhashTable)(nil).RPop), arg0, arg1)
+}
+
+// RPush mocks base method.
+func (m *MockhashTable) RPush(arg0 string, arg1 []string, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPush", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RPush indicates an expected call of RPush.
+func (mr *MockhashTableMockRecorder) RPush(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPush", reflect.TypeOf((*MockhashTable)(nil).RPush), arg0, arg1, arg2)
+}
+
+// SAdd mocks base method.
+func (m *MockhashTable) SAdd(arg0 string, arg1 []string, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SAdd", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockhashTableMockRecorder) SAdd(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockhashTable)(nil).SAdd), arg0, arg1, arg2)
+}
+
+// SCard mocks base method.
+func (m *MockhashTable) SCard(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SCard", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SCard indicates an expected call of SCard.
+func (mr *MockhashTableMockRecorder) SCard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SCard", reflect.TypeOf((*MockhashTable)(nil).SCard), arg0)
+}
+
+// SIsMember mocks base method.
+func (m *MockhashTable) SIsMember(arg0, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SIsMember", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SIsMember indicates an expected call of SIsMember.
+func (mr *MockhashTableMockRecorder) SIsMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SIsMember", reflect.TypeOf((*MockhashTable)(nil).SIsMember), arg0, arg1)
+}
+
+// SMembers mocks base method.
+func (m *MockhashTable) SMembers(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockhashTableMockRecorder) SMembers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockhashTable)(nil).SMembers), arg0)
+}
+
+// SRem mocks base method.
+func (m *MockhashTable) SRem(arg0 string, arg1 []string, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SRem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockhashTableMockRecorder) SRem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockhashTable)(nil).SRem), arg0, arg1, arg2)
+}
+
+// Scan mocks base method.
+func (m *MockhashTable) Scan(arg0 *string, arg1 int) ([]string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockhashTableMockRecorder) Scan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockhashTable)(nil).Scan), arg0, arg1)
+}
+
 // Set mocks base method.
 func (m *MockhashTable) Set(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -71,3 +554,289 @@ func (mr *MockhashTableMockRecorder) Set(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockhashTable)(nil).Set), arg0, arg1)
 }
+
+// SetIf mocks base method.
+func (m *MockhashTable) SetIf(arg0, arg1 string, arg2 int64, arg3 bool, arg4 func(string, int64) error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIf", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetIf indicates an expected call of SetIf.
+func (mr *MockhashTableMockRecorder) SetIf(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIf", reflect.TypeOf((*MockhashTable)(nil).SetIf), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SetRange mocks base method.
+func (m *MockhashTable) SetRange(arg0 string, arg1 int, arg2 string, arg3 func(string, int64) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRange indicates an expected call of SetRange.
+func (mr *MockhashTableMockRecorder) SetRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRange", reflect.TypeOf((*MockhashTable)(nil).SetRange), arg0, arg1, arg2, arg3)
+}
+
+// SetWithExpiration mocks base method.
+func (m *MockhashTable) SetWithExpiration(arg0, arg1 string, arg2 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetWithExpiration", arg0, arg1, arg2)
+}
+
+// SetWithExpiration indicates an expected call of SetWithExpiration.
+func (mr *MockhashTableMockRecorder) SetWithExpiration(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithExpiration", reflect.TypeOf((*MockhashTable)(nil).SetWithExpiration), arg0, arg1, arg2)
+}
+
+// Type mocks base method.
+func (m *MockhashTable) Type(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Type", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Type indicates an expected call of Type.
+func (mr *MockhashTableMockRecorder) Type(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Type", reflect.TypeOf((*MockhashTable)(nil).Type), arg0)
+}
+
+// Version mocks base method.
+func (m *MockhashTable) Version(arg0 string) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", arg0)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockhashTableMockRecorder) Version(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockhashTable)(nil).Version), arg0)
+}
+
+// ZAdd mocks base method.
+func (m *MockhashTable) ZAdd(arg0 string, arg1 map[string]float64, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZAdd", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockhashTableMockRecorder) ZAdd(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockhashTable)(nil).ZAdd), arg0, arg1, arg2)
+}
+
+// ZCard mocks base method.
+func (m *MockhashTable) ZCard(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZCard", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZCard indicates an expected call of ZCard.
+func (mr *MockhashTableMockRecorder) ZCard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZCard", reflect.TypeOf((*MockhashTable)(nil).ZCard), arg0)
+}
+
+// ZRange mocks base method.
+func (m *MockhashTable) ZRange(arg0 string, arg1, arg2 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRange", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRange indicates an expected call of ZRange.
+func (mr *MockhashTableMockRecorder) ZRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRange", reflect.TypeOf((*MockhashTable)(nil).ZRange), arg0, arg1, arg2)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockhashTable) ZRangeByScore(arg0 string, arg1, arg2 float64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockhashTableMockRecorder) ZRangeByScore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockhashTable)(nil).ZRangeByScore), arg0, arg1, arg2)
+}
+
+// ZRank mocks base method.
+func (m *MockhashTable) ZRank(arg0, arg1 string) (int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRank", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ZRank indicates an expected call of ZRank.
+func (mr *MockhashTableMockRecorder) ZRank(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRank", reflect.TypeOf((*MockhashTable)(nil).ZRank), arg0, arg1)
+}
+
+// ZRem mocks base method.
+func (m *MockhashTable) ZRem(arg0 string, arg1 []string, arg2 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockhashTableMockRecorder) ZRem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockhashTable)(nil).ZRem), arg0, arg1, arg2)
+}
+
+// ZScore mocks base method.
+func (m *MockhashTable) ZScore(arg0, arg1 string) (float64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZScore", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ZScore indicates an expected call of ZScore.
+func (mr *MockhashTableMockRecorder) ZScore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScore", reflect.TypeOf((*MockhashTable)(nil).ZScore), arg0, arg1)
+}
+
+// lock mocks base method.
+func (m *MockhashTable) lock() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "lock")
+}
+
+// lock indicates an expected call of lock.
+func (mr *MockhashTableMockRecorder) lock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "lock", reflect.TypeOf((*MockhashTable)(nil).lock))
+}
+
+// members mocks base method.
+func (m *MockhashTable) members(arg0 string) (map[string]struct{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "members", arg0)
+	ret0, _ := ret[0].(map[string]struct{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// members indicates an expected call of members.
+func (mr *MockhashTableMockRecorder) members(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "members", reflect.TypeOf((*MockhashTable)(nil).members), arg0)
+}
+
+// exists mocks base method.
+func (m *MockhashTable) exists(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "exists", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// exists indicates an expected call of exists.
+func (mr *MockhashTableMockRecorder) exists(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "exists", reflect.TypeOf((*MockhashTable)(nil).exists), arg0)
+}
+
+// expiration mocks base method.
+func (m *MockhashTable) expiration(arg0 string) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "expiration", arg0)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// expiration indicates an expected call of expiration.
+func (mr *MockhashTableMockRecorder) expiration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "expiration", reflect.TypeOf((*MockhashTable)(nil).expiration), arg0)
+}
+
+// read mocks base method.
+func (m *MockhashTable) read(arg0 string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "read", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// read indicates an expected call of read.
+func (mr *MockhashTableMockRecorder) read(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "read", reflect.TypeOf((*MockhashTable)(nil).read), arg0)
+}
+
+// unlock mocks base method.
+func (m *MockhashTable) unlock() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "unlock")
+}
+
+// unlock indicates an expected call of unlock.
+func (mr *MockhashTableMockRecorder) unlock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "unlock", reflect.TypeOf((*MockhashTable)(nil).unlock))
+}
+
+// version mocks base method.
+func (m *MockhashTable) version(arg0 string) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "version", arg0)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// version indicates an expected call of version.
+func (mr *MockhashTableMockRecorder) version(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "version", reflect.TypeOf((*MockhashTable)(nil).version), arg0)
+}
+
+// write mocks base method.
+func (m *MockhashTable) write(arg0 string, arg1 *string, arg2 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "write", arg0, arg1, arg2)
+}
+
+// write indicates an expected call of write.
+func (mr *MockhashTableMockRecorder) write(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "write", reflect.TypeOf((*MockhashTable)(nil).write), arg0, arg1, arg2)
+}
@@ -2,10 +2,14 @@ package in_memory
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"sync"
 	"testing"
+	"time"
 )
 
 // mockgen -source=engine.go -destination=engine_mock.go -package=in_memory
@@ -95,3 +99,406 @@ func TestDelQuery(t *testing.T) {
 
 	engine.Del(ctx, "key_1")
 }
+
+func TestExpireQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	tableBuilder := func() hashTable {
+		ctrl := gomock.NewController(t)
+		table := NewMockhashTable(ctrl)
+		table.EXPECT().SetWithExpiration("key_1", "value_1", int64(100))
+		table.EXPECT().Expire("key_1", int64(200), gomock.Any()).Return(true, nil)
+		table.EXPECT().ExpiresAt("key_1").Return(int64(200), true)
+		table.EXPECT().Persist("key_1", gomock.Any()).Return(true, nil)
+		return table
+	}
+
+	engine, err := NewEngine(tableBuilder, 1, zap.NewNop())
+	require.NoError(t, err)
+
+	engine.SetWithExpiration(ctx, "key_1", "value_1", 100)
+	updated, err := engine.Expire(ctx, "key_1", 200, func() error { return nil })
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	expiresAt, found := engine.ExpiresAt(ctx, "key_1")
+	require.Equal(t, int64(200), expiresAt)
+	require.True(t, found)
+
+	updated, err = engine.Persist(ctx, "key_1", func() error { return nil })
+	require.NoError(t, err)
+	require.True(t, updated)
+}
+
+func TestStartExpiration(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	table := NewHashTable()
+	table.SetWithExpiration("key_1", "value_1", time.Now().Add(-time.Second).UnixMilli())
+
+	engine, err := NewEngine(func() hashTable { return table }, 1, zap.NewNop())
+	require.NoError(t, err)
+
+	expired := make(chan string, 1)
+	engine.StartExpiration(ctx, time.Millisecond*10, func(key string) {
+		expired <- key
+	})
+
+	require.Eventually(t, func() bool {
+		table.mutex.RLock()
+		defer table.mutex.RUnlock()
+		return len(table.data) == 0
+	}, time.Second, time.Millisecond*10)
+	require.Equal(t, "key_1", <-expired)
+}
+
+func TestCommitQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	engine, err := NewEngine(HashTableBuilder, 8, zap.NewNop())
+	require.NoError(t, err)
+
+	engine.Set(ctx, "key_1", "value_1")
+	engine.Set(ctx, "key_2", "value_2")
+	watches := map[string]int64{
+		"key_1": engine.Version(ctx, "key_1"),
+		"key_3": engine.Version(ctx, "key_3"),
+	}
+
+	value := "new_value"
+	values := map[string]*string{"key_1": &value, "key_2": nil, "key_4": nil}
+	log := func() error { return nil }
+
+	updated, _, err := engine.Commit(ctx, watches, values, nil, func() error {
+		return errors.New("wal error")
+	})
+	require.Error(t, err, "wal error")
+	require.False(t, updated)
+
+	updated, removed, err := engine.Commit(ctx, watches, values, nil, log)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, []string{"key_2"}, removed)
+
+	current, _ := engine.Get(ctx, "key_1")
+	require.Equal(t, "new_value", current)
+	_, found := engine.Get(ctx, "key_2")
+	require.False(t, found)
+
+	updated, _, err = engine.Commit(ctx, watches, values, nil, log)
+	require.NoError(t, err)
+	require.False(t, updated)
+}
+
+func TestExecuteQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	engine, err := NewEngine(HashTableBuilder, 8, zap.NewNop())
+	require.NoError(t, err)
+
+	engine.Set(ctx, "key_1", "value_1")
+	engine.Set(ctx, "key_2", "value_2")
+	_, err = engine.HSet(ctx, "hash", []string{"field", "value"}, func() error { return nil })
+	require.NoError(t, err)
+
+	err = engine.Execute(ctx, []string{"key_1", "hash"}, func(read func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error) {
+		_, _, _, err := read("hash")
+		return nil, nil, err
+	})
+	require.ErrorIs(t, err, ErrWrongType)
+
+	err = engine.Execute(ctx, []string{"key_1", "key_2"}, func(read func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error) {
+		value, _, found, err := read("key_1")
+		require.NoError(t, err)
+		require.True(t, found)
+
+		return map[string]*string{"key_1": nil, "key_2": &value}, nil, errors.New("wal error")
+	})
+	require.Error(t, err)
+	current, _ := engine.Get(ctx, "key_2")
+	require.Equal(t, "value_2", current)
+
+	err = engine.Execute(ctx, []string{"key_1", "key_2"}, func(read func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error) {
+		value, _, _, err := read("key_1")
+		return map[string]*string{"key_1": nil, "key_2": &value}, nil, err
+	})
+	require.NoError(t, err)
+
+	_, found := engine.Get(ctx, "key_1")
+	require.False(t, found)
+	current, _ = engine.Get(ctx, "key_2")
+	require.Equal(t, "value_1", current)
+
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+	engine.SetWithExpiration(ctx, "key_1", "value_1", expiresAt)
+	err = engine.Execute(ctx, []string{"key_1"}, func(read func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error) {
+		value, expiration, _, err := read("key_1")
+		require.Equal(t, expiresAt, expiration)
+
+		value += "_2"
+		return map[string]*string{"key_1": &value}, map[string]int64{"key_1": expiration}, err
+	})
+	require.NoError(t, err)
+
+	current, _ = engine.Get(ctx, "key_1")
+	require.Equal(t, "value_1_2", current)
+	ttl, _ := engine.ExpiresAt(ctx, "key_1")
+	require.Equal(t, expiresAt, ttl)
+}
+
+func TestCompareAndSetQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	tableBuilder := func() hashTable {
+		ctrl := gomock.NewController(t)
+		table := NewMockhashTable(ctrl)
+		table.EXPECT().CompareAndSet("key_1", "value_1", "value_2", gomock.Any()).Return(true, nil)
+		return table
+	}
+
+	engine, err := NewEngine(tableBuilder, 1, zap.NewNop())
+	require.NoError(t, err)
+
+	updated, err := engine.CompareAndSet(ctx, "key_1", "value_1", "value_2", func() error { return nil })
+	require.NoError(t, err)
+	require.True(t, updated)
+}
+
+func TestScanQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	engine, err := NewEngine(HashTableBuilder, 4, zap.NewNop())
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		engine.Set(ctx, fmt.Sprintf("key_%d", i), "value")
+	}
+
+	matchAll := func(string) bool { return true }
+
+	scanned := make(map[string]int)
+	cursor := "0"
+	for iteration := 0; ; iteration++ {
+		var keys []string
+		keys, cursor, err = engine.Scan(ctx, cursor, 7, matchAll)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(keys), 7)
+
+		for _, key := range keys {
+			scanned[key]++
+		}
+
+		engine.Set(ctx, fmt.Sprintf("new_key_%d", iteration), "value")
+		engine.Del(ctx, fmt.Sprintf("new_key_%d", iteration-1))
+		if cursor == "0" {
+			break
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		require.Equal(t, 1, scanned[fmt.Sprintf("key_%d", i)])
+	}
+
+	keys, cursor, err := engine.Scan(ctx, "0", 1000, func(key string) bool { return key == "key_1" })
+	require.NoError(t, err)
+	require.Equal(t, "0", cursor)
+	require.Equal(t, []string{"key_1"}, keys)
+
+	_, _, err = engine.Scan(ctx, "4", 10, matchAll)
+	require.Error(t, err)
+
+	_, _, err = engine.Scan(ctx, "1:zz", 10, matchAll)
+	require.Error(t, err)
+}
+
+func TestKeysQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	engine, err := NewEngine(HashTableBuilder, 4, zap.NewNop())
+	require.NoError(t, err)
+
+	engine.Set(ctx, "key_2", "value")
+	engine.Set(ctx, "key_1", "value")
+	engine.Set(ctx, "other", "value")
+
+	keys := engine.Keys(ctx, func(key string) bool { return key != "other" })
+	require.Equal(t, []string{"key_1", "key_2"}, keys)
+}
+
+func TestHashQueries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	tableBuilder := func() hashTable {
+		ctrl := gomock.NewController(t)
+		table := NewMockhashTable(ctrl)
+		table.EXPECT().HSet("key_1", []string{"field_1", "value_1"}, gomock.Any()).Return(1, nil)
+		table.EXPECT().HGet("key_1", "field_1").Return("value_1", true, nil)
+		table.EXPECT().Type("key_1").Return(hashType)
+		return table
+	}
+
+	engine, err := NewEngine(tableBuilder, 1, zap.NewNop())
+	require.NoError(t, err)
+
+	added, err := engine.HSet(ctx, "key_1", []string{"field_1", "value_1"}, func() error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+
+	value, found, err := engine.HGet(ctx, "key_1", "field_1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "value_1", value)
+
+	require.Equal(t, hashType, engine.Type(ctx, "key_1"))
+}
+
+func TestListQueries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	tableBuilder := func() hashTable {
+		ctrl := gomock.NewController(t)
+		table := NewMockhashTable(ctrl)
+		table.EXPECT().RPush("key_1", []string{"value_1", "value_2"}, gomock.Any()).Return(2, nil)
+		table.EXPECT().LPop("key_1", gomock.Any()).Return("value_1", true, nil)
+		table.EXPECT().LRange("key_1", 0, -1).Return([]string{"value_2"}, nil)
+		return table
+	}
+
+	engine, err := NewEngine(tableBuilder, 1, zap.NewNop())
+	require.NoError(t, err)
+
+	length, err := engine.RPush(ctx, "key_1", []string{"value_1", "value_2"}, func() error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, 2, length)
+
+	value, found, err := engine.LPop(ctx, "key_1", func() error { return nil })
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "value_1", value)
+
+	values, err := engine.LRange(ctx, "key_1", 0, -1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"value_2"}, values)
+}
+
+func TestSetOperations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	log := func() error { return nil }
+
+	engine, err := NewEngine(HashTableBuilder, 8, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = engine.SAdd(ctx, "set_1", []string{"a", "b", "c", "d"}, log)
+	require.NoError(t, err)
+	_, err = engine.SAdd(ctx, "set_2", []string{"b", "c", "e"}, log)
+	require.NoError(t, err)
+	_, err = engine.SAdd(ctx, "set_3", []string{"c", "f"}, log)
+	require.NoError(t, err)
+	engine.Set(ctx, "string", "value")
+
+	members, err := engine.SInter(ctx, []string{"set_1", "set_2", "set_3"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, members)
+
+	members, err = engine.SInter(ctx, []string{"set_1", "missing"})
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	members, err = engine.SUnion(ctx, []string{"set_2", "set_3", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c", "e", "f"}, members)
+
+	members, err = engine.SDiff(ctx, []string{"set_1", "set_2", "set_3"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "d"}, members)
+
+	_, err = engine.SUnion(ctx, []string{"set_1", "string"})
+	require.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestConcurrentSetOperations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	engine, err := NewEngine(HashTableBuilder, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	keys := make([]string, 0, 32)
+	for i := 0; i < 32; i++ {
+		keys = append(keys, fmt.Sprintf("set_%d", i))
+		_, err = engine.SAdd(ctx, keys[i], []string{"member"}, func() error { return nil })
+		require.NoError(t, err)
+	}
+
+	reversed := make([]string, len(keys))
+	for i, key := range keys {
+		reversed[len(keys)-1-i] = key
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		order := keys
+		if i%2 != 0 {
+			order = reversed
+		}
+
+		wg.Add(1)
+		go func(keys []string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				members, err := engine.SInter(ctx, keys)
+				require.NoError(t, err)
+				require.Equal(t, []string{"member"}, members)
+			}
+		}(order)
+	}
+
+	wg.Wait()
+}
+
+func TestSortedSetQueries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	tableBuilder := func() hashTable {
+		ctrl := gomock.NewController(t)
+		table := NewMockhashTable(ctrl)
+		table.EXPECT().ZAdd("key_1", map[string]float64{"member_1": 1.5}, gomock.Any()).Return(1, nil)
+		table.EXPECT().ZRangeByScore("key_1", 1.0, 2.0).Return([]string{"member_1", "1.5"}, nil)
+		return table
+	}
+
+	engine, err := NewEngine(tableBuilder, 1, zap.NewNop())
+	require.NoError(t, err)
+
+	added, err := engine.ZAdd(ctx, "key_1", map[string]float64{"member_1": 1.5}, func() error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+
+	pairs, err := engine.ZRangeByScore(ctx, "key_1", 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"member_1", "1.5"}, pairs)
+}
@@ -0,0 +1,197 @@
+package in_memory
+
+import (
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"sort"
+	"strconv"
+)
+
+// HSet sets fields of the hash stored at key, pairs contains
+// fields followed by values, returns number of the added fields
+func (s *HashTable) HSet(key string, pairs []string, log func() error) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	hash, err := s.hash(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := log(); err != nil {
+		return 0, err
+	}
+
+	if hash == nil {
+		hash = s.createHash(key)
+	}
+
+	added := 0
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		if _, found := hash[pairs[idx]]; !found {
+			added++
+		}
+
+		hash[pairs[idx]] = pairs[idx+1]
+	}
+
+	s.touch(key)
+	return added, nil
+}
+
+func (s *HashTable) HGet(key, field string) (string, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	hash, err := s.hash(key)
+	if err != nil {
+		return "", false, err
+	}
+
+	value, found := hash[field]
+	return value, found, nil
+}
+
+// HDel deletes fields of the hash and returns number of the deleted
+// fields, the hash without fields is deleted with the key
+func (s *HashTable) HDel(key string, fields []string, log func() error) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	hash, err := s.hash(key)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		if _, found := hash[field]; found {
+			deleted[field] = struct{}{}
+		}
+	}
+
+	if len(deleted) == 0 {
+		return 0, nil
+	}
+
+	if err := log(); err != nil {
+		return 0, err
+	}
+
+	for field := range deleted {
+		delete(hash, field)
+	}
+
+	if len(hash) == 0 {
+		s.remove(key)
+	} else {
+		s.touch(key)
+	}
+
+	return len(deleted), nil
+}
+
+// HGetAll returns fields followed by their values sorted by fields
+func (s *HashTable) HGetAll(key string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	hash, err := s.hash(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(hash))
+	for field := range hash {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+	pairs := make([]string, 0, 2*len(fields))
+	for _, field := range fields {
+		pairs = append(pairs, field, hash[field])
+	}
+
+	return pairs, nil
+}
+
+func (s *HashTable) HLen(key string) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	hash, err := s.hash(key)
+	return len(hash), err
+}
+
+func (s *HashTable) HExists(key, field string) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	hash, err := s.hash(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, found := hash[field]
+	return found, nil
+}
+
+// HIncrBy increments integer value of the field by delta, missing field
+// is considered to be zero and log is called with the resulting value
+func (s *HashTable) HIncrBy(key, field string, delta int64, log func(string) error) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	hash, err := s.hash(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var number int64
+	if value, found := hash[field]; found {
+		if number, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return 0, ErrNotInteger
+		}
+	}
+
+	result, ok := tools.AddInt64(number, delta)
+	if !ok {
+		return 0, ErrNotInteger
+	}
+
+	value := strconv.FormatInt(result, 10)
+	if err := log(value); err != nil {
+		return 0, err
+	}
+
+	if hash == nil {
+		hash = s.createHash(key)
+	}
+
+	hash[field] = value
+	s.touch(key)
+	return result, nil
+}
+
+// hash returns the hash stored at key or nil if the
+// key doesn't exist, must be called under the lock
+func (s *HashTable) hash(key string) (map[string]string, error) {
+	switch s.keyType(key) {
+	case "":
+		return nil, nil
+	case hashType:
+		return s.hashes[key], nil
+	default:
+		return nil, ErrWrongType
+	}
+}
+
+func (s *HashTable) createHash(key string) map[string]string {
+	if s.hashes == nil {
+		s.hashes = make(map[string]map[string]string)
+	}
+
+	s.remove(key)
+	hash := make(map[string]string)
+	s.hashes[key] = hash
+	return hash
+}
@@ -1,21 +1,69 @@
 package in_memory
 
 import (
+	"errors"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"math"
+	"strconv"
 	"sync"
+	"time"
+)
+
+var now = time.Now
+
+// maxValueSize limits length of the strings built by APPEND and SETRANGE
+const maxValueSize = 512 << 20
+
+const (
+	stringType    = "string"
+	hashType      = "hash"
+	listType      = "list"
+	setType       = "set"
+	sortedSetType = "zset"
+)
+
+var (
+	ErrNotInteger    = errors.New("value is not an integer or out of range")
+	ErrNotFloat      = errors.New("value is not a valid float")
+	ErrValueTooLarge = errors.New("value exceeds maximum allowed size")
+	ErrWrongType     = errors.New("WRONGTYPE operation against a key holding the wrong kind of value")
 )
 
 var HashTableBuilder = func() hashTable {
 	return NewHashTable()
 }
 
+// HashTable keeps every key in exactly one map of its value type and
+// tracks version of every key, which is changed by each modification
+// of the key and is used to detect concurrent writes, missing keys
+// share the version of the last removal, so a key created and removed
+// again after it has been watched is noticed as changed
 type HashTable struct {
-	mutex sync.RWMutex
-	data  map[string]string
+	mutex       sync.RWMutex
+	data        map[string]string
+	hashes      map[string]map[string]string
+	lists       map[string]*list
+	sets        map[string]map[string]struct{}
+	sortedSets  map[string]*sortedSet
+	expirations map[string]int64
+	versions    map[string]int64
+	revision    int64
+	removed     int64
+	// index keeps the keys with versions in lexicographical
+	// order (all of them have zero score) to be scanned
+	index *skipList
 }
 
 func NewHashTable() *HashTable {
 	return &HashTable{
-		data: make(map[string]string),
+		data:        make(map[string]string),
+		hashes:      make(map[string]map[string]string),
+		lists:       make(map[string]*list),
+		sets:        make(map[string]map[string]struct{}),
+		sortedSets:  make(map[string]*sortedSet),
+		expirations: make(map[string]int64),
+		versions:    make(map[string]int64),
+		index:       newSkipList(),
 	}
 }
 
@@ -23,13 +71,259 @@ func (s *HashTable) Set(key, value string) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.data[key] = value
+	s.storeString(key, value)
+	delete(s.expirations, key)
+	s.touch(key)
+}
+
+func (s *HashTable) SetWithExpiration(key, value string, expiresAt int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.storeString(key, value)
+	s.expirations[key] = expiresAt
+	s.touch(key)
+}
+
+// SetIf sets value of the key only if existence of the key equals to
+// exists, zero expiration time means that the key never expires
+func (s *HashTable) SetIf(key, value string, expiresAt int64, exists bool, log func(string, int64) error) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.exists(key) != exists {
+		return false, nil
+	}
+
+	if err := log(value, expiresAt); err != nil {
+		return false, err
+	}
+
+	s.write(key, &value, expiresAt)
+	return true, nil
+}
+
+// CompareAndSet replaces value of the key only if the current value
+// equals to the expected one, log is called under the lock before
+// the value is replaced and the replacement is canceled by its error
+func (s *HashTable) CompareAndSet(key, expected, value string, log func() error) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if current, found := s.data[key]; !found || current != expected || s.isExpired(key) {
+		return false, nil
+	}
+
+	if err := log(); err != nil {
+		return false, err
+	}
+
+	s.storeString(key, value)
+	delete(s.expirations, key)
+	s.touch(key)
+	return true, nil
+}
+
+// IncrBy increments integer value of the key by delta, missing key
+// is considered to be zero and expiration time of the key is kept
+func (s *HashTable) IncrBy(key string, delta int64, log func(string, int64) error) (int64, error) {
+	var result int64
+	_, err := s.modify(key, log, func(value string, found bool) (string, error) {
+		var number int64
+		if found {
+			var err error
+			if number, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return "", ErrNotInteger
+			}
+		}
+
+		var ok bool
+		if result, ok = tools.AddInt64(number, delta); !ok {
+			return "", ErrNotInteger
+		}
+
+		return strconv.FormatInt(result, 10), nil
+	})
+
+	return result, err
+}
+
+// IncrByFloat increments float value of the key by delta, missing key
+// is considered to be zero and expiration time of the key is kept
+func (s *HashTable) IncrByFloat(key string, delta float64, log func(string, int64) error) (float64, error) {
+	var result float64
+	_, err := s.modify(key, log, func(value string, found bool) (string, error) {
+		var number float64
+		if found {
+			var err error
+			if number, err = strconv.ParseFloat(value, 64); err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+				return "", ErrNotFloat
+			}
+		}
+
+		result = number + delta
+		if math.IsNaN(result) || math.IsInf(result, 0) {
+			return "", ErrNotFloat
+		}
+
+		return strconv.FormatFloat(result, 'f', -1, 64), nil
+	})
+
+	return result, err
+}
+
+// Append appends value to the current value of the key and returns
+// length of the result, expiration time of the key is kept
+func (s *HashTable) Append(key, value string, log func(string, int64) error) (int, error) {
+	result, err := s.modify(key, log, func(current string, _ bool) (string, error) {
+		if len(current)+len(value) > maxValueSize {
+			return "", ErrValueTooLarge
+		}
+
+		return current + value, nil
+	})
+
+	return len(result), err
+}
+
+// SetRange overwrites part of the current value starting at offset,
+// the value is padded with zero bytes if it is shorter than offset
+func (s *HashTable) SetRange(key string, offset int, value string, log func(string, int64) error) (int, error) {
+	if len(value) == 0 {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+
+		current, _, err := s.read(key)
+		return len(current), err
+	}
+
+	result, err := s.modify(key, log, func(current string, _ bool) (string, error) {
+		if offset < 0 || offset > maxValueSize-len(value) {
+			return "", ErrValueTooLarge
+		}
+
+		buffer := []byte(current)
+		if size := offset + len(value); size > len(buffer) {
+			buffer = append(buffer, make([]byte, size-len(buffer))...)
+		}
+
+		copy(buffer[offset:], value)
+		return string(buffer), nil
+	})
+
+	return len(result), err
+}
+
+// GetSet replaces value of the key and returns the previous one,
+// expiration time of the key is removed as by Set
+func (s *HashTable) GetSet(key, value string, log func(string, int64) error) (string, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var previous string
+	found := s.exists(key)
+	if found && s.keyType(key) != stringType {
+		return "", false, ErrWrongType
+	} else if found {
+		previous = s.data[key]
+	}
+
+	if err := log(value, 0); err != nil {
+		return "", false, err
+	}
+
+	s.storeString(key, value)
+	delete(s.expirations, key)
+	s.touch(key)
+	return previous, found, nil
+}
+
+// GetDel deletes the key and returns its value,
+// nothing is logged if the key doesn't exist
+func (s *HashTable) GetDel(key string, log func() error) (string, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch s.keyType(key) {
+	case "":
+		return "", false, nil
+	case stringType:
+	default:
+		return "", false, ErrWrongType
+	}
+
+	if err := log(); err != nil {
+		return "", false, err
+	}
+
+	value := s.data[key]
+	s.remove(key)
+	return value, true, nil
+}
+
+// Scan returns at most count keys following after in lexicographical
+// order starting from the first key if after is nil, done reports that
+// there are no more keys, so keys existing during the whole iteration
+// are returned exactly once regardless of concurrent writes
+func (s *HashTable) Scan(after *string, count int) ([]string, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	node := s.keyIndex().head.next()
+	if after != nil {
+		node = s.keyIndex().firstAfter(*after, 0)
+	}
+
+	keys := make([]string, 0, count)
+	for ; node != nil && len(keys) < count; node = node.next() {
+		if !s.isExpired(node.member) {
+			keys = append(keys, node.member)
+		}
+	}
+
+	return keys, node == nil
+}
+
+// Keys returns all keys accepted by match
+func (s *HashTable) Keys(match func(string) bool) []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	keys := make([]string, 0)
+	s.forEachKey(func(key string) {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	})
+
+	return keys
+}
+
+// Version returns version of the key, which is changed by every write
+// of the key, missing keys have the version of the last removal
+func (s *HashTable) Version(key string) int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.version(key)
+}
+
+// Type returns type of the value stored at key, empty for missing keys
+func (s *HashTable) Type(key string) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.keyType(key)
 }
 
 func (s *HashTable) Get(key string) (string, bool) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
+	if s.isExpired(key) {
+		return "", false
+	}
+
 	value, found := s.data[key]
 	return value, found
 }
@@ -38,5 +332,289 @@ func (s *HashTable) Del(key string) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	s.remove(key)
+}
+
+// Expire sets expiration time of the existing key, the change
+// is logged under the lock, so it's ordered with other writes
+func (s *HashTable) Expire(key string, expiresAt int64, log func() error) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.exists(key) {
+		return false, nil
+	}
+
+	if err := log(); err != nil {
+		return false, err
+	}
+
+	s.expirations[key] = expiresAt
+	s.touch(key)
+	return true, nil
+}
+
+// Persist removes expiration time of the key like Expire sets it
+func (s *HashTable) Persist(key string, log func() error) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.exists(key) {
+		return false, nil
+	}
+
+	if _, found := s.expirations[key]; !found {
+		return false, nil
+	}
+
+	if err := log(); err != nil {
+		return false, err
+	}
+
+	delete(s.expirations, key)
+	s.touch(key)
+	return true, nil
+}
+
+// ExpiresAt returns expiration time of the key in unix milliseconds,
+// zero means that the key never expires
+func (s *HashTable) ExpiresAt(key string) (int64, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if !s.exists(key) {
+		return 0, false
+	}
+
+	return s.expirations[key], true
+}
+
+// DeleteExpired removes at most limit expired keys and
+// returns the removed keys
+func (s *HashTable) DeleteExpired(limit int) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var deleted []string
+	timestamp := now().UnixMilli()
+	for key, expiresAt := range s.expirations {
+		if len(deleted) >= limit {
+			break
+		}
+
+		if expiresAt <= timestamp {
+			s.remove(key)
+			deleted = append(deleted, key)
+		}
+	}
+
+	return deleted
+}
+
+// modify replaces value of the key by the result of update under the lock,
+// log is called with the new value and expiration time before it is stored
+func (s *HashTable) modify(
+	key string,
+	log func(string, int64) error,
+	update func(string, bool) (string, error),
+) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var current string
+	found := s.exists(key)
+	if found && s.keyType(key) != stringType {
+		return "", ErrWrongType
+	} else if found {
+		current = s.data[key]
+	}
+
+	value, err := update(current, found)
+	if err != nil {
+		return "", err
+	}
+
+	var expiresAt int64
+	if found {
+		expiresAt = s.expirations[key]
+	} else {
+		delete(s.expirations, key)
+	}
+
+	if err := log(value, expiresAt); err != nil {
+		return "", err
+	}
+
+	s.storeString(key, value)
+	s.touch(key)
+	return value, nil
+}
+
+func (s *HashTable) lock() {
+	s.mutex.Lock()
+}
+
+func (s *HashTable) unlock() {
+	s.mutex.Unlock()
+}
+
+func (s *HashTable) version(key string) int64 {
+	if !s.exists(key) {
+		return s.removed
+	}
+
+	return s.versions[key]
+}
+
+// expiration must be called under the lock, zero means no expiration
+func (s *HashTable) expiration(key string) int64 {
+	return s.expirations[key]
+}
+
+// read must be called under the lock, it returns string value of the
+// key or ErrWrongType if the key holds a value of another type
+func (s *HashTable) read(key string) (string, bool, error) {
+	switch s.keyType(key) {
+	case "":
+		return "", false, nil
+	case stringType:
+		return s.data[key], true, nil
+	default:
+		return "", false, ErrWrongType
+	}
+}
+
+// write must be called under the lock, nil value means deletion
+// of the key and zero expiration time means no expiration
+func (s *HashTable) write(key string, value *string, expiresAt int64) {
+	if value == nil {
+		s.remove(key)
+		return
+	}
+
+	s.storeString(key, *value)
+	if expiresAt != 0 {
+		s.expirations[key] = expiresAt
+	} else {
+		delete(s.expirations, key)
+	}
+
+	s.touch(key)
+}
+
+func (s *HashTable) touch(key string) {
+	if s.versions == nil {
+		s.versions = make(map[string]int64)
+	}
+
+	if _, found := s.versions[key]; !found {
+		s.keyIndex().insert(key, 0)
+	}
+
+	s.revision++
+	s.versions[key] = s.revision
+}
+
+func (s *HashTable) keyIndex() *skipList {
+	if s.index == nil {
+		s.index = newSkipList()
+	}
+
+	return s.index
+}
+
+// storeString replaces value of any type stored at key by the string
+func (s *HashTable) storeString(key, value string) {
+	delete(s.hashes, key)
+	delete(s.lists, key)
+	delete(s.sets, key)
+	delete(s.sortedSets, key)
+	s.data[key] = value
+}
+
+// remove deletes the key of any type with its expiration time and
+// version, removal of the existing key changes version of missing keys
+func (s *HashTable) remove(key string) {
 	delete(s.data, key)
+	delete(s.hashes, key)
+	delete(s.lists, key)
+	delete(s.sets, key)
+	delete(s.sortedSets, key)
+	delete(s.expirations, key)
+
+	if _, found := s.versions[key]; found {
+		delete(s.versions, key)
+		s.keyIndex().delete(key, 0)
+		s.revision++
+		s.removed = s.revision
+	}
+}
+
+func (s *HashTable) forEachKey(action func(string)) {
+	for key := range s.data {
+		if !s.isExpired(key) {
+			action(key)
+		}
+	}
+
+	for key := range s.hashes {
+		if !s.isExpired(key) {
+			action(key)
+		}
+	}
+
+	for key := range s.lists {
+		if !s.isExpired(key) {
+			action(key)
+		}
+	}
+
+	for key := range s.sets {
+		if !s.isExpired(key) {
+			action(key)
+		}
+	}
+
+	for key := range s.sortedSets {
+		if !s.isExpired(key) {
+			action(key)
+		}
+	}
+}
+
+func (s *HashTable) keyType(key string) string {
+	if s.isExpired(key) {
+		return ""
+	}
+
+	if _, found := s.data[key]; found {
+		return stringType
+	}
+
+	if _, found := s.hashes[key]; found {
+		return hashType
+	}
+
+	if _, found := s.lists[key]; found {
+		return listType
+	}
+
+	if _, found := s.sets[key]; found {
+		return setType
+	}
+
+	if _, found := s.sortedSets[key]; found {
+		return sortedSetType
+	}
+
+	return ""
+}
+
+func (s *HashTable) exists(key string) bool {
+	return s.keyType(key) != ""
+}
+
+func (s *HashTable) isExpired(key string) bool {
+	expiresAt, found := s.expirations[key]
+	return found && expiresAt <= now().UnixMilli()
 }
@@ -1,8 +1,11 @@
 package in_memory
 
 import (
+	"errors"
 	"github.com/stretchr/testify/require"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestCreateStorage(t *testing.T) {
@@ -92,3 +95,425 @@ func TestDel(t *testing.T) {
 		require.True(t, found)
 	})
 }
+
+func TestSetWithExpiration(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	expired := time.Now().Add(-time.Second).UnixMilli()
+	notExpired := time.Now().Add(time.Hour).UnixMilli()
+
+	table.SetWithExpiration("key_1", "value_1", expired)
+	table.SetWithExpiration("key_2", "value_2", notExpired)
+
+	_, found := table.Get("key_1")
+	require.False(t, found)
+
+	value, found := table.Get("key_2")
+	require.Equal(t, "value_2", value)
+	require.True(t, found)
+
+	table.Set("key_2", "new_value")
+	expiresAt, found := table.ExpiresAt("key_2")
+	require.True(t, found)
+	require.Zero(t, expiresAt)
+}
+
+func TestExpire(t *testing.T) {
+	t.Parallel()
+
+	logged := 0
+	log := func() error {
+		logged++
+		return nil
+	}
+
+	table := NewHashTable()
+	table.Set("key_1", "value_1")
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+
+	expire := func(key string, expiresAt int64) bool {
+		updated, err := table.Expire(key, expiresAt, log)
+		require.NoError(t, err)
+		return updated
+	}
+
+	require.False(t, expire("key_2", expiresAt))
+	require.Zero(t, logged)
+	require.True(t, expire("key_1", expiresAt))
+	require.Equal(t, 1, logged)
+
+	value, found := table.ExpiresAt("key_1")
+	require.Equal(t, expiresAt, value)
+	require.True(t, found)
+
+	// failed log keeps the previous expiration time
+	_, err := table.Expire("key_1", expiresAt+1000, func() error { return errors.New("wal error") })
+	require.Error(t, err)
+	value, _ = table.ExpiresAt("key_1")
+	require.Equal(t, expiresAt, value)
+
+	require.True(t, expire("key_1", time.Now().Add(-time.Second).UnixMilli()))
+	_, found = table.ExpiresAt("key_1")
+	require.False(t, found)
+	require.False(t, expire("key_1", expiresAt))
+}
+
+func TestPersist(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	table.Set("key_1", "value_1")
+	table.SetWithExpiration("key_2", "value_2", time.Now().Add(time.Hour).UnixMilli())
+
+	persist := func(key string) bool {
+		updated, err := table.Persist(key, func() error { return nil })
+		require.NoError(t, err)
+		return updated
+	}
+
+	require.False(t, persist("key_1"))
+	require.False(t, persist("key_3"))
+
+	_, err := table.Persist("key_2", func() error { return errors.New("wal error") })
+	require.Error(t, err)
+	require.True(t, persist("key_2"))
+
+	expiresAt, found := table.ExpiresAt("key_2")
+	require.Zero(t, expiresAt)
+	require.True(t, found)
+}
+
+func TestDeleteExpired(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	expired := time.Now().Add(-time.Second).UnixMilli()
+	table.SetWithExpiration("key_1", "value_1", expired)
+	table.SetWithExpiration("key_2", "value_2", expired)
+	table.SetWithExpiration("key_3", "value_3", time.Now().Add(time.Hour).UnixMilli())
+	table.Set("key_4", "value_4")
+
+	first := table.DeleteExpired(1)
+	require.Len(t, first, 1)
+	second := table.DeleteExpired(10)
+	require.Len(t, second, 1)
+	require.Empty(t, table.DeleteExpired(10))
+	require.ElementsMatch(t, []string{"key_1", "key_2"}, append(first, second...))
+
+	require.Equal(t, 2, len(table.data))
+	require.Equal(t, 1, len(table.expirations))
+}
+
+func TestCompareAndSet(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	table.SetWithExpiration("key_1", "value_1", time.Now().Add(time.Hour).UnixMilli())
+
+	logged := 0
+	log := func() error {
+		logged++
+		return nil
+	}
+
+	updated, err := table.CompareAndSet("key_1", "value_2", "new_value", log)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	updated, err = table.CompareAndSet("key_2", "", "new_value", log)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	updated, err = table.CompareAndSet("key_1", "value_1", "new_value", func() error {
+		return errors.New("wal error")
+	})
+	require.Error(t, err, "wal error")
+	require.False(t, updated)
+
+	updated, err = table.CompareAndSet("key_1", "value_1", "new_value", log)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, 1, logged)
+
+	value, found := table.Get("key_1")
+	require.Equal(t, "new_value", value)
+	require.True(t, found)
+
+	expiresAt, _ := table.ExpiresAt("key_1")
+	require.Zero(t, expiresAt)
+}
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	require.Zero(t, table.Version("key_1"))
+
+	table.Set("key_1", "value_1")
+	first := table.Version("key_1")
+	require.NotZero(t, first)
+
+	table.Set("key_2", "value_2")
+	require.Equal(t, first, table.Version("key_1"))
+
+	table.Set("key_1", "value_1")
+	second := table.Version("key_1")
+	require.NotEqual(t, first, second)
+
+	updated, err := table.Expire("key_1", time.Now().Add(time.Hour).UnixMilli(), func() error { return nil })
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.NotEqual(t, second, table.Version("key_1"))
+
+	third := table.Version("key_1")
+	table.Del("key_1")
+	missing := table.Version("key_1")
+	require.NotEqual(t, third, missing)
+	require.Equal(t, missing, table.Version("key_3"))
+
+	// the key created and removed again has another version
+	table.Set("key_1", "value_1")
+	table.Del("key_1")
+	require.NotEqual(t, missing, table.Version("key_1"))
+
+	// removal of missing key changes nothing
+	missing = table.Version("key_1")
+	table.Del("key_1")
+	require.Equal(t, missing, table.Version("key_1"))
+}
+
+func TestIncrBy(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+	table := NewHashTable()
+	table.SetWithExpiration("key_1", "10", expiresAt)
+	table.Set("key_2", "value")
+	table.Set("key_3", "9223372036854775807")
+
+	var logged []string
+	log := func(value string, expiration int64) error {
+		logged = append(logged, value)
+		require.Equal(t, expiresAt, expiration)
+		return nil
+	}
+
+	value, err := table.IncrBy("key_1", -15, log)
+	require.NoError(t, err)
+	require.Equal(t, int64(-5), value)
+	require.Equal(t, []string{"-5"}, logged)
+
+	expiration, _ := table.ExpiresAt("key_1")
+	require.Equal(t, expiresAt, expiration)
+
+	value, err = table.IncrBy("key_4", 3, func(value string, expiration int64) error {
+		require.Zero(t, expiration)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), value)
+
+	_, err = table.IncrBy("key_2", 1, log)
+	require.ErrorIs(t, err, ErrNotInteger)
+
+	_, err = table.IncrBy("key_3", 1, log)
+	require.ErrorIs(t, err, ErrNotInteger)
+
+	_, err = table.IncrBy("key_1", 1, func(string, int64) error {
+		return errors.New("wal error")
+	})
+	require.Error(t, err, "wal error")
+
+	current, _ := table.Get("key_1")
+	require.Equal(t, "-5", current)
+}
+
+func TestIncrByFloat(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	table.Set("key_1", "10.5")
+	table.Set("key_2", "value")
+
+	log := func(string, int64) error { return nil }
+
+	value, err := table.IncrByFloat("key_1", 0.1, log)
+	require.NoError(t, err)
+	require.InDelta(t, 10.6, value, 1e-9)
+
+	current, _ := table.Get("key_1")
+	require.Equal(t, "10.6", current)
+
+	_, err = table.IncrByFloat("key_2", 1, log)
+	require.ErrorIs(t, err, ErrNotFloat)
+}
+
+func TestAppend(t *testing.T) {
+	t.Parallel()
+
+	log := func(string, int64) error { return nil }
+
+	table := NewHashTable()
+	length, err := table.Append("key_1", "hello", log)
+	require.NoError(t, err)
+	require.Equal(t, 5, length)
+
+	length, err = table.Append("key_1", " world", log)
+	require.NoError(t, err)
+	require.Equal(t, 11, length)
+
+	value, _ := table.Get("key_1")
+	require.Equal(t, "hello world", value)
+}
+
+func TestSetRange(t *testing.T) {
+	t.Parallel()
+
+	log := func(string, int64) error { return nil }
+
+	table := NewHashTable()
+	table.Set("key_1", "hello world")
+
+	length, err := table.SetRange("key_1", 6, "redis", log)
+	require.NoError(t, err)
+	require.Equal(t, 11, length)
+
+	value, _ := table.Get("key_1")
+	require.Equal(t, "hello redis", value)
+
+	length, err = table.SetRange("key_2", 2, "ab", log)
+	require.NoError(t, err)
+	require.Equal(t, 4, length)
+
+	value, _ = table.Get("key_2")
+	require.Equal(t, "\x00\x00ab", value)
+
+	length, err = table.SetRange("key_3", 2, "", log)
+	require.NoError(t, err)
+	require.Zero(t, length)
+
+	_, found := table.Get("key_3")
+	require.False(t, found)
+
+	length, err = table.SetRange("key_1", 0, "", log)
+	require.NoError(t, err)
+	require.Equal(t, 11, length)
+
+	_, err = table.HSet("key_4", []string{"field", "value"}, func() error { return nil })
+	require.NoError(t, err)
+	_, err = table.SetRange("key_4", 0, "", log)
+	require.ErrorIs(t, err, ErrWrongType)
+	_, err = table.SetRange("key_4", 0, "a", log)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, err = table.SetRange("key_1", maxValueSize, "a", log)
+	require.ErrorIs(t, err, ErrValueTooLarge)
+
+	_, err = table.SetRange("key_1", math.MaxInt, "a", log)
+	require.ErrorIs(t, err, ErrValueTooLarge)
+}
+
+func TestGetSetAndGetDel(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	table.SetWithExpiration("key_1", "value_1", time.Now().Add(time.Hour).UnixMilli())
+
+	previous, found, err := table.GetSet("key_1", "value_2", func(value string, expiresAt int64) error {
+		require.Equal(t, "value_2", value)
+		require.Zero(t, expiresAt)
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "value_1", previous)
+
+	expiresAt, _ := table.ExpiresAt("key_1")
+	require.Zero(t, expiresAt)
+
+	logged := 0
+	log := func() error {
+		logged++
+		return nil
+	}
+
+	value, found, err := table.GetDel("key_1", log)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "value_2", value)
+
+	_, found, err = table.GetDel("key_1", log)
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Equal(t, 1, logged)
+}
+
+func TestSetIf(t *testing.T) {
+	t.Parallel()
+
+	log := func(string, int64) error { return nil }
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+
+	table := NewHashTable()
+	updated, err := table.SetIf("key_1", "value_1", 0, true, log)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	updated, err = table.SetIf("key_1", "value_1", expiresAt, false, log)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	value, _ := table.ExpiresAt("key_1")
+	require.Equal(t, expiresAt, value)
+
+	updated, err = table.SetIf("key_1", "value_2", 0, false, log)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	updated, err = table.SetIf("key_1", "value_2", 0, true, log)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	current, _ := table.Get("key_1")
+	require.Equal(t, "value_2", current)
+
+	value, _ = table.ExpiresAt("key_1")
+	require.Zero(t, value)
+}
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	table.Set("key_3", "value")
+	table.Set("key_1", "value")
+	table.Set("key_2", "value")
+	table.SetWithExpiration("key_0", "value", time.Now().Add(-time.Second).UnixMilli())
+
+	keys, done := table.Scan(nil, 2)
+	require.Equal(t, []string{"key_1", "key_2"}, keys)
+	require.False(t, done)
+
+	keys, done = table.Scan(&keys[1], 2)
+	require.Equal(t, []string{"key_3"}, keys)
+	require.True(t, done)
+
+	// keys of all types are indexed until they are removed
+	_, err := table.RPush("key_4", []string{"value"}, func() error { return nil })
+	require.NoError(t, err)
+	table.Del("key_1")
+	table.Set("key_2", "new_value")
+
+	keys, done = table.Scan(nil, 10)
+	require.Equal(t, []string{"key_2", "key_3", "key_4"}, keys)
+	require.True(t, done)
+
+	after := "key_2"
+	keys, done = table.Scan(&after, 1)
+	require.Equal(t, []string{"key_3"}, keys)
+	require.False(t, done)
+
+	require.Equal(t, []string{"key_0"}, table.DeleteExpired(10))
+	require.Equal(t, 3, table.index.length)
+}
@@ -0,0 +1,168 @@
+package in_memory
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestHSet(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	table.Set("key_2", "value")
+
+	added, err := table.HSet("key_1", []string{"field_1", "value_1", "field_2", "value_2"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+
+	added, err = table.HSet("key_1", []string{"field_1", "new_value", "field_3", "value_3"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+
+	value, found, err := table.HGet("key_1", "field_1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "new_value", value)
+
+	_, err = table.HSet("key_2", []string{"field", "value"}, log)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, err = table.HSet("key_3", []string{"field", "value"}, func() error {
+		return errors.New("wal error")
+	})
+	require.Error(t, err, "wal error")
+	require.Empty(t, table.Type("key_3"))
+}
+
+func TestHashTypeChecks(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	_, err := table.HSet("key_1", []string{"field", "value"}, log)
+	require.NoError(t, err)
+	require.Equal(t, hashType, table.Type("key_1"))
+
+	_, found := table.Get("key_1")
+	require.False(t, found)
+
+	_, err = table.Append("key_1", "value", func(string, int64) error { return nil })
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, _, err = table.GetDel("key_1", log)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	keys, _ := table.Scan(nil, 10)
+	require.Equal(t, []string{"key_1"}, keys)
+
+	table.Set("key_1", "value")
+	require.Equal(t, stringType, table.Type("key_1"))
+
+	_, err = table.HLen("key_1")
+	require.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestHDel(t *testing.T) {
+	t.Parallel()
+
+	logged := 0
+	log := func() error {
+		logged++
+		return nil
+	}
+
+	table := NewHashTable()
+	_, err := table.HSet("key_1", []string{"field_1", "value_1", "field_2", "value_2"}, log)
+	require.NoError(t, err)
+
+	deleted, err := table.HDel("key_1", []string{"field_1", "field_3"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	deleted, err = table.HDel("key_1", []string{"field_3"}, log)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	require.Equal(t, 2, logged)
+
+	length, err := table.HLen("key_1")
+	require.NoError(t, err)
+	require.Equal(t, 1, length)
+
+	deleted, err = table.HDel("key_1", []string{"field_2"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	require.Empty(t, table.Type("key_1"))
+}
+
+func TestHGetAllAndHExists(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	_, err := table.HSet("key_1", []string{"field_2", "value_2", "field_1", "value_1"}, func() error { return nil })
+	require.NoError(t, err)
+
+	pairs, err := table.HGetAll("key_1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"field_1", "value_1", "field_2", "value_2"}, pairs)
+
+	pairs, err = table.HGetAll("key_2")
+	require.NoError(t, err)
+	require.Empty(t, pairs)
+
+	found, err := table.HExists("key_1", "field_1")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	found, err = table.HExists("key_1", "field_3")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestHIncrBy(t *testing.T) {
+	t.Parallel()
+
+	var logged []string
+	log := func(value string) error {
+		logged = append(logged, value)
+		return nil
+	}
+
+	table := NewHashTable()
+	value, err := table.HIncrBy("key_1", "field_1", 5, log)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+
+	value, err = table.HIncrBy("key_1", "field_1", -7, log)
+	require.NoError(t, err)
+	require.Equal(t, int64(-2), value)
+	require.Equal(t, []string{"5", "-2"}, logged)
+
+	_, err = table.HSet("key_1", []string{"field_2", "value"}, func() error { return nil })
+	require.NoError(t, err)
+
+	_, err = table.HIncrBy("key_1", "field_2", 1, log)
+	require.ErrorIs(t, err, ErrNotInteger)
+}
+
+func TestExpiredHash(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	_, err := table.HSet("key_1", []string{"field", "value"}, func() error { return nil })
+	require.NoError(t, err)
+	updated, err := table.Expire("key_1", time.Now().Add(-time.Second).UnixMilli(), func() error { return nil })
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	_, found, err := table.HGet("key_1", "field")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.Equal(t, []string{"key_1"}, table.DeleteExpired(10))
+	require.Empty(t, table.hashes)
+}
@@ -0,0 +1,269 @@
+package in_memory
+
+// list is a ring buffer, so values are pushed
+// and popped at both ends in amortized O(1)
+type list struct {
+	items []string
+	head  int
+	size  int
+}
+
+func (l *list) len() int {
+	return l.size
+}
+
+func (l *list) at(idx int) string {
+	return l.items[(l.head+idx)%len(l.items)]
+}
+
+func (l *list) pushFront(value string) {
+	l.grow()
+	l.head = (l.head - 1 + len(l.items)) % len(l.items)
+	l.items[l.head] = value
+	l.size++
+}
+
+func (l *list) pushBack(value string) {
+	l.grow()
+	l.items[(l.head+l.size)%len(l.items)] = value
+	l.size++
+}
+
+func (l *list) popFront() string {
+	value := l.items[l.head]
+	l.items[l.head] = ""
+	l.head = (l.head + 1) % len(l.items)
+	l.size--
+	return value
+}
+
+func (l *list) popBack() string {
+	idx := (l.head + l.size - 1) % len(l.items)
+	value := l.items[idx]
+	l.items[idx] = ""
+	l.size--
+	return value
+}
+
+// values returns copy of the values from start to stop inclusive
+func (l *list) values(start, stop int) []string {
+	values := make([]string, 0, stop-start+1)
+	for idx := start; idx <= stop; idx++ {
+		values = append(values, l.at(idx))
+	}
+
+	return values
+}
+
+func (l *list) grow() {
+	if l.size < len(l.items) {
+		return
+	}
+
+	items := make([]string, max(2*len(l.items), 4))
+	for idx := 0; idx < l.size; idx++ {
+		items[idx] = l.at(idx)
+	}
+
+	l.items = items
+	l.head = 0
+}
+
+// LPush inserts values at the head of the list one after
+// another and returns length of the list after insertion
+func (s *HashTable) LPush(key string, values []string, log func() error) (int, error) {
+	return s.push(key, values, log, (*list).pushFront)
+}
+
+// RPush appends values to the tail of the list and
+// returns length of the list after insertion
+func (s *HashTable) RPush(key string, values []string, log func() error) (int, error) {
+	return s.push(key, values, log, (*list).pushBack)
+}
+
+// LPop removes and returns the first value of the list,
+// nothing is logged if the key doesn't exist
+func (s *HashTable) LPop(key string, log func() error) (string, bool, error) {
+	return s.pop(key, log, (*list).popFront)
+}
+
+// RPop removes and returns the last value of the list,
+// nothing is logged if the key doesn't exist
+func (s *HashTable) RPop(key string, log func() error) (string, bool, error) {
+	return s.pop(key, log, (*list).popBack)
+}
+
+func (s *HashTable) LLen(key string) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values, err := s.list(key)
+	if err != nil || values == nil {
+		return 0, err
+	}
+
+	return values.len(), nil
+}
+
+// LRange returns values of the list from start to stop inclusive,
+// negative indexes are counted from the tail of the list
+func (s *HashTable) LRange(key string, start, stop int) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values, err := s.list(key)
+	if err != nil || values == nil {
+		return nil, err
+	}
+
+	start, stop, ok := listRange(start, stop, values.len())
+	if !ok {
+		return nil, nil
+	}
+
+	return values.values(start, stop), nil
+}
+
+// LIndex returns value of the list at index,
+// negative index is counted from the tail of the list
+func (s *HashTable) LIndex(key string, index int) (string, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values, err := s.list(key)
+	if err != nil || values == nil {
+		return "", false, err
+	}
+
+	if index < 0 {
+		index += values.len()
+	}
+
+	if index < 0 || index >= values.len() {
+		return "", false, nil
+	}
+
+	return values.at(index), true, nil
+}
+
+// LTrim keeps only values of the list from start to stop inclusive,
+// the list without values is deleted with the key
+func (s *HashTable) LTrim(key string, start, stop int, log func() error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.list(key)
+	if err != nil || values == nil {
+		return err
+	}
+
+	if err := log(); err != nil {
+		return err
+	}
+
+	start, stop, ok := listRange(start, stop, values.len())
+	if !ok {
+		s.remove(key)
+		return nil
+	}
+
+	kept := values.values(start, stop)
+	*values = list{}
+	for _, value := range kept {
+		values.pushBack(value)
+	}
+
+	s.touch(key)
+	return nil
+}
+
+func (s *HashTable) push(key string, values []string, log func() error, push func(*list, string)) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	target, err := s.list(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := log(); err != nil {
+		return 0, err
+	}
+
+	if target == nil {
+		target = s.createList(key)
+	}
+
+	for _, value := range values {
+		push(target, value)
+	}
+
+	s.touch(key)
+	return target.len(), nil
+}
+
+func (s *HashTable) pop(key string, log func() error, pop func(*list) string) (string, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	target, err := s.list(key)
+	if err != nil || target == nil {
+		return "", false, err
+	}
+
+	if err := log(); err != nil {
+		return "", false, err
+	}
+
+	value := pop(target)
+	if target.len() == 0 {
+		s.remove(key)
+	} else {
+		s.touch(key)
+	}
+
+	return value, true, nil
+}
+
+// list returns the list stored at key or nil if the
+// key doesn't exist, must be called under the lock
+func (s *HashTable) list(key string) (*list, error) {
+	switch s.keyType(key) {
+	case "":
+		return nil, nil
+	case listType:
+		return s.lists[key], nil
+	default:
+		return nil, ErrWrongType
+	}
+}
+
+func (s *HashTable) createList(key string) *list {
+	if s.lists == nil {
+		s.lists = make(map[string]*list)
+	}
+
+	s.remove(key)
+	values := &list{}
+	s.lists[key] = values
+	return values
+}
+
+// listRange converts start and stop which may be negative to indexes
+// of the list with length, ok is false if the range is empty
+func listRange(start, stop, length int) (int, int, bool) {
+	if start < 0 {
+		start = max(start+length, 0)
+	}
+
+	if stop < 0 {
+		stop += length
+	}
+
+	stop = min(stop, length-1)
+	if start > stop {
+		return 0, 0, false
+	}
+
+	return start, stop, true
+}
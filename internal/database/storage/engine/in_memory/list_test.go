@@ -0,0 +1,165 @@
+package in_memory
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPushAndPop(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	table.Set("key_2", "value")
+
+	length, err := table.RPush("key_1", []string{"value_2", "value_3"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 2, length)
+
+	length, err = table.LPush("key_1", []string{"value_1", "value_0"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 4, length)
+	require.Equal(t, listType, table.Type("key_1"))
+
+	values, err := table.LRange("key_1", 0, -1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"value_0", "value_1", "value_2", "value_3"}, values)
+
+	value, found, err := table.LPop("key_1", log)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "value_0", value)
+
+	value, found, err = table.RPop("key_1", log)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "value_3", value)
+
+	_, _, err = table.RPop("key_1", func() error { return errors.New("wal error") })
+	require.Error(t, err, "wal error")
+
+	length, err = table.LLen("key_1")
+	require.NoError(t, err)
+	require.Equal(t, 2, length)
+
+	_, _, err = table.LPop("key_1", log)
+	require.NoError(t, err)
+	_, _, err = table.LPop("key_1", log)
+	require.NoError(t, err)
+	require.Empty(t, table.Type("key_1"))
+
+	_, found, err = table.LPop("key_1", func() error { return errors.New("must not be logged") })
+	require.NoError(t, err)
+	require.False(t, found)
+
+	_, err = table.LPush("key_2", []string{"value"}, log)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, _, err = table.RPop("key_2", log)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, err = table.RPush("key_3", []string{"value"}, func() error { return errors.New("wal error") })
+	require.Error(t, err, "wal error")
+	require.Empty(t, table.Type("key_3"))
+}
+
+func TestListAsQueue(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	for i := 0; i < 100; i++ {
+		_, err := table.LPush("queue", []string{fmt.Sprintf("value_%d", i)}, log)
+		require.NoError(t, err)
+
+		if i%3 == 0 {
+			value, _, err := table.RPop("queue", log)
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprintf("value_%d", i/3), value)
+		}
+	}
+
+	length, err := table.LLen("queue")
+	require.NoError(t, err)
+	require.Equal(t, 66, length)
+
+	value, found, err := table.LIndex("queue", -1)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "value_34", value)
+}
+
+func TestLRangeAndLIndex(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	_, err := table.RPush("key_1", []string{"a", "b", "c", "d"}, func() error { return nil })
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		start  int
+		stop   int
+		values []string
+	}{
+		"whole list":         {start: 0, stop: -1, values: []string{"a", "b", "c", "d"}},
+		"middle":             {start: 1, stop: 2, values: []string{"b", "c"}},
+		"negative indexes":   {start: -3, stop: -2, values: []string{"b", "c"}},
+		"out of range stop":  {start: 2, stop: 100, values: []string{"c", "d"}},
+		"out of range start": {start: -100, stop: 0, values: []string{"a"}},
+		"empty range":        {start: 3, stop: 1},
+		"start after tail":   {start: 10, stop: 20},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			values, err := table.LRange("key_1", test.start, test.stop)
+			require.NoError(t, err)
+			require.Equal(t, test.values, values)
+		})
+	}
+
+	value, found, err := table.LIndex("key_1", -4)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "a", value)
+
+	_, found, err = table.LIndex("key_1", 4)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	values, err := table.LRange("key_2", 0, -1)
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+func TestLTrim(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	_, err := table.RPush("key_1", []string{"a", "b", "c", "d"}, log)
+	require.NoError(t, err)
+
+	err = table.LTrim("key_1", 0, 0, func() error { return errors.New("wal error") })
+	require.Error(t, err, "wal error")
+
+	require.NoError(t, table.LTrim("key_1", 1, -2, log))
+	values, err := table.LRange("key_1", 0, -1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c"}, values)
+
+	length, err := table.RPush("key_1", []string{"e"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 3, length)
+
+	require.NoError(t, table.LTrim("key_1", 5, 10, log))
+	require.Empty(t, table.Type("key_1"))
+
+	require.NoError(t, table.LTrim("key_2", 0, 1, func() error { return errors.New("must not be logged") }))
+}
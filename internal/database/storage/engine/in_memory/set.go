@@ -0,0 +1,141 @@
+package in_memory
+
+import "sort"
+
+// SAdd adds members to the set stored at key
+// and returns number of the added members
+func (s *HashTable) SAdd(key string, members []string, log func() error) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	set, err := s.members(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := log(); err != nil {
+		return 0, err
+	}
+
+	if set == nil {
+		set = s.createSet(key)
+	}
+
+	added := 0
+	for _, member := range members {
+		if _, found := set[member]; !found {
+			set[member] = struct{}{}
+			added++
+		}
+	}
+
+	s.touch(key)
+	return added, nil
+}
+
+// SRem removes members from the set and returns number of the removed
+// members, the set without members is deleted with the key
+func (s *HashTable) SRem(key string, members []string, log func() error) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	set, err := s.members(key)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		if _, found := set[member]; found {
+			removed[member] = struct{}{}
+		}
+	}
+
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	if err := log(); err != nil {
+		return 0, err
+	}
+
+	for member := range removed {
+		delete(set, member)
+	}
+
+	if len(set) == 0 {
+		s.remove(key)
+	} else {
+		s.touch(key)
+	}
+
+	return len(removed), nil
+}
+
+func (s *HashTable) SIsMember(key, member string) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.members(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, found := set[member]
+	return found, nil
+}
+
+// SMembers returns members of the set in lexicographical order
+func (s *HashTable) SMembers(key string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.members(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedMembers(set), nil
+}
+
+func (s *HashTable) SCard(key string) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.members(key)
+	return len(set), err
+}
+
+// members returns the set stored at key or nil if the
+// key doesn't exist, must be called under the lock
+func (s *HashTable) members(key string) (map[string]struct{}, error) {
+	switch s.keyType(key) {
+	case "":
+		return nil, nil
+	case setType:
+		return s.sets[key], nil
+	default:
+		return nil, ErrWrongType
+	}
+}
+
+func (s *HashTable) createSet(key string) map[string]struct{} {
+	if s.sets == nil {
+		s.sets = make(map[string]map[string]struct{})
+	}
+
+	s.remove(key)
+	set := make(map[string]struct{})
+	s.sets[key] = set
+	return set
+}
+
+func sortedMembers(set map[string]struct{}) []string {
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+
+	sort.Strings(members)
+	return members
+}
@@ -0,0 +1,74 @@
+package in_memory
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSAdd(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	table.Set("key_2", "value")
+
+	added, err := table.SAdd("key_1", []string{"b", "a", "b"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+
+	added, err = table.SAdd("key_1", []string{"a", "c"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+	require.Equal(t, setType, table.Type("key_1"))
+
+	members, err := table.SMembers("key_1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, members)
+
+	found, err := table.SIsMember("key_1", "c")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	length, err := table.SCard("key_1")
+	require.NoError(t, err)
+	require.Equal(t, 3, length)
+
+	_, err = table.SAdd("key_2", []string{"a"}, log)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, err = table.SCard("key_2")
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, err = table.SAdd("key_3", []string{"a"}, func() error { return errors.New("wal error") })
+	require.Error(t, err, "wal error")
+	require.Empty(t, table.Type("key_3"))
+}
+
+func TestSRem(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	_, err := table.SAdd("key_1", []string{"a", "b"}, log)
+	require.NoError(t, err)
+
+	removed, err := table.SRem("key_1", []string{"c"}, func() error { return errors.New("must not be logged") })
+	require.NoError(t, err)
+	require.Equal(t, 0, removed)
+
+	removed, err = table.SRem("key_1", []string{"a", "c"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	removed, err = table.SRem("key_1", []string{"b"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	require.Empty(t, table.Type("key_1"))
+
+	members, err := table.SMembers("key_1")
+	require.NoError(t, err)
+	require.Empty(t, members)
+}
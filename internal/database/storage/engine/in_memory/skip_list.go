@@ -0,0 +1,220 @@
+package in_memory
+
+import "math/rand"
+
+const (
+	skipListMaxLevel    = 32
+	skipListProbability = 0.25
+)
+
+type skipListLevel struct {
+	forward *skipListNode
+	// span is number of nodes between the node and the forward one,
+	// which allows to find rank of a node and a node by rank in O(log n)
+	span int
+}
+
+type skipListNode struct {
+	member   string
+	score    float64
+	backward *skipListNode
+	levels   []skipListLevel
+}
+
+// skipList keeps members ordered by score and then by member,
+// so every member has a unique position in the list
+type skipList struct {
+	head   *skipListNode
+	tail   *skipListNode
+	length int
+	level  int
+}
+
+func newSkipList() *skipList {
+	return &skipList{
+		head:  &skipListNode{levels: make([]skipListLevel, skipListMaxLevel)},
+		level: 1,
+	}
+}
+
+func (l *skipList) insert(member string, score float64) {
+	var update [skipListMaxLevel]*skipListNode
+	var rank [skipListMaxLevel]int
+
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		if i != l.level-1 {
+			rank[i] = rank[i+1]
+		}
+
+		for node.levels[i].forward != nil && node.levels[i].forward.before(member, score) {
+			rank[i] += node.levels[i].span
+			node = node.levels[i].forward
+		}
+
+		update[i] = node
+	}
+
+	level := randomSkipListLevel()
+	if level > l.level {
+		for i := l.level; i < level; i++ {
+			update[i] = l.head
+			update[i].levels[i].span = l.length
+		}
+
+		l.level = level
+	}
+
+	node = &skipListNode{
+		member: member,
+		score:  score,
+		levels: make([]skipListLevel, level),
+	}
+
+	for i := 0; i < level; i++ {
+		node.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = node
+		node.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+
+	for i := level; i < l.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != l.head {
+		node.backward = update[0]
+	}
+
+	if node.levels[0].forward != nil {
+		node.levels[0].forward.backward = node
+	} else {
+		l.tail = node
+	}
+
+	l.length++
+}
+
+func (l *skipList) delete(member string, score float64) bool {
+	var update [skipListMaxLevel]*skipListNode
+
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && node.levels[i].forward.before(member, score) {
+			node = node.levels[i].forward
+		}
+
+		update[i] = node
+	}
+
+	node = node.levels[0].forward
+	if node == nil || node.member != member || node.score != score {
+		return false
+	}
+
+	for i := 0; i < l.level; i++ {
+		if update[i].levels[i].forward == node {
+			update[i].levels[i].span += node.levels[i].span - 1
+			update[i].levels[i].forward = node.levels[i].forward
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+
+	if node.levels[0].forward != nil {
+		node.levels[0].forward.backward = node.backward
+	} else {
+		l.tail = node.backward
+	}
+
+	for l.level > 1 && l.head.levels[l.level-1].forward == nil {
+		l.level--
+	}
+
+	l.length--
+	return true
+}
+
+// rank returns zero based position of the member with
+// the score or -1 if there is no such member in the list
+func (l *skipList) rank(member string, score float64) int {
+	rank := 0
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && !node.levels[i].forward.after(member, score) {
+			rank += node.levels[i].span
+			node = node.levels[i].forward
+		}
+
+		if node != l.head && node.member == member {
+			return rank - 1
+		}
+	}
+
+	return -1
+}
+
+// byRank returns node at zero based position or nil if it is out of range
+func (l *skipList) byRank(rank int) *skipListNode {
+	traversed := 0
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && traversed+node.levels[i].span <= rank+1 {
+			traversed += node.levels[i].span
+			node = node.levels[i].forward
+		}
+
+		if traversed == rank+1 {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// firstFrom returns the first node with score not less than
+// min or nil if all of the scores are less than min
+func (l *skipList) firstFrom(min float64) *skipListNode {
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && node.levels[i].forward.score < min {
+			node = node.levels[i].forward
+		}
+	}
+
+	return node.levels[0].forward
+}
+
+// firstAfter returns the first node following the member with
+// the score or nil if there are no such nodes
+func (l *skipList) firstAfter(member string, score float64) *skipListNode {
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && !node.levels[i].forward.after(member, score) {
+			node = node.levels[i].forward
+		}
+	}
+
+	return node.levels[0].forward
+}
+
+func (n *skipListNode) next() *skipListNode {
+	return n.levels[0].forward
+}
+
+func (n *skipListNode) before(member string, score float64) bool {
+	return n.score < score || (n.score == score && n.member < member)
+}
+
+func (n *skipListNode) after(member string, score float64) bool {
+	return n.score > score || (n.score == score && n.member > member)
+}
+
+func randomSkipListLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.Float64() < skipListProbability {
+		level++
+	}
+
+	return level
+}
@@ -0,0 +1,71 @@
+package in_memory
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSkipList(t *testing.T) {
+	t.Parallel()
+
+	list := newSkipList()
+	scores := make(map[string]float64)
+	for i := 0; i < 1000; i++ {
+		member := fmt.Sprintf("member_%d", rand.Intn(300))
+		if score, found := scores[member]; found {
+			require.True(t, list.delete(member, score))
+			delete(scores, member)
+			continue
+		}
+
+		score := float64(rand.Intn(50))
+		list.insert(member, score)
+		scores[member] = score
+	}
+
+	expected := make([]string, 0, len(scores))
+	for member := range scores {
+		expected = append(expected, member)
+	}
+
+	sort.Slice(expected, func(i, j int) bool {
+		left, right := expected[i], expected[j]
+		return scores[left] < scores[right] || (scores[left] == scores[right] && left < right)
+	})
+
+	require.Equal(t, len(expected), list.length)
+	for rank, member := range expected {
+		require.Equal(t, rank, list.rank(member, scores[member]))
+		require.Equal(t, member, list.byRank(rank).member)
+	}
+
+	require.Nil(t, list.byRank(len(expected)))
+	require.Equal(t, -1, list.rank("missing", 0))
+	require.False(t, list.delete("missing", 0))
+
+	if len(expected) != 0 {
+		require.Equal(t, expected[len(expected)-1], list.tail.member)
+	}
+
+	for _, member := range expected {
+		first := sort.Search(len(expected), func(i int) bool {
+			return scores[expected[i]] >= scores[member]
+		})
+
+		require.Equal(t, expected[first], list.firstFrom(scores[member]).member)
+	}
+
+	require.Nil(t, list.firstFrom(100))
+
+	for rank, member := range expected {
+		next := list.firstAfter(member, scores[member])
+		if rank+1 == len(expected) {
+			require.Nil(t, next)
+		} else {
+			require.Equal(t, expected[rank+1], next.member)
+		}
+	}
+}
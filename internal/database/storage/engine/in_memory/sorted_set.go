@@ -0,0 +1,207 @@
+package in_memory
+
+import "strconv"
+
+// sortedSet keeps scores of members for O(1) lookups
+// and the skip list ordered by score for range queries
+type sortedSet struct {
+	scores  map[string]float64
+	ordered *skipList
+}
+
+// ZAdd sets scores of members of the sorted set stored at
+// key and returns number of the added members
+func (s *HashTable) ZAdd(key string, scores map[string]float64, log func() error) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	set, err := s.sortedSet(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := log(); err != nil {
+		return 0, err
+	}
+
+	if set == nil {
+		set = s.createSortedSet(key)
+	}
+
+	added := 0
+	for member, score := range scores {
+		if current, found := set.scores[member]; !found {
+			added++
+		} else if current == score {
+			continue
+		} else {
+			set.ordered.delete(member, current)
+		}
+
+		set.scores[member] = score
+		set.ordered.insert(member, score)
+	}
+
+	s.touch(key)
+	return added, nil
+}
+
+// ZRem removes members from the sorted set and returns number of the
+// removed members, the sorted set without members is deleted with the key
+func (s *HashTable) ZRem(key string, members []string, log func() error) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	set, err := s.sortedSet(key)
+	if err != nil || set == nil {
+		return 0, err
+	}
+
+	removed := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		if _, found := set.scores[member]; found {
+			removed[member] = struct{}{}
+		}
+	}
+
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	if err := log(); err != nil {
+		return 0, err
+	}
+
+	for member := range removed {
+		set.ordered.delete(member, set.scores[member])
+		delete(set.scores, member)
+	}
+
+	if len(set.scores) == 0 {
+		s.remove(key)
+	} else {
+		s.touch(key)
+	}
+
+	return len(removed), nil
+}
+
+func (s *HashTable) ZScore(key, member string) (float64, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.sortedSet(key)
+	if err != nil || set == nil {
+		return 0, false, err
+	}
+
+	score, found := set.scores[member]
+	return score, found, nil
+}
+
+// ZRank returns zero based position of the member ordered by score
+func (s *HashTable) ZRank(key, member string) (int, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.sortedSet(key)
+	if err != nil || set == nil {
+		return 0, false, err
+	}
+
+	score, found := set.scores[member]
+	if !found {
+		return 0, false, nil
+	}
+
+	return set.ordered.rank(member, score), true, nil
+}
+
+// ZRange returns members from start to stop inclusive ordered by score
+// followed by their scores, negative indexes are counted from the tail
+func (s *HashTable) ZRange(key string, start, stop int) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.sortedSet(key)
+	if err != nil || set == nil {
+		return nil, err
+	}
+
+	start, stop, ok := listRange(start, stop, set.ordered.length)
+	if !ok {
+		return nil, nil
+	}
+
+	pairs := make([]string, 0, 2*(stop-start+1))
+	node := set.ordered.byRank(start)
+	for idx := start; idx <= stop; idx++ {
+		pairs = append(pairs, node.member, formatScore(node.score))
+		node = node.next()
+	}
+
+	return pairs, nil
+}
+
+// ZRangeByScore returns members with scores between min and max
+// inclusive ordered by score followed by their scores
+func (s *HashTable) ZRangeByScore(key string, min, max float64) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.sortedSet(key)
+	if err != nil || set == nil {
+		return nil, err
+	}
+
+	var pairs []string
+	for node := set.ordered.firstFrom(min); node != nil && node.score <= max; node = node.next() {
+		pairs = append(pairs, node.member, formatScore(node.score))
+	}
+
+	return pairs, nil
+}
+
+func (s *HashTable) ZCard(key string) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set, err := s.sortedSet(key)
+	if err != nil || set == nil {
+		return 0, err
+	}
+
+	return set.ordered.length, nil
+}
+
+// sortedSet returns the sorted set stored at key or nil if
+// the key doesn't exist, must be called under the lock
+func (s *HashTable) sortedSet(key string) (*sortedSet, error) {
+	switch s.keyType(key) {
+	case "":
+		return nil, nil
+	case sortedSetType:
+		return s.sortedSets[key], nil
+	default:
+		return nil, ErrWrongType
+	}
+}
+
+func (s *HashTable) createSortedSet(key string) *sortedSet {
+	if s.sortedSets == nil {
+		s.sortedSets = make(map[string]*sortedSet)
+	}
+
+	s.remove(key)
+	set := &sortedSet{
+		scores:  make(map[string]float64),
+		ordered: newSkipList(),
+	}
+
+	s.sortedSets[key] = set
+	return set
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
@@ -0,0 +1,120 @@
+package in_memory
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestZAdd(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	table.Set("key_2", "value")
+
+	added, err := table.ZAdd("key_1", map[string]float64{"alice": 10, "bob": 5}, log)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+
+	added, err = table.ZAdd("key_1", map[string]float64{"alice": 1, "carol": 7.5}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+	require.Equal(t, sortedSetType, table.Type("key_1"))
+
+	score, found, err := table.ZScore("key_1", "alice")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 1.0, score)
+
+	rank, found, err := table.ZRank("key_1", "carol")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 2, rank)
+
+	_, found, err = table.ZRank("key_1", "dave")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	length, err := table.ZCard("key_1")
+	require.NoError(t, err)
+	require.Equal(t, 3, length)
+
+	_, err = table.ZAdd("key_2", map[string]float64{"alice": 1}, log)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	_, err = table.ZAdd("key_3", map[string]float64{"alice": 1}, func() error { return errors.New("wal error") })
+	require.Error(t, err, "wal error")
+	require.Empty(t, table.Type("key_3"))
+}
+
+func TestZRange(t *testing.T) {
+	t.Parallel()
+
+	table := NewHashTable()
+	_, err := table.ZAdd("key_1", map[string]float64{
+		"a": 1,
+		"b": 2,
+		"c": 2,
+		"d": 3.5,
+		"e": math.Inf(1),
+	}, func() error { return nil })
+	require.NoError(t, err)
+
+	pairs, err := table.ZRange("key_1", 0, -1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "1", "b", "2", "c", "2", "d", "3.5", "e", "+Inf"}, pairs)
+
+	pairs, err = table.ZRange("key_1", -2, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"d", "3.5", "e", "+Inf"}, pairs)
+
+	pairs, err = table.ZRange("key_1", 3, 1)
+	require.NoError(t, err)
+	require.Empty(t, pairs)
+
+	pairs, err = table.ZRangeByScore("key_1", 2, 3.5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "2", "c", "2", "d", "3.5"}, pairs)
+
+	pairs, err = table.ZRangeByScore("key_1", math.Nextafter(2, math.Inf(1)), math.Inf(1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"d", "3.5", "e", "+Inf"}, pairs)
+
+	pairs, err = table.ZRangeByScore("key_1", 10, 5)
+	require.NoError(t, err)
+	require.Empty(t, pairs)
+
+	pairs, err = table.ZRange("key_2", 0, -1)
+	require.NoError(t, err)
+	require.Empty(t, pairs)
+}
+
+func TestZRem(t *testing.T) {
+	t.Parallel()
+
+	log := func() error { return nil }
+
+	table := NewHashTable()
+	_, err := table.ZAdd("key_1", map[string]float64{"a": 1, "b": 2}, log)
+	require.NoError(t, err)
+
+	removed, err := table.ZRem("key_1", []string{"c"}, func() error { return errors.New("must not be logged") })
+	require.NoError(t, err)
+	require.Equal(t, 0, removed)
+
+	removed, err = table.ZRem("key_1", []string{"a", "c"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	rank, _, err := table.ZRank("key_1", "b")
+	require.NoError(t, err)
+	require.Equal(t, 0, rank)
+
+	removed, err = table.ZRem("key_1", []string{"b"}, log)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	require.Empty(t, table.Type("key_1"))
+}
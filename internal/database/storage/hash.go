@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+)
+
+// HSet sets fields of the hash and returns number of the added fields
+func (s *Storage) HSet(ctx context.Context, key string, pairs []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	count, err := s.engine.HSet(ctx, key, pairs, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.HSet(ctx, key, pairs)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.HSetCommand, true, err)
+	return count, err
+}
+
+func (s *Storage) HGet(ctx context.Context, key, field string) (string, error) {
+	value, found, err := s.engine.HGet(ctx, key, field)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+// HDel deletes fields of the hash and returns number of the deleted fields
+func (s *Storage) HDel(ctx context.Context, key string, fields []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	count, err := s.engine.HDel(ctx, key, fields, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.HDel(ctx, key, fields)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.HDelCommand, count != 0, err)
+	return count, err
+}
+
+// HGetAll returns fields of the hash followed by their values
+func (s *Storage) HGetAll(ctx context.Context, key string) ([]string, error) {
+	return s.engine.HGetAll(ctx, key)
+}
+
+func (s *Storage) HLen(ctx context.Context, key string) (int, error) {
+	return s.engine.HLen(ctx, key)
+}
+
+func (s *Storage) HExists(ctx context.Context, key, field string) (bool, error) {
+	return s.engine.HExists(ctx, key, field)
+}
+
+// HIncrBy increments integer value of the field, the resulting
+// value is logged to WAL instead of the delta
+func (s *Storage) HIncrBy(ctx context.Context, key, field string, delta int64) (int64, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	value, err := s.engine.HIncrBy(ctx, key, field, delta, func(value string) error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.HSet(ctx, key, []string{field, value})
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.HIncrByCommand, true, err)
+	return value, err
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestHSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		HSet(ctx, "key_1", []string{"field_1", "value_1"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ []string, log func() error) (int, error) {
+			return 1, log()
+		})
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().HSet(ctx, "key_1", []string{"field_1", "value_1"}).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	added, err := storage.HSet(ctx, "key_1", []string{"field_1", "value_1"})
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+}
+
+func TestHIncrBy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		HIncrBy(ctx, "key_1", "field_1", int64(5), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, _ int64, log func(string) error) (int64, error) {
+			return 15, log("15")
+		})
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().HSet(ctx, "key_1", []string{"field_1", "15"}).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	value, err := storage.HIncrBy(ctx, "key_1", "field_1", 5)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), value)
+}
+
+func TestHGetMissingField(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().HGet(ctx, "key_1", "field_1").Return("", false, nil)
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = storage.HGet(ctx, "key_1", "field_1")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRecoverHashes(t *testing.T) {
+	t.Parallel()
+
+	logs := []wal.LogData{
+		{LSN: 1, CommandID: compute.HSetCommandID, Arguments: []string{"key_1", "field_1", "value_1", "field_2", "value_2"}},
+		{LSN: 2, CommandID: compute.HDelCommandID, Arguments: []string{"key_1", "field_1"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	gomock.InOrder(
+		engine.EXPECT().HSet(gomock.Any(), "key_1", []string{"field_1", "value_1", "field_2", "value_2"}, gomock.Any()),
+		engine.EXPECT().HDel(gomock.Any(), "key_1", []string{"field_1"}, gomock.Any()),
+	)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(logs, nil)
+	walMock.EXPECT().Start()
+
+	_, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+)
+
+// LPush inserts values at the head of the list and
+// returns length of the list after insertion
+func (s *Storage) LPush(ctx context.Context, key string, values []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	length, err := s.engine.LPush(ctx, key, values, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.LPush(ctx, key, values)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.LPushCommand, true, err)
+	return length, err
+}
+
+// RPush appends values to the tail of the list and
+// returns length of the list after insertion
+func (s *Storage) RPush(ctx context.Context, key string, values []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	length, err := s.engine.RPush(ctx, key, values, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.RPush(ctx, key, values)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.RPushCommand, true, err)
+	return length, err
+}
+
+// LPop removes and returns the first value of the list
+func (s *Storage) LPop(ctx context.Context, key string) (string, error) {
+	if s.stream != nil {
+		return "", ErrSlaveWrite
+	}
+
+	value, found, err := s.engine.LPop(ctx, key, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.LPop(ctx, key)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.LPopCommand, found, err)
+	return listValue(value, found, err)
+}
+
+// RPop removes and returns the last value of the list
+func (s *Storage) RPop(ctx context.Context, key string) (string, error) {
+	if s.stream != nil {
+		return "", ErrSlaveWrite
+	}
+
+	value, found, err := s.engine.RPop(ctx, key, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.RPop(ctx, key)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.RPopCommand, found, err)
+	return listValue(value, found, err)
+}
+
+func (s *Storage) LLen(ctx context.Context, key string) (int, error) {
+	return s.engine.LLen(ctx, key)
+}
+
+// LRange returns values of the list from start to stop inclusive
+func (s *Storage) LRange(ctx context.Context, key string, start, stop int) ([]string, error) {
+	return s.engine.LRange(ctx, key, start, stop)
+}
+
+func (s *Storage) LIndex(ctx context.Context, key string, index int) (string, error) {
+	value, found, err := s.engine.LIndex(ctx, key, index)
+	return listValue(value, found, err)
+}
+
+// LTrim keeps only values of the list from start to stop inclusive
+func (s *Storage) LTrim(ctx context.Context, key string, start, stop int) error {
+	if s.stream != nil {
+		return ErrSlaveWrite
+	}
+
+	err := s.engine.LTrim(ctx, key, start, stop, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.LTrim(ctx, key, start, stop)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.LTrimCommand, true, err)
+	return err
+}
+
+func listValue(value string, found bool, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestLPush(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		LPush(ctx, "key_1", []string{"value_1", "value_2"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ []string, log func() error) (int, error) {
+			return 2, log()
+		})
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().LPush(ctx, "key_1", []string{"value_1", "value_2"}).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	length, err := storage.LPush(ctx, "key_1", []string{"value_1", "value_2"})
+	require.NoError(t, err)
+	require.Equal(t, 2, length)
+}
+
+func TestRPop(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		RPop(ctx, "key_1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, log func() error) (string, bool, error) {
+			return "value_1", true, log()
+		})
+	engine.EXPECT().RPop(ctx, "key_2", gomock.Any()).Return("", false, nil)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().RPop(ctx, "key_1").Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	value, err := storage.RPop(ctx, "key_1")
+	require.NoError(t, err)
+	require.Equal(t, "value_1", value)
+
+	_, err = storage.RPop(ctx, "key_2")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLTrim(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		LTrim(ctx, "key_1", 1, -1, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _, _ int, log func() error) error {
+			return log()
+		})
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().LTrim(ctx, "key_1", 1, -1).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, storage.LTrim(ctx, "key_1", 1, -1))
+}
+
+func TestRecoverLists(t *testing.T) {
+	t.Parallel()
+
+	logs := []wal.LogData{
+		{LSN: 1, CommandID: compute.RPushCommandID, Arguments: []string{"key_1", "value_1", "value_2", "value_3"}},
+		{LSN: 2, CommandID: compute.LPushCommandID, Arguments: []string{"key_1", "value_0"}},
+		{LSN: 3, CommandID: compute.LPopCommandID, Arguments: []string{"key_1"}},
+		{LSN: 4, CommandID: compute.RPopCommandID, Arguments: []string{"key_1"}},
+		{LSN: 5, CommandID: compute.LTrimCommandID, Arguments: []string{"key_1", "0", "-2"}},
+		{LSN: 6, CommandID: compute.LTrimCommandID, Arguments: []string{"key_1", "invalid", "-2"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	gomock.InOrder(
+		engine.EXPECT().RPush(gomock.Any(), "key_1", []string{"value_1", "value_2", "value_3"}, gomock.Any()),
+		engine.EXPECT().LPush(gomock.Any(), "key_1", []string{"value_0"}, gomock.Any()),
+		engine.EXPECT().LPop(gomock.Any(), "key_1", gomock.Any()),
+		engine.EXPECT().RPop(gomock.Any(), "key_1", gomock.Any()),
+		engine.EXPECT().LTrim(gomock.Any(), "key_1", 0, -2, gomock.Any()),
+	)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(logs, nil)
+	walMock.EXPECT().Start()
+
+	_, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+}
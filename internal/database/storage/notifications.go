@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"time"
+)
+
+// Classes of keyspace events, which are enabled separately
+const (
+	SetEventClass     = "set"
+	DelEventClass     = "del"
+	ExpiredEventClass = "expired"
+)
+
+// ExpiredCommand is command of events about keys deleted
+// by expiration, which isn't a client command
+const ExpiredCommand = "EXPIRED"
+
+var eventClasses = map[string]struct{}{
+	SetEventClass:     {},
+	DelEventClass:     {},
+	ExpiredEventClass: {},
+}
+
+// Event describes change of the key made by the command, LSN is
+// the sequence number of the change in WAL or zero for expirations,
+// which aren't logged
+type Event struct {
+	Key     string
+	Command string
+	LSN     int64
+}
+
+type notifications struct {
+	classes  map[string]struct{}
+	listener func(Event)
+}
+
+// EnableNotifications makes storage to pass events of the classes to the
+// listener after changes are applied, it may be called several times to add
+// listeners of different classes, but must be called before storage is used
+func (s *Storage) EnableNotifications(classes []string, listener func(Event)) error {
+	enabled := make(map[string]struct{}, len(classes))
+	for _, class := range classes {
+		if _, found := eventClasses[class]; !found {
+			return fmt.Errorf("unknown event class: %s", class)
+		}
+
+		enabled[class] = struct{}{}
+	}
+
+	s.notifications = append(s.notifications, notifications{
+		classes:  enabled,
+		listener: listener,
+	})
+
+	return nil
+}
+
+// StartExpiration runs background deletion of expired
+// keys, which are notified as expired events
+func (s *Storage) StartExpiration(ctx context.Context, interval time.Duration) {
+	s.engine.StartExpiration(ctx, interval, func(key string) {
+		s.emit(ExpiredEventClass, Event{Key: key, Command: ExpiredCommand})
+	})
+}
+
+// written notifies waiters and listener about the
+// key set by the command, must be called after the write
+func (s *Storage) written(ctx context.Context, key, command string) {
+	s.waiters.notify(key)
+	s.emit(SetEventClass, Event{Key: key, Command: command, LSN: lsnFromContext(ctx)})
+}
+
+func (s *Storage) deleted(ctx context.Context, key, command string) {
+	s.emit(DelEventClass, Event{Key: key, Command: command, LSN: lsnFromContext(ctx)})
+}
+
+func (s *Storage) emit(class string, event Event) {
+	for _, notifications := range s.notifications {
+		if _, found := notifications.classes[class]; found {
+			notifications.listener(event)
+		}
+	}
+}
+
+// loggedCommands are names of the commands logged to WAL,
+// which are notified when the logs are replayed by replicas
+var loggedCommands = map[int]string{
+	compute.SetCommandID:     compute.SetCommand,
+	compute.DelCommandID:     compute.DelCommand,
+	compute.ExpireCommandID:  compute.ExpireCommand,
+	compute.PersistCommandID: compute.PersistCommand,
+	compute.HSetCommandID:    compute.HSetCommand,
+	compute.HDelCommandID:    compute.HDelCommand,
+	compute.LPushCommandID:   compute.LPushCommand,
+	compute.RPushCommandID:   compute.RPushCommand,
+	compute.LPopCommandID:    compute.LPopCommand,
+	compute.RPopCommandID:    compute.RPopCommand,
+	compute.LTrimCommandID:   compute.LTrimCommand,
+	compute.SAddCommandID:    compute.SAddCommand,
+	compute.SRemCommandID:    compute.SRemCommand,
+	compute.ZAddCommandID:    compute.ZAddCommand,
+	compute.ZRemCommandID:    compute.ZRemCommand,
+}
+
+// changed notifies about the key changed by the command, the key is
+// notified as deleted if the command has removed its last element,
+// which isn't looked up if there are no listeners
+func (s *Storage) changed(ctx context.Context, key, command string) {
+	if len(s.notifications) == 0 {
+		s.waiters.notify(key)
+		return
+	}
+
+	if s.engine.Type(ctx, key) == "" {
+		s.deleted(ctx, key, command)
+	} else {
+		s.written(ctx, key, command)
+	}
+}
+
+// changedBy notifies about the key if the command has changed it without error
+func (s *Storage) changedBy(ctx context.Context, key, command string, changed bool, err error) {
+	if changed && err == nil {
+		s.changed(ctx, key, command)
+	}
+}
+
+// applied notifies about the key changed by the log replayed from WAL of
+// the master, commands computing values (like INCR) are logged as SET
+func (s *Storage) applied(ctx context.Context, commandID int, arguments []string) {
+	command, found := loggedCommands[commandID]
+	if !found || len(arguments) == 0 {
+		return
+	}
+
+	if commandID == compute.DelCommandID {
+		s.deleted(ctx, arguments[0], command)
+	} else {
+		s.changed(ctx, arguments[0], command)
+	}
+}
+
+// lsnFromContext returns the transaction id, which is used
+// as LSN of the logs written by the transaction
+func lsnFromContext(ctx context.Context) int64 {
+	lsn, _ := ctx.Value("tx").(int64)
+	return lsn
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type eventRecorder struct {
+	mutex  sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) record(event Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) recorded() []Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]Event(nil), r.events...)
+}
+
+func TestEnableNotificationsWithUnknownClass(t *testing.T) {
+	t.Parallel()
+
+	storage := newBlockingTestStorage(t)
+	require.Error(t, storage.EnableNotifications([]string{"set", "renamed"}, func(Event) {}))
+}
+
+func TestNotifications(t *testing.T) {
+	t.Parallel()
+
+	var recorder eventRecorder
+	storage := newBlockingTestStorage(t)
+	require.NoError(t, storage.EnableNotifications([]string{SetEventClass, DelEventClass}, recorder.record))
+
+	ctx := context.WithValue(context.Background(), "tx", int64(10))
+	require.NoError(t, storage.Set(ctx, "key_1", "value_1"))
+	deleted, err := storage.Del(ctx, "key_1")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	ctx = context.WithValue(context.Background(), "tx", int64(11))
+	_, err = storage.IncrBy(ctx, "counter", 1)
+	require.NoError(t, err)
+	_, err = storage.IncrBy(ctx, "counter", 1)
+	require.NoError(t, err)
+
+	_, err = storage.GetDel(ctx, "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// deletions of missing keys aren't notified
+	deleted, err = storage.Del(ctx, "missing")
+	require.NoError(t, err)
+	require.False(t, deleted)
+	removed, err := storage.MDel(ctx, []string{"missing", "counter", "counter"})
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	tx := storage.Begin()
+	_, err = tx.Del(ctx, "missing")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(ctx))
+
+	storage.applyLogs([]wal.LogData{
+		{LSN: 12, CommandID: compute.SetCommandID, Arguments: []string{"key_2", "value_2"}},
+		{LSN: 13, CommandID: compute.DelCommandID, Arguments: []string{"key_2"}},
+		{LSN: 14, CommandID: compute.DelCommandID, Arguments: []string{"key_2"}},
+	})
+
+	require.Equal(t, []Event{
+		{Key: "key_1", Command: "SET", LSN: 10},
+		{Key: "key_1", Command: "DEL", LSN: 10},
+		{Key: "counter", Command: "INCRBY", LSN: 11},
+		{Key: "counter", Command: "INCRBY", LSN: 11},
+		{Key: "counter", Command: "MDEL", LSN: 11},
+		{Key: "key_2", Command: "SET", LSN: 12},
+		{Key: "key_2", Command: "DEL", LSN: 13},
+	}, recorder.recorded())
+}
+
+func TestCollectionNotifications(t *testing.T) {
+	t.Parallel()
+
+	var recorder eventRecorder
+	storage := newBlockingTestStorage(t)
+	require.NoError(t, storage.EnableNotifications([]string{SetEventClass, DelEventClass}, recorder.record))
+
+	ctx := context.WithValue(context.Background(), "tx", int64(10))
+	_, err := storage.HSet(ctx, "hash", []string{"field", "value"})
+	require.NoError(t, err)
+	_, err = storage.HDel(ctx, "hash", []string{"missing"})
+	require.NoError(t, err)
+	_, err = storage.HDel(ctx, "hash", []string{"field"})
+	require.NoError(t, err)
+
+	_, err = storage.RPush(ctx, "list", []string{"a", "b"})
+	require.NoError(t, err)
+	_, err = storage.LPop(ctx, "list")
+	require.NoError(t, err)
+	_, err = storage.LPop(ctx, "list")
+	require.NoError(t, err)
+	_, err = storage.LPop(ctx, "list")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = storage.SAdd(ctx, "set", []string{"a"})
+	require.NoError(t, err)
+	_, err = storage.ZAdd(ctx, "zset", []string{"1", "a"})
+	require.NoError(t, err)
+
+	require.Equal(t, []Event{
+		{Key: "hash", Command: "HSET", LSN: 10},
+		{Key: "hash", Command: "HDEL", LSN: 10},
+		{Key: "list", Command: "RPUSH", LSN: 10},
+		{Key: "list", Command: "LPOP", LSN: 10},
+		{Key: "list", Command: "LPOP", LSN: 10},
+		{Key: "set", Command: "SADD", LSN: 10},
+		{Key: "zset", Command: "ZADD", LSN: 10},
+	}, recorder.recorded())
+}
+
+func TestReplicaNotifications(t *testing.T) {
+	t.Parallel()
+
+	var sets, deletions eventRecorder
+	storage := newBlockingTestStorage(t)
+	require.NoError(t, storage.EnableNotifications([]string{SetEventClass}, sets.record))
+	require.NoError(t, storage.EnableNotifications([]string{DelEventClass}, deletions.record))
+
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+	storage.applyLogs([]wal.LogData{
+		{LSN: 1, CommandID: compute.SetCommandID, Arguments: []string{"key", "value"}},
+		{LSN: 2, CommandID: compute.ExpireCommandID, Arguments: []string{"key", formatExpiration(expiresAt)}},
+		{LSN: 3, CommandID: compute.LPushCommandID, Arguments: []string{"list", "a"}},
+		{LSN: 4, CommandID: compute.RPopCommandID, Arguments: []string{"list"}},
+	})
+
+	require.Equal(t, []Event{
+		{Key: "key", Command: "SET", LSN: 1},
+		{Key: "key", Command: "EXPIRE", LSN: 2},
+		{Key: "list", Command: "LPUSH", LSN: 3},
+	}, sets.recorded())
+	require.Equal(t, []Event{{Key: "list", Command: "RPOP", LSN: 4}}, deletions.recorded())
+}
+
+func TestNotificationsOfDisabledClasses(t *testing.T) {
+	t.Parallel()
+
+	var recorder eventRecorder
+	storage := newBlockingTestStorage(t)
+	require.NoError(t, storage.EnableNotifications([]string{DelEventClass}, recorder.record))
+
+	ctx := context.WithValue(context.Background(), "tx", int64(10))
+	require.NoError(t, storage.MSet(ctx, []string{"key_1", "value_1", "key_2", "value_2"}))
+	removed, err := storage.MDel(ctx, []string{"key_1"})
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	require.Equal(t, []Event{{Key: "key_1", Command: "MDEL", LSN: 10}}, recorder.recorded())
+}
+
+func TestExpiredNotifications(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), "tx", int64(10)))
+	defer cancel()
+
+	var recorder eventRecorder
+	storage := newBlockingTestStorage(t)
+	require.NoError(t, storage.EnableNotifications([]string{ExpiredEventClass}, recorder.record))
+
+	require.NoError(t, storage.SetWithTTL(ctx, "key_1", "value_1", time.Millisecond))
+	storage.StartExpiration(ctx, time.Millisecond*5)
+
+	require.Eventually(t, func() bool {
+		return len(recorder.recorded()) == 1
+	}, time.Second, time.Millisecond*5)
+	require.Equal(t, Event{Key: "key_1", Command: ExpiredCommand}, recorder.recorded()[0])
+}
+
+func TestNotificationsWithSeveralListeners(t *testing.T) {
+	t.Parallel()
+
+	var sets, all eventRecorder
+	storage := newBlockingTestStorage(t)
+	require.NoError(t, storage.EnableNotifications([]string{SetEventClass}, sets.record))
+	require.NoError(t, storage.EnableNotifications([]string{SetEventClass, DelEventClass}, all.record))
+
+	ctx := context.WithValue(context.Background(), "tx", int64(10))
+	require.NoError(t, storage.Set(ctx, "key", "value"))
+	_, err := storage.Del(ctx, "key")
+	require.NoError(t, err)
+
+	require.Equal(t, []Event{{Key: "key", Command: compute.SetCommand, LSN: 10}}, sets.recorded())
+	require.Equal(t, []Event{
+		{Key: "key", Command: compute.SetCommand, LSN: 10},
+		{Key: "key", Command: compute.DelCommand, LSN: 10},
+	}, all.recorded())
+}
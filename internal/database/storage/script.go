@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/script"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"sort"
+)
+
+// maxScriptSteps limits number of statements and expressions evaluated
+// by a script, so runaway scripts don't hold partition locks forever
+const maxScriptSteps = 100000
+
+// scriptView buffers writes of the script over values read from the
+// engine, so the script sees its own writes before they are applied,
+// keys set by the script keep their expiration times unless they have
+// been deleted by the script before
+type scriptView struct {
+	read        func(string) (string, int64, bool, error)
+	values      map[string]*string
+	expirations map[string]int64
+}
+
+func (v *scriptView) Get(key string) (string, bool, error) {
+	if value, found := v.values[key]; found {
+		if value == nil {
+			return "", false, nil
+		}
+
+		return *value, true, nil
+	}
+
+	value, _, found, err := v.read(key)
+	return value, found, err
+}
+
+func (v *scriptView) Set(key, value string) {
+	if _, written := v.values[key]; !written {
+		if _, expiresAt, found, _ := v.read(key); found && expiresAt != 0 {
+			v.expirations[key] = expiresAt
+		}
+	}
+
+	v.values[key] = &value
+}
+
+func (v *scriptView) Del(key string) (bool, error) {
+	_, found, err := v.Get(key)
+	if err != nil {
+		return false, err
+	}
+
+	v.values[key] = nil
+	delete(v.expirations, key)
+	return found, nil
+}
+
+// logs returns resulting writes of the script ordered
+// by keys, so they are replayed deterministically
+func (v *scriptView) logs() []wal.LogData {
+	keys := make([]string, 0, len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	logs := make([]wal.LogData, 0, len(keys))
+	for _, key := range keys {
+		if value := v.values[key]; value != nil && v.expirations[key] != 0 {
+			arguments := []string{key, *value, formatExpiration(v.expirations[key])}
+			logs = append(logs, wal.LogData{CommandID: compute.SetCommandID, Arguments: arguments})
+		} else if value != nil {
+			logs = append(logs, wal.LogData{CommandID: compute.SetCommandID, Arguments: []string{key, *value}})
+		} else {
+			logs = append(logs, wal.LogData{CommandID: compute.DelCommandID, Arguments: []string{key}})
+		}
+	}
+
+	return logs
+}
+
+// Eval runs the script with partitions of the keys locked, so it's
+// atomic, the script may access only the keys, its writes are logged
+// to WAL as plain SET and DEL logs, so replicas and recovery don't run
+// scripts again, the result is false if the script returns nil
+func (s *Storage) Eval(ctx context.Context, source string, keys, args []string) (string, bool, error) {
+	if s.stream != nil {
+		return "", false, ErrSlaveWrite
+	}
+
+	parsed, err := script.Parse(source)
+	if err != nil {
+		return "", false, err
+	}
+
+	var result string
+	var found bool
+	view := &scriptView{
+		values:      make(map[string]*string),
+		expirations: make(map[string]int64),
+	}
+
+	err = s.engine.Execute(ctx, keys, func(read func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error) {
+		view.read = read
+		result, found, err = parsed.Run(view, keys, args, maxScriptSteps)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if logs := view.logs(); s.wal != nil && len(logs) != 0 {
+			future := s.wal.Write(ctx, logs)
+			if err := future.Get(); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return view.values, view.expirations, nil
+	})
+
+	if err != nil {
+		return "", false, err
+	}
+
+	for key, value := range view.values {
+		if value != nil {
+			s.written(ctx, key, compute.SetCommand)
+		} else {
+			s.deleted(ctx, key, compute.DelCommand)
+		}
+	}
+
+	return result, found, nil
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/engine/in_memory"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestEvalLogsResultingWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	engine, err := in_memory.NewEngine(in_memory.HashTableBuilder, 4, zap.NewNop())
+	require.NoError(t, err)
+	engine.Set(ctx, "a", "10")
+	engine.Set(ctx, "c", "value")
+
+	ctrl := gomock.NewController(t)
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().Write(ctx, []wal.LogData{
+		{CommandID: compute.SetCommandID, Arguments: []string{"a", "12"}},
+		{CommandID: compute.SetCommandID, Arguments: []string{"b", "done"}},
+		{CommandID: compute.DelCommandID, Arguments: []string{"c"}},
+	}).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	source := `
+		incrby(KEYS[1], 1)
+		incrby(KEYS[1], 1)
+		del(KEYS[3])
+		set(KEYS[2], ARGV[1])
+		return get(KEYS[1])`
+	value, found, err := storage.Eval(ctx, source, []string{"a", "b", "c"}, []string{"done"})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "12", value)
+
+	current, err := storage.Get(ctx, "b")
+	require.NoError(t, err)
+	require.Equal(t, "done", current)
+	_, err = storage.Get(ctx, "c")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEvalKeepsExpiration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+	engine, err := in_memory.NewEngine(in_memory.HashTableBuilder, 4, zap.NewNop())
+	require.NoError(t, err)
+	engine.SetWithExpiration(ctx, "a", "10", expiresAt)
+	engine.SetWithExpiration(ctx, "b", "value", expiresAt)
+
+	ctrl := gomock.NewController(t)
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().Write(ctx, []wal.LogData{
+		{CommandID: compute.SetCommandID, Arguments: []string{"a", "11", formatExpiration(expiresAt)}},
+		{CommandID: compute.SetCommandID, Arguments: []string{"b", "new"}},
+	}).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	// the key deleted by the script loses its expiration time
+	_, _, err = storage.Eval(ctx, "incrby(KEYS[1], 1) del(KEYS[2]) set(KEYS[2], ARGV[1])", []string{"a", "b"}, []string{"new"})
+	require.NoError(t, err)
+
+	ttl, found, err := storage.TTL(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Greater(t, ttl, time.Minute)
+
+	ttl, found, err = storage.TTL(ctx, "b")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, NoExpiration, ttl)
+}
+
+func TestEvalWithWALError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- errors.New("wal error")
+
+	engine, err := in_memory.NewEngine(in_memory.HashTableBuilder, 4, zap.NewNop())
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().Write(ctx, gomock.Any()).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	_, _, err = storage.Eval(ctx, "set(KEYS[1], 1)", []string{"a"}, nil)
+	require.Error(t, err)
+
+	_, err = storage.Get(ctx, "a")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEvalWithoutWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+	storage := newBlockingTestStorage(t)
+
+	_, _, err := storage.Eval(ctx, "return (", nil, nil)
+	require.Error(t, err)
+
+	value, found, err := storage.Eval(ctx, "return exists(KEYS[1])", []string{"a"}, nil)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "false", value)
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+)
+
+// SAdd adds members to the set and returns number of the added members
+func (s *Storage) SAdd(ctx context.Context, key string, members []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	count, err := s.engine.SAdd(ctx, key, members, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.SAdd(ctx, key, members)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.SAddCommand, count != 0, err)
+	return count, err
+}
+
+// SRem removes members from the set and returns number of the removed members
+func (s *Storage) SRem(ctx context.Context, key string, members []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	count, err := s.engine.SRem(ctx, key, members, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.SRem(ctx, key, members)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.SRemCommand, count != 0, err)
+	return count, err
+}
+
+func (s *Storage) SIsMember(ctx context.Context, key, member string) (bool, error) {
+	return s.engine.SIsMember(ctx, key, member)
+}
+
+func (s *Storage) SMembers(ctx context.Context, key string) ([]string, error) {
+	return s.engine.SMembers(ctx, key)
+}
+
+func (s *Storage) SCard(ctx context.Context, key string) (int, error) {
+	return s.engine.SCard(ctx, key)
+}
+
+func (s *Storage) SInter(ctx context.Context, keys []string) ([]string, error) {
+	return s.engine.SInter(ctx, keys)
+}
+
+func (s *Storage) SUnion(ctx context.Context, keys []string) ([]string, error) {
+	return s.engine.SUnion(ctx, keys)
+}
+
+func (s *Storage) SDiff(ctx context.Context, keys []string) ([]string, error) {
+	return s.engine.SDiff(ctx, keys)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestSAdd(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		SAdd(ctx, "key_1", []string{"member_1", "member_2"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ []string, log func() error) (int, error) {
+			return 2, log()
+		})
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().SAdd(ctx, "key_1", []string{"member_1", "member_2"}).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	added, err := storage.SAdd(ctx, "key_1", []string{"member_1", "member_2"})
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+}
+
+func TestSInter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().SInter(ctx, []string{"key_1", "key_2"}).Return([]string{"member_1"}, nil)
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	members, err := storage.SInter(ctx, []string{"key_1", "key_2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"member_1"}, members)
+}
+
+func TestRecoverSets(t *testing.T) {
+	t.Parallel()
+
+	logs := []wal.LogData{
+		{LSN: 1, CommandID: compute.SAddCommandID, Arguments: []string{"key_1", "member_1", "member_2"}},
+		{LSN: 2, CommandID: compute.SRemCommandID, Arguments: []string{"key_1", "member_1"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	gomock.InOrder(
+		engine.EXPECT().SAdd(gomock.Any(), "key_1", []string{"member_1", "member_2"}, gomock.Any()),
+		engine.EXPECT().SRem(gomock.Any(), "key_1", []string{"member_1"}, gomock.Any()),
+	)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(logs, nil)
+	walMock.EXPECT().Start()
+
+	_, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"math"
+	"strconv"
+)
+
+var ErrInvalidScore = errors.New("score is not a valid float")
+
+// ZAdd sets scores of members of the sorted set, pairs contains scores
+// followed by members, returns number of the added members
+func (s *Storage) ZAdd(ctx context.Context, key string, pairs []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	scores, err := parseScores(pairs)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.engine.ZAdd(ctx, key, scores, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.ZAdd(ctx, key, pairs)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.ZAddCommand, true, err)
+	return count, err
+}
+
+// ZRem removes members from the sorted set and
+// returns number of the removed members
+func (s *Storage) ZRem(ctx context.Context, key string, members []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	count, err := s.engine.ZRem(ctx, key, members, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.ZRem(ctx, key, members)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.ZRemCommand, count != 0, err)
+	return count, err
+}
+
+func (s *Storage) ZScore(ctx context.Context, key, member string) (float64, error) {
+	score, found, err := s.engine.ZScore(ctx, key, member)
+	if err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return 0, ErrNotFound
+	}
+
+	return score, nil
+}
+
+func (s *Storage) ZRank(ctx context.Context, key, member string) (int, error) {
+	rank, found, err := s.engine.ZRank(ctx, key, member)
+	if err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return 0, ErrNotFound
+	}
+
+	return rank, nil
+}
+
+// ZRange returns members from start to stop inclusive
+// ordered by score followed by their scores
+func (s *Storage) ZRange(ctx context.Context, key string, start, stop int) ([]string, error) {
+	return s.engine.ZRange(ctx, key, start, stop)
+}
+
+// ZRangeByScore returns members with scores between min and max
+// inclusive ordered by score followed by their scores
+func (s *Storage) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]string, error) {
+	return s.engine.ZRangeByScore(ctx, key, min, max)
+}
+
+func (s *Storage) ZCard(ctx context.Context, key string) (int, error) {
+	return s.engine.ZCard(ctx, key)
+}
+
+// parseScores parses scores followed by members, the last
+// score of a member repeated several times is used
+func parseScores(pairs []string) (map[string]float64, error) {
+	scores := make(map[string]float64, len(pairs)/2)
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		score, err := strconv.ParseFloat(pairs[idx], 64)
+		if err != nil || math.IsNaN(score) {
+			return nil, ErrInvalidScore
+		}
+
+		scores[pairs[idx+1]] = score
+	}
+
+	return scores, nil
+}
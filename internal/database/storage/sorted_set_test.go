@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"math"
+	"testing"
+)
+
+func TestZAdd(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	pairs := []string{"1.5", "member_1", "-inf", "member_2", "3", "member_1"}
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		ZAdd(ctx, "key_1", map[string]float64{"member_1": 3, "member_2": math.Inf(-1)}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ map[string]float64, log func() error) (int, error) {
+			return 2, log()
+		})
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().ZAdd(ctx, "key_1", pairs).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	added, err := storage.ZAdd(ctx, "key_1", pairs)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+
+	_, err = storage.ZAdd(ctx, "key_1", []string{"nan", "member_1"})
+	require.ErrorIs(t, err, ErrInvalidScore)
+
+	_, err = storage.ZAdd(ctx, "key_1", []string{"high", "member_1"})
+	require.ErrorIs(t, err, ErrInvalidScore)
+}
+
+func TestZScoreMissingMember(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().ZScore(ctx, "key_1", "member_1").Return(0.0, false, nil)
+	engine.EXPECT().ZRank(ctx, "key_1", "member_1").Return(0, false, nil)
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = storage.ZScore(ctx, "key_1", "member_1")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = storage.ZRank(ctx, "key_1", "member_1")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRecoverSortedSets(t *testing.T) {
+	t.Parallel()
+
+	logs := []wal.LogData{
+		{LSN: 1, CommandID: compute.ZAddCommandID, Arguments: []string{"key_1", "1", "member_1", "2.5", "member_2"}},
+		{LSN: 2, CommandID: compute.ZRemCommandID, Arguments: []string{"key_1", "member_1"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	gomock.InOrder(
+		engine.EXPECT().ZAdd(gomock.Any(), "key_1", map[string]float64{"member_1": 1, "member_2": 2.5}, gomock.Any()),
+		engine.EXPECT().ZRem(gomock.Any(), "key_1", []string{"member_1"}, gomock.Any()),
+	)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(logs, nil)
+	walMock.EXPECT().Start()
+
+	_, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+}
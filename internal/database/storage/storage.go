@@ -7,19 +7,99 @@ import (
 	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
 	"github.com/passsquale/key-value-storage/internal/tools"
 	"go.uber.org/zap"
+	"strconv"
+	"time"
 )
 
+var (
+	ErrNotFound          = errors.New("key not found")
+	ErrWatchedKeyChanged = errors.New("watched key has been changed")
+	ErrWrongType         = errors.New("WRONGTYPE operation against a key holding the wrong kind of value")
+	ErrSlaveWrite        = errors.New("mutable transaction on slave")
+)
+
+// NoExpiration is returned as TTL of keys without expiration time
+const NoExpiration time.Duration = -1
+
+var now = time.Now
+
 type Engine interface {
 	Set(context.Context, string, string)
+	SetWithExpiration(context.Context, string, string, int64)
 	Get(context.Context, string) (string, bool)
 	Del(context.Context, string)
+	Expire(context.Context, string, int64, func() error) (bool, error)
+	Persist(context.Context, string, func() error) (bool, error)
+	ExpiresAt(context.Context, string) (int64, bool)
+	CompareAndSet(context.Context, string, string, string, func() error) (bool, error)
+	SetIf(context.Context, string, string, int64, bool, func(string, int64) error) (bool, error)
+	Version(context.Context, string) int64
+	Scan(context.Context, string, int, func(string) bool) ([]string, string, error)
+	Keys(context.Context, func(string) bool) []string
+	Type(context.Context, string) string
+	HSet(context.Context, string, []string, func() error) (int, error)
+	HGet(context.Context, string, string) (string, bool, error)
+	HDel(context.Context, string, []string, func() error) (int, error)
+	HGetAll(context.Context, string) ([]string, error)
+	HLen(context.Context, string) (int, error)
+	HExists(context.Context, string, string) (bool, error)
+	HIncrBy(context.Context, string, string, int64, func(string) error) (int64, error)
+	LPush(context.Context, string, []string, func() error) (int, error)
+	RPush(context.Context, string, []string, func() error) (int, error)
+	LPop(context.Context, string, func() error) (string, bool, error)
+	RPop(context.Context, string, func() error) (string, bool, error)
+	LLen(context.Context, string) (int, error)
+	LRange(context.Context, string, int, int) ([]string, error)
+	LIndex(context.Context, string, int) (string, bool, error)
+	LTrim(context.Context, string, int, int, func() error) error
+	SAdd(context.Context, string, []string, func() error) (int, error)
+	SRem(context.Context, string, []string, func() error) (int, error)
+	SIsMember(context.Context, string, string) (bool, error)
+	SMembers(context.Context, string) ([]string, error)
+	SCard(context.Context, string) (int, error)
+	SInter(context.Context, []string) ([]string, error)
+	SUnion(context.Context, []string) ([]string, error)
+	SDiff(context.Context, []string) ([]string, error)
+	ZAdd(context.Context, string, map[string]float64, func() error) (int, error)
+	ZRem(context.Context, string, []string, func() error) (int, error)
+	ZScore(context.Context, string, string) (float64, bool, error)
+	ZRank(context.Context, string, string) (int, bool, error)
+	ZRange(context.Context, string, int, int) ([]string, error)
+	ZRangeByScore(context.Context, string, float64, float64) ([]string, error)
+	ZCard(context.Context, string) (int, error)
+	IncrBy(context.Context, string, int64, func(string, int64) error) (int64, error)
+	IncrByFloat(context.Context, string, float64, func(string, int64) error) (float64, error)
+	Append(context.Context, string, string, func(string, int64) error) (int, error)
+	SetRange(context.Context, string, int, string, func(string, int64) error) (int, error)
+	GetSet(context.Context, string, string, func(string, int64) error) (string, bool, error)
+	GetDel(context.Context, string, func() error) (string, bool, error)
+	Commit(context.Context, map[string]int64, map[string]*string, map[string]int64, func() error) (bool, []string, error)
+	Execute(context.Context, []string, func(func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error)) error
+	StartExpiration(context.Context, time.Duration, func(string))
 }
 
 type WAL interface {
 	Start()
 	Recover() ([]wal.LogData, error)
 	Set(context.Context, string, string) tools.FutureError
+	SetWithExpiration(context.Context, string, string, int64) tools.FutureError
 	Del(context.Context, string) tools.FutureError
+	MSet(context.Context, []string) tools.FutureError
+	MDel(context.Context, []string) tools.FutureError
+	Write(context.Context, []wal.LogData) tools.FutureError
+	Expire(context.Context, string, int64) tools.FutureError
+	Persist(context.Context, string) tools.FutureError
+	HSet(context.Context, string, []string) tools.FutureError
+	HDel(context.Context, string, []string) tools.FutureError
+	LPush(context.Context, string, []string) tools.FutureError
+	RPush(context.Context, string, []string) tools.FutureError
+	LPop(context.Context, string) tools.FutureError
+	RPop(context.Context, string) tools.FutureError
+	LTrim(context.Context, string, int, int) tools.FutureError
+	SAdd(context.Context, string, []string) tools.FutureError
+	SRem(context.Context, string, []string) tools.FutureError
+	ZAdd(context.Context, string, []string) tools.FutureError
+	ZRem(context.Context, string, []string) tools.FutureError
 	Shutdown()
 }
 
@@ -28,6 +108,9 @@ type Storage struct {
 	wal    WAL
 	stream <-chan []wal.LogData
 	logger *zap.Logger
+
+	waiters       *waiters
+	notifications []notifications
 }
 
 func NewStorage(
@@ -45,9 +128,10 @@ func NewStorage(
 	}
 
 	storage := &Storage{
-		engine: engine,
-		wal:    wal,
-		logger: logger,
+		engine:  engine,
+		wal:     wal,
+		logger:  logger,
+		waiters: newWaiters(),
 		//stream: replicationStream,
 	}
 
@@ -66,54 +150,588 @@ func NewStorage(
 
 func (s *Storage) Set(ctx context.Context, key, value string) error {
 	if s.stream != nil {
-		return errors.New("mutable transaction on slave")
+		return ErrSlaveWrite
 	}
 
-	if s.wal != nil {
-		future := s.wal.Set(ctx, key, value)
-		if err := future.Get(); err != nil {
-			return err
-		}
+	values := map[string]*string{key: &value}
+	if err := s.write(ctx, values, nil, func() error { return s.logValue(ctx, key)(value, 0) }); err != nil {
+		return err
+	}
+
+	s.written(ctx, key, compute.SetCommand)
+	return nil
+}
+
+func (s *Storage) SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error {
+	if s.stream != nil {
+		return ErrSlaveWrite
+	}
+
+	expiresAt := now().Add(ttl).UnixMilli()
+	values := map[string]*string{key: &value}
+	expirations := map[string]int64{key: expiresAt}
+	if err := s.write(ctx, values, expirations, func() error { return s.logValue(ctx, key)(value, expiresAt) }); err != nil {
+		return err
 	}
 
-	s.engine.Set(ctx, key, value)
+	s.written(ctx, key, compute.SetCommand)
 	return nil
 }
 
-func (s *Storage) Del(ctx context.Context, key string) error {
+// Del deletes the key of any type and reports whether it has existed
+func (s *Storage) Del(ctx context.Context, key string) (bool, error) {
 	if s.stream != nil {
-		return errors.New("mutable transaction on slave")
+		return false, ErrSlaveWrite
 	}
 
-	if s.wal != nil {
+	removed, err := s.remove(ctx, []string{key}, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
 		future := s.wal.Del(ctx, key)
-		if err := future.Get(); err != nil {
-			return err
+		return future.Get()
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, key := range removed {
+		s.deleted(ctx, key, compute.DelCommand)
+	}
+
+	return len(removed) != 0, nil
+}
+
+func (s *Storage) Get(ctx context.Context, key string) (string, error) {
+	value, found := s.engine.Get(ctx, key)
+	if !found {
+		if s.engine.Type(ctx, key) != "" {
+			return "", ErrWrongType
 		}
+
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+// MGet returns values of the keys, missing keys are represented by nil
+func (s *Storage) MGet(ctx context.Context, keys []string) ([]*string, error) {
+	values := make([]*string, 0, len(keys))
+	for _, key := range keys {
+		if value, found := s.engine.Get(ctx, key); found {
+			values = append(values, &value)
+		} else {
+			values = append(values, nil)
+		}
+	}
+
+	return values, nil
+}
+
+// Exists returns number of existing keys, the same
+// key mentioned several times is counted every time
+func (s *Storage) Exists(ctx context.Context, keys []string) (int, error) {
+	count := 0
+	for _, key := range keys {
+		if s.engine.Type(ctx, key) != "" {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// MSet sets key-value pairs, which are logged
+// to WAL as one batch to be recovered atomically
+func (s *Storage) MSet(ctx context.Context, pairs []string) error {
+	if s.stream != nil {
+		return ErrSlaveWrite
+	}
+
+	values := make(map[string]*string, len(pairs)/2)
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		values[pairs[idx]] = &pairs[idx+1]
+	}
+
+	err := s.write(ctx, values, nil, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.MSet(ctx, pairs)
+		return future.Get()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		s.written(ctx, pairs[idx], compute.MSetCommand)
 	}
 
-	s.engine.Del(ctx, key)
 	return nil
 }
 
-func (s *Storage) Get(ctx context.Context, key string) (string, error) {
-	value, _ := s.engine.Get(ctx, key)
+// MDel deletes the keys and returns number of the existed
+// ones, the same key mentioned several times is counted once
+func (s *Storage) MDel(ctx context.Context, keys []string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	removed, err := s.remove(ctx, keys, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.MDel(ctx, keys)
+		return future.Get()
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range removed {
+		s.deleted(ctx, key, compute.MDelCommand)
+	}
+
+	return len(removed), nil
+}
+
+// Expire sets time to live of the existing key, the change is logged
+// to WAL under the partition lock like other writes of the key
+func (s *Storage) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if s.stream != nil {
+		return false, ErrSlaveWrite
+	}
+
+	expiresAt := now().Add(ttl).UnixMilli()
+	updated, err := s.engine.Expire(ctx, key, expiresAt, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.Expire(ctx, key, expiresAt)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.ExpireCommand, updated, err)
+	return updated, err
+}
+
+// Persist removes expiration time of the key like Expire sets it
+func (s *Storage) Persist(ctx context.Context, key string) (bool, error) {
+	if s.stream != nil {
+		return false, ErrSlaveWrite
+	}
+
+	updated, err := s.engine.Persist(ctx, key, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.Persist(ctx, key)
+		return future.Get()
+	})
+
+	s.changedBy(ctx, key, compute.PersistCommand, updated, err)
+	return updated, err
+}
+
+// SetIf sets value of the key only if existence of the key equals
+// to exists, the check and the write are atomic, zero ttl means
+// that the key never expires
+func (s *Storage) SetIf(ctx context.Context, key, value string, ttl time.Duration, exists bool) (bool, error) {
+	if s.stream != nil {
+		return false, ErrSlaveWrite
+	}
+
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = now().Add(ttl).UnixMilli()
+	}
+
+	updated, err := s.engine.SetIf(ctx, key, value, expiresAt, exists, s.logValue(ctx, key))
+	if updated {
+		s.written(ctx, key, compute.SetCommand)
+	}
+
+	return updated, err
+}
+
+// CompareAndSet sets value of the key only if the current value equals
+// to the expected one, the check and the write are made under the partition
+// lock, which is held until the write is logged to WAL
+func (s *Storage) CompareAndSet(ctx context.Context, key, expected, value string) (bool, error) {
+	if s.stream != nil {
+		return false, ErrSlaveWrite
+	}
+
+	updated, err := s.engine.CompareAndSet(ctx, key, expected, value, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.Set(ctx, key, value)
+		return future.Get()
+	})
+
+	if updated {
+		s.written(ctx, key, compute.CASCommand)
+	}
+
+	return updated, err
+}
+
+// IncrBy increments integer value of the key under the partition
+// lock, the resulting value is logged to WAL instead of the delta
+func (s *Storage) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	value, err := s.engine.IncrBy(ctx, key, delta, s.logValue(ctx, key))
+	s.notifyWritten(ctx, key, compute.IncrByCommand, err)
+	return value, err
+}
+
+func (s *Storage) IncrByFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	value, err := s.engine.IncrByFloat(ctx, key, delta, s.logValue(ctx, key))
+	s.notifyWritten(ctx, key, compute.IncrByFloatCommand, err)
+	return value, err
+}
+
+// Append appends value to the key and returns length of the result
+func (s *Storage) Append(ctx context.Context, key, value string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	length, err := s.engine.Append(ctx, key, value, s.logValue(ctx, key))
+	s.notifyWritten(ctx, key, compute.AppendCommand, err)
+	return length, err
+}
+
+// SetRange overwrites part of the value starting at
+// offset and returns length of the result
+func (s *Storage) SetRange(ctx context.Context, key string, offset int, value string) (int, error) {
+	if s.stream != nil {
+		return 0, ErrSlaveWrite
+	}
+
+	length, err := s.engine.SetRange(ctx, key, offset, value, s.logValue(ctx, key))
+	s.notifyWritten(ctx, key, compute.SetRangeCommand, err)
+	return length, err
+}
+
+// GetSet sets value of the key and returns the
+// previous one or ErrNotFound if there was none
+func (s *Storage) GetSet(ctx context.Context, key, value string) (string, error) {
+	if s.stream != nil {
+		return "", ErrSlaveWrite
+	}
+
+	previous, found, err := s.engine.GetSet(ctx, key, value, s.logValue(ctx, key))
+	if err != nil {
+		return "", err
+	}
+
+	s.written(ctx, key, compute.GetSetCommand)
+
+	if !found {
+		return "", ErrNotFound
+	}
+
+	return previous, nil
+}
+
+// GetDel deletes the key and returns its value
+func (s *Storage) GetDel(ctx context.Context, key string) (string, error) {
+	if s.stream != nil {
+		return "", ErrSlaveWrite
+	}
+
+	value, found, err := s.engine.GetDel(ctx, key, func() error {
+		if s.wal == nil {
+			return nil
+		}
+
+		future := s.wal.Del(ctx, key)
+		return future.Get()
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", ErrNotFound
+	}
+
+	s.deleted(ctx, key, compute.GetDelCommand)
 	return value, nil
 }
 
+// StrLen returns length of the value, zero for missing keys
+func (s *Storage) StrLen(ctx context.Context, key string) (int, error) {
+	value, err := s.Get(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+
+	return len(value), nil
+}
+
+// GetRange returns substring of the value between start and end
+// inclusively, negative offsets are counted from the end of the value
+func (s *Storage) GetRange(ctx context.Context, key string, start, end int) (string, error) {
+	value, err := s.Get(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+
+	length := len(value)
+
+	if start < 0 {
+		start = max(length+start, 0)
+	}
+
+	if end < 0 {
+		end = length + end
+	}
+
+	end = min(end, length-1)
+	if start > end || start >= length {
+		return "", nil
+	}
+
+	return value[start : end+1], nil
+}
+
+// Scan returns keys matching glob-style pattern and the cursor
+// to continue iteration, which is finished when the cursor is "0"
+func (s *Storage) Scan(ctx context.Context, cursor, pattern string, count int) ([]string, string, error) {
+	return s.engine.Scan(ctx, cursor, count, func(key string) bool {
+		return tools.MatchPattern(pattern, key)
+	})
+}
+
+// Keys returns all keys matching glob-style pattern
+func (s *Storage) Keys(ctx context.Context, pattern string) ([]string, error) {
+	return s.engine.Keys(ctx, func(key string) bool {
+		return tools.MatchPattern(pattern, key)
+	}), nil
+}
+
+// Version returns version of the key to be watched by transactions,
+// which is changed by every write of the key including its removal
+func (s *Storage) Version(ctx context.Context, key string) int64 {
+	return s.engine.Version(ctx, key)
+}
+
+// TTL returns remaining time to live of the key
+// or NoExpiration if the key never expires
+func (s *Storage) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	expiresAt, found := s.engine.ExpiresAt(ctx, key)
+	if !found {
+		return 0, false, nil
+	}
+
+	if expiresAt == 0 {
+		return NoExpiration, true, nil
+	}
+
+	ttl := time.UnixMilli(expiresAt).Sub(now())
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	return ttl, true, nil
+}
+
+// notifyWritten notifies about the key if it has been written without error
+func (s *Storage) notifyWritten(ctx context.Context, key, command string, err error) {
+	if err == nil {
+		s.written(ctx, key, command)
+	}
+}
+
+// write applies values (nil value means deletion) under the locks of
+// their partitions, which are held until log is written to WAL, so
+// order of the records in WAL matches the order of the writes
+func (s *Storage) write(ctx context.Context, values map[string]*string, expirations map[string]int64, log func() error) error {
+	_, _, err := s.engine.Commit(ctx, nil, values, expirations, log)
+	return err
+}
+
+// remove deletes the keys like write and returns the keys existed
+// before, only strings are read by the engine, so the error of the
+// read means that the key holds the value of another type
+func (s *Storage) remove(ctx context.Context, keys []string, log func() error) ([]string, error) {
+	var removed []string
+	err := s.engine.Execute(ctx, keys, func(read func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error) {
+		values := make(map[string]*string, len(keys))
+		for _, key := range keys {
+			if _, found := values[key]; found {
+				continue
+			}
+
+			values[key] = nil
+			if _, _, found, err := read(key); found || err != nil {
+				removed = append(removed, key)
+			}
+		}
+
+		if err := log(); err != nil {
+			return nil, nil, err
+		}
+
+		return values, nil, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// logValue returns function logging the value computed by the
+// engine, which keeps expiration time of the key to be replayed
+func (s *Storage) logValue(ctx context.Context, key string) func(string, int64) error {
+	return func(value string, expiresAt int64) error {
+		if s.wal == nil {
+			return nil
+		}
+
+		var future tools.FutureError
+		if expiresAt != 0 {
+			future = s.wal.SetWithExpiration(ctx, key, value, expiresAt)
+		} else {
+			future = s.wal.Set(ctx, key, value)
+		}
+
+		return future.Get()
+	}
+}
+
 func (s *Storage) synchronizeReplica() {
 	for logs := range s.stream {
 		s.applyLogs(logs)
 	}
 }
 
+// applyLogs replays the logs and notifies about the changes,
+// deletions of missing keys are replayed without notifications
 func (s *Storage) applyLogs(logs []wal.LogData) {
 	for _, log := range logs {
-		switch log.CommandID {
-		case compute.SetCommandID:
-			s.engine.Set(context.Background(), log.Arguments[0], log.Arguments[1])
-		case compute.DelCommandID:
-			s.engine.Del(context.Background(), log.Arguments[0])
+		ctx := context.WithValue(context.Background(), "tx", log.LSN)
+		missing := log.CommandID == compute.DelCommandID &&
+			len(log.Arguments) != 0 && s.engine.Type(ctx, log.Arguments[0]) == ""
+
+		s.applyLog(ctx, log)
+		if !missing {
+			s.applied(ctx, log.CommandID, log.Arguments)
 		}
 	}
 }
+
+func (s *Storage) applyLog(ctx context.Context, log wal.LogData) {
+	var err error
+	switch log.CommandID {
+	case compute.SetCommandID:
+		if len(log.Arguments) == 3 {
+			if expiresAt, ok := s.parseExpiration(log); ok {
+				s.engine.SetWithExpiration(ctx, log.Arguments[0], log.Arguments[1], expiresAt)
+			}
+		} else {
+			s.engine.Set(ctx, log.Arguments[0], log.Arguments[1])
+		}
+	case compute.DelCommandID:
+		s.engine.Del(ctx, log.Arguments[0])
+	case compute.ExpireCommandID:
+		if expiresAt, ok := s.parseExpiration(log); ok {
+			_, err = s.engine.Expire(ctx, log.Arguments[0], expiresAt, noLog)
+		}
+	case compute.PersistCommandID:
+		_, err = s.engine.Persist(ctx, log.Arguments[0], noLog)
+	case compute.HSetCommandID:
+		_, err = s.engine.HSet(ctx, log.Arguments[0], log.Arguments[1:], noLog)
+	case compute.HDelCommandID:
+		_, err = s.engine.HDel(ctx, log.Arguments[0], log.Arguments[1:], noLog)
+	case compute.LPushCommandID:
+		_, err = s.engine.LPush(ctx, log.Arguments[0], log.Arguments[1:], noLog)
+	case compute.RPushCommandID:
+		_, err = s.engine.RPush(ctx, log.Arguments[0], log.Arguments[1:], noLog)
+	case compute.LPopCommandID:
+		_, _, err = s.engine.LPop(ctx, log.Arguments[0], noLog)
+	case compute.RPopCommandID:
+		_, _, err = s.engine.RPop(ctx, log.Arguments[0], noLog)
+	case compute.LTrimCommandID:
+		err = s.applyLTrim(ctx, log)
+	case compute.SAddCommandID:
+		_, err = s.engine.SAdd(ctx, log.Arguments[0], log.Arguments[1:], noLog)
+	case compute.SRemCommandID:
+		_, err = s.engine.SRem(ctx, log.Arguments[0], log.Arguments[1:], noLog)
+	case compute.ZAddCommandID:
+		err = s.applyZAdd(ctx, log)
+	case compute.ZRemCommandID:
+		_, err = s.engine.ZRem(ctx, log.Arguments[0], log.Arguments[1:], noLog)
+	}
+
+	if err != nil {
+		s.logger.Error("failed to apply log from WAL", zap.Int64("lsn", log.LSN), zap.Error(err))
+	}
+}
+
+func (s *Storage) applyLTrim(ctx context.Context, log wal.LogData) error {
+	start, err := strconv.Atoi(log.Arguments[1])
+	if err != nil {
+		return err
+	}
+
+	stop, err := strconv.Atoi(log.Arguments[2])
+	if err != nil {
+		return err
+	}
+
+	return s.engine.LTrim(ctx, log.Arguments[0], start, stop, noLog)
+}
+
+func (s *Storage) applyZAdd(ctx context.Context, log wal.LogData) error {
+	scores, err := parseScores(log.Arguments[1:])
+	if err != nil {
+		return err
+	}
+
+	_, err = s.engine.ZAdd(ctx, log.Arguments[0], scores, noLog)
+	return err
+}
+
+func (s *Storage) parseExpiration(log wal.LogData) (int64, bool) {
+	expiresAt, err := strconv.ParseInt(log.Arguments[len(log.Arguments)-1], 10, 64)
+	if err != nil {
+		s.logger.Error("failed to parse expiration from WAL", zap.Int64("lsn", log.LSN), zap.Error(err))
+		return 0, false
+	}
+
+	return expiresAt, true
+}
+
+// noLog is used to apply logs, which are already in WAL
+func noLog() error {
+	return nil
+}
+
+func formatExpiration(expiresAt int64) string {
+	return strconv.FormatInt(expiresAt, 10)
+}
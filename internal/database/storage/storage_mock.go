@@ -7,72 +7,807 @@ package storage
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
 	wal "github.com/passsquale/key-value-storage/internal/database/storage/wal"
 	tools "github.com/passsquale/key-value-storage/internal/tools"
+)
+
+// MockEngine is a mock of Engine interface.
+type MockEngine struct {
+	ctrl     *gomock.Controller
+	recorder *MockEngineMockRecorder
+}
+
+// MockEngineMockRecorder is the mock recorder for MockEngine.
+type MockEngineMockRecorder struct {
+	mock *MockEngine
+}
+
+// NewMockEngine creates a new mock instance.
+func NewMockEngine(ctrl *gomock.Controller) *MockEngine {
+	mock := &MockEngine{ctrl: ctrl}
+	mock.recorder = &MockEngineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockEngine) Append(arg0 context.Context, arg1, arg2 string, arg3 func(string, int64) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockEngineMockRecorder) Append(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockEngine)(nil).Append), arg0, arg1, arg2, arg3)
+}
+
+// Commit mocks base method.
+func (m *MockEngine) Commit(arg0 context.Context, arg1 map[string]int64, arg2 map[string]*string, arg3 map[string]int64, arg4 func() error) (bool, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockEngineMockRecorder) Commit(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockEngine)(nil).Commit), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CompareAndSet mocks base method.
+func (m *MockEngine) CompareAndSet(arg0 context.Context, arg1, arg2, arg3 string, arg4 func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSet", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareAndSet indicates an expected call of CompareAndSet.
+func (mr *MockEngineMockRecorder) CompareAndSet(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSet", reflect.TypeOf((*MockEngine)(nil).CompareAndSet), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Del mocks base method.
+func (m *MockEngine) Del(arg0 context.Context, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Del", arg0, arg1)
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockEngineMockRecorder) Del(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockEngine)(nil).Del), arg0, arg1)
+}
+
+// Execute mocks base method.
+func (m *MockEngine) Execute(arg0 context.Context, arg1 []string, arg2 func(func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockEngineMockRecorder) Execute(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockEngine)(nil).Execute), arg0, arg1, arg2)
+}
+
+// Expire mocks base method.
+func (m *MockEngine) Expire(arg0 context.Context, arg1 string, arg2 int64, arg3 func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockEngineMockRecorder) Expire(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockEngine)(nil).Expire), arg0, arg1, arg2, arg3)
+}
+
+// ExpiresAt mocks base method.
+func (m *MockEngine) ExpiresAt(arg0 context.Context, arg1 string) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpiresAt", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// ExpiresAt indicates an expected call of ExpiresAt.
+func (mr *MockEngineMockRecorder) ExpiresAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpiresAt", reflect.TypeOf((*MockEngine)(nil).ExpiresAt), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockEngine) Get(arg0 context.Context, arg1 string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockEngineMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEngine)(nil).Get), arg0, arg1)
+}
+
+// GetDel mocks base method.
+func (m *MockEngine) GetDel(arg0 context.Context, arg1 string, arg2 func() error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDel", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDel indicates an expected call of GetDel.
+func (mr *MockEngineMockRecorder) GetDel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDel", reflect.TypeOf((*MockEngine)(nil).GetDel), arg0, arg1, arg2)
+}
+
+// GetSet mocks base method.
+func (m *MockEngine) GetSet(arg0 context.Context, arg1, arg2 string, arg3 func(string, int64) error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSet", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSet indicates an expected call of GetSet.
+func (mr *MockEngineMockRecorder) GetSet(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSet", reflect.TypeOf((*MockEngine)(nil).GetSet), arg0, arg1, arg2, arg3)
+}
+
+// HDel mocks base method.
+func (m *MockEngine) HDel(arg0 context.Context, arg1 string, arg2 []string, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HDel", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockEngineMockRecorder) HDel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockEngine)(nil).HDel), arg0, arg1, arg2, arg3)
+}
+
+// HExists mocks base method.
+func (m *MockEngine) HExists(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HExists", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HExists indicates an expected call of HExists.
+func (mr *MockEngineMockRecorder) HExists(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HExists", reflect.TypeOf((*MockEngine)(nil).HExists), arg0, arg1, arg2)
+}
+
+// HGet mocks base method.
+func (m *MockEngine) HGet(arg0 context.Context, arg1, arg2 string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockEngineMockRecorder) HGet(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockEngine)(nil).HGet), arg0, arg1, arg2)
+}
+
+// HGetAll mocks base method.
+func (m *MockEngine) HGetAll(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockEngineMockRecorder) HGetAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockEngine)(nil).HGetAll), arg0, arg1)
+}
+
+// HIncrBy mocks base method.
+func (m *MockEngine) HIncrBy(arg0 context.Context, arg1, arg2 string, arg3 int64, arg4 func(string) error) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HIncrBy", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HIncrBy indicates an expected call of HIncrBy.
+func (mr *MockEngineMockRecorder) HIncrBy(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HIncrBy", reflect.TypeOf((*MockEngine)(nil).HIncrBy), arg0, arg1, arg2, arg3, arg4)
+}
+
+// HLen mocks base method.
+func (m *MockEngine) HLen(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HLen", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HLen indicates an expected call of HLen.
+func (mr *MockEngineMockRecorder) HLen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HLen", reflect.TypeOf((*MockEngine)(nil).HLen), arg0, arg1)
+}
+
+// HSet mocks base method.
+func (m *MockEngine) HSet(arg0 context.Context, arg1 string, arg2 []string, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSet", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockEngineMockRecorder) HSet(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockEngine)(nil).HSet), arg0, arg1, arg2, arg3)
+}
+
+// IncrBy mocks base method.
+func (m *MockEngine) IncrBy(arg0 context.Context, arg1 string, arg2 int64, arg3 func(string, int64) error) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockEngineMockRecorder) IncrBy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockEngine)(nil).IncrBy), arg0, arg1, arg2, arg3)
+}
+
+// IncrByFloat mocks base method.
+func (m *MockEngine) IncrByFloat(arg0 context.Context, arg1 string, arg2 float64, arg3 func(string, int64) error) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrByFloat", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrByFloat indicates an expected call of IncrByFloat.
+func (mr *MockEngineMockRecorder) IncrByFloat(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrByFloat", reflect.TypeOf((*MockEngine)(nil).IncrByFloat), arg0, arg1, arg2, arg3)
+}
+
+// Keys mocks base method.
+func (m *MockEngine) Keys(arg0 context.Context, arg1 func(string) bool) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockEngineMockRecorder) Keys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockEngine)(nil).Keys), arg0, arg1)
+}
+
+// LIndex mocks base method.
+func (m *MockEngine) LIndex(arg0 context.Context, arg1 string, arg2 int) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LIndex", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LIndex indicates an expected call of LIndex.
+func (mr *MockEngineMockRecorder) LIndex(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LIndex", reflect.TypeOf((*MockEngine)(nil).LIndex), arg0, arg1, arg2)
+}
+
+// LLen mocks base method.
+func (m *MockEngine) LLen(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LLen", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LLen indicates an expected call of LLen.
+func (mr *MockEngineMockRecorder) LLen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LLen", reflect.TypeOf((*MockEngine)(nil).LLen), arg0, arg1)
+}
+
+// LPop mocks base method.
+func (m *MockEngine) LPop(arg0 context.Context, arg1 string, arg2 func() error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPop", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LPop indicates an expected call of LPop.
+func (mr *MockEngineMockRecorder) LPop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPop", reflect.TypeOf((*MockEngine)(nil).LPop), arg0, arg1, arg2)
+}
+
+// LPush mocks base method.
+func (m *MockEngine) LPush(arg0 context.Context, arg1 string, arg2 []string, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPush", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockEngineMockRecorder) LPush(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockEngine)(nil).LPush), arg0, arg1, arg2, arg3)
+}
+
+// LRange mocks base method.
+func (m *MockEngine) LRange(arg0 context.Context, arg1 string, arg2, arg3 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LRange indicates an expected call of LRange.
+func (mr *MockEngineMockRecorder) LRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LRange", reflect.TypeOf((*MockEngine)(nil).LRange), arg0, arg1, arg2, arg3)
+}
+
+// LTrim mocks base method.
+func (m *MockEngine) LTrim(arg0 context.Context, arg1 string, arg2, arg3 int, arg4 func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LTrim", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LTrim indicates an expected call of LTrim.
+func (mr *MockEngineMockRecorder) LTrim(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LTrim", reflect.TypeOf((*MockEngine)(nil).LTrim), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Persist mocks base method.
+func (m *MockEngine) Persist(arg0 context.Context, arg1 string, arg2 func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persist", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockEngineMockRecorder) Persist(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockEngine)(nil).Persist), arg0, arg1, arg2)
+}
+
+// RPop mocks base method.
+func (m *MockEngine) RPop(arg0 context.Context, arg1 string, arg2 func() error) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPop", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RPop indicates an expected call of RPop.
+func (mr *MockEngineMockRecorder) RPop(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPop", reflect.TypeOf((*MockEngine)(nil).RPop), arg0, arg1, arg2)
+}
+
+// RPush mocks base method.
+func (m *MockEngine) RPush(arg0 context.Context, arg1 string, arg2 []string, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPush", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RPush indicates an expected call of RPush.
+func (mr *MockEngineMockRecorder) RPush(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPush", reflect.TypeOf((*MockEngine)(nil).RPush), arg0, arg1, arg2, arg3)
+}
+
+// SAdd mocks base method.
+func (m *MockEngine) SAdd(arg0 context.Context, arg1 string, arg2 []string, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SAdd", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockEngineMockRecorder) SAdd(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockEngine)(nil).SAdd), arg0, arg1, arg2, arg3)
+}
+
+// SCard mocks base method.
+func (m *MockEngine) SCard(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SCard", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SCard indicates an expected call of SCard.
+func (mr *MockEngineMockRecorder) SCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SCard", reflect.TypeOf((*MockEngine)(nil).SCard), arg0, arg1)
+}
+
+// SDiff mocks base method.
+func (m *MockEngine) SDiff(arg0 context.Context, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SDiff", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SDiff indicates an expected call of SDiff.
+func (mr *MockEngineMockRecorder) SDiff(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SDiff", reflect.TypeOf((*MockEngine)(nil).SDiff), arg0, arg1)
+}
+
+// SInter mocks base method.
+func (m *MockEngine) SInter(arg0 context.Context, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SInter", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SInter indicates an expected call of SInter.
+func (mr *MockEngineMockRecorder) SInter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SInter", reflect.TypeOf((*MockEngine)(nil).SInter), arg0, arg1)
+}
+
+// SIsMember mocks base method.
+func (m *MockEngine) SIsMember(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SIsMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SIsMember indicates an expected call of SIsMember.
+func (mr *MockEngineMockRecorder) SIsMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SIsMember", reflect.TypeOf((*MockEngine)(nil).SIsMember), arg0, arg1, arg2)
+}
+
+// SMembers mocks base method.
+func (m *MockEngine) SMembers(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockEngineMockRecorder) SMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockEngine)(nil).SMembers), arg0, arg1)
+}
+
+// SRem mocks base method.
+func (m *MockEngine) SRem(arg0 context.Context, arg1 string, arg2 []string, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SRem", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockEngineMockRecorder) SRem(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockEngine)(nil).SRem), arg0, arg1, arg2, arg3)
+}
+
+// SUnion mocks base method.
+func (m *MockEngine) SUnion(arg0 context.Context, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SUnion", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SUnion indicates an expected call of SUnion.
+func (mr *MockEngineMockRecorder) SUnion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SUnion", reflect.TypeOf((*MockEngine)(nil).SUnion), arg0, arg1)
+}
 
-	gomock "github.com/golang/mock/gomock"
-)
+// Scan mocks base method.
+func (m *MockEngine) Scan(arg0 context.Context, arg1 string, arg2 int, arg3 func(string) bool) ([]string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
 
-// MockEngine is a mock of Engine interface.
-type MockEngine struct {
-	ctrl     *gomock.Controller
-	recorder *MockEngineMockRecorder
+// Scan indicates an expected call of Scan.
+func (mr *MockEngineMockRecorder) Scan(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockEngine)(nil).Scan), arg0, arg1, arg2, arg3)
 }
 
-// MockEngineMockRecorder is the mock recorder for MockEngine.
-type MockEngineMockRecorder struct {
-	mock *MockEngine
+// Set mocks base method.
+func (m *MockEngine) Set(arg0 context.Context, arg1, arg2 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", arg0, arg1, arg2)
 }
 
-// NewMockEngine creates a new mock instance.
-func NewMockEngine(ctrl *gomock.Controller) *MockEngine {
-	mock := &MockEngine{ctrl: ctrl}
-	mock.recorder = &MockEngineMockRecorder{mock}
-	return mock
+// Set indicates an expected call of Set.
+func (mr *MockEngineMockRecorder) Set(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockEngine)(nil).Set), arg0, arg1, arg2)
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
-	return m.recorder
+// SetIf mocks base method.
+func (m *MockEngine) SetIf(arg0 context.Context, arg1, arg2 string, arg3 int64, arg4 bool, arg5 func(string, int64) error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIf", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Del mocks base method.
-func (m *MockEngine) Del(arg0 context.Context, arg1 string) {
+// SetIf indicates an expected call of SetIf.
+func (mr *MockEngineMockRecorder) SetIf(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIf", reflect.TypeOf((*MockEngine)(nil).SetIf), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// SetRange mocks base method.
+func (m *MockEngine) SetRange(arg0 context.Context, arg1 string, arg2 int, arg3 string, arg4 func(string, int64) error) (int, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Del", arg0, arg1)
+	ret := m.ctrl.Call(m, "SetRange", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Del indicates an expected call of Del.
-func (mr *MockEngineMockRecorder) Del(arg0, arg1 interface{}) *gomock.Call {
+// SetRange indicates an expected call of SetRange.
+func (mr *MockEngineMockRecorder) SetRange(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockEngine)(nil).Del), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRange", reflect.TypeOf((*MockEngine)(nil).SetRange), arg0, arg1, arg2, arg3, arg4)
 }
 
-// Get mocks base method.
-func (m *MockEngine) Get(arg0 context.Context, arg1 string) (string, bool) {
+// SetWithExpiration mocks base method.
+func (m *MockEngine) SetWithExpiration(arg0 context.Context, arg1, arg2 string, arg3 int64) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	m.ctrl.Call(m, "SetWithExpiration", arg0, arg1, arg2, arg3)
+}
+
+// SetWithExpiration indicates an expected call of SetWithExpiration.
+func (mr *MockEngineMockRecorder) SetWithExpiration(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithExpiration", reflect.TypeOf((*MockEngine)(nil).SetWithExpiration), arg0, arg1, arg2, arg3)
+}
+
+// StartExpiration mocks base method.
+func (m *MockEngine) StartExpiration(arg0 context.Context, arg1 time.Duration, arg2 func(string)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StartExpiration", arg0, arg1, arg2)
+}
+
+// StartExpiration indicates an expected call of StartExpiration.
+func (mr *MockEngineMockRecorder) StartExpiration(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExpiration", reflect.TypeOf((*MockEngine)(nil).StartExpiration), arg0, arg1, arg2)
+}
+
+// Type mocks base method.
+func (m *MockEngine) Type(arg0 context.Context, arg1 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Type", arg0, arg1)
 	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Type indicates an expected call of Type.
+func (mr *MockEngineMockRecorder) Type(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Type", reflect.TypeOf((*MockEngine)(nil).Type), arg0, arg1)
+}
+
+// Version mocks base method.
+func (m *MockEngine) Version(arg0 context.Context, arg1 string) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockEngineMockRecorder) Version(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockEngine)(nil).Version), arg0, arg1)
+}
+
+// ZAdd mocks base method.
+func (m *MockEngine) ZAdd(arg0 context.Context, arg1 string, arg2 map[string]float64, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZAdd", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockEngineMockRecorder) ZAdd(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockEngine)(nil).ZAdd), arg0, arg1, arg2, arg3)
+}
+
+// ZCard mocks base method.
+func (m *MockEngine) ZCard(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZCard", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZCard indicates an expected call of ZCard.
+func (mr *MockEngineMockRecorder) ZCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZCard", reflect.TypeOf((*MockEngine)(nil).ZCard), arg0, arg1)
+}
+
+// ZRange mocks base method.
+func (m *MockEngine) ZRange(arg0 context.Context, arg1 string, arg2, arg3 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRange indicates an expected call of ZRange.
+func (mr *MockEngineMockRecorder) ZRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRange", reflect.TypeOf((*MockEngine)(nil).ZRange), arg0, arg1, arg2, arg3)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockEngine) ZRangeByScore(arg0 context.Context, arg1 string, arg2, arg3 float64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockEngineMockRecorder) ZRangeByScore(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockEngine)(nil).ZRangeByScore), arg0, arg1, arg2, arg3)
+}
+
+// ZRank mocks base method.
+func (m *MockEngine) ZRank(arg0 context.Context, arg1, arg2 string) (int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRank", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ZRank indicates an expected call of ZRank.
+func (mr *MockEngineMockRecorder) ZRank(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRank", reflect.TypeOf((*MockEngine)(nil).ZRank), arg0, arg1, arg2)
+}
+
+// ZRem mocks base method.
+func (m *MockEngine) ZRem(arg0 context.Context, arg1 string, arg2 []string, arg3 func() error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRem", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockEngineMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+// ZRem indicates an expected call of ZRem.
+func (mr *MockEngineMockRecorder) ZRem(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEngine)(nil).Get), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockEngine)(nil).ZRem), arg0, arg1, arg2, arg3)
 }
 
-// Set mocks base method.
-func (m *MockEngine) Set(arg0 context.Context, arg1, arg2 string) {
+// ZScore mocks base method.
+func (m *MockEngine) ZScore(arg0 context.Context, arg1, arg2 string) (float64, bool, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Set", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ZScore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// Set indicates an expected call of Set.
-func (mr *MockEngineMockRecorder) Set(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ZScore indicates an expected call of ZScore.
+func (mr *MockEngineMockRecorder) ZScore(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockEngine)(nil).Set), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScore", reflect.TypeOf((*MockEngine)(nil).ZScore), arg0, arg1, arg2)
 }
 
 // MockWAL is a mock of WAL interface.
@@ -112,6 +847,160 @@ func (mr *MockWALMockRecorder) Del(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockWAL)(nil).Del), arg0, arg1)
 }
 
+// Expire mocks base method.
+func (m *MockWAL) Expire(arg0 context.Context, arg1 string, arg2 int64) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockWALMockRecorder) Expire(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockWAL)(nil).Expire), arg0, arg1, arg2)
+}
+
+// HDel mocks base method.
+func (m *MockWAL) HDel(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HDel", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockWALMockRecorder) HDel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockWAL)(nil).HDel), arg0, arg1, arg2)
+}
+
+// HSet mocks base method.
+func (m *MockWAL) HSet(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockWALMockRecorder) HSet(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockWAL)(nil).HSet), arg0, arg1, arg2)
+}
+
+// LPop mocks base method.
+func (m *MockWAL) LPop(arg0 context.Context, arg1 string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPop", arg0, arg1)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// LPop indicates an expected call of LPop.
+func (mr *MockWALMockRecorder) LPop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPop", reflect.TypeOf((*MockWAL)(nil).LPop), arg0, arg1)
+}
+
+// LPush mocks base method.
+func (m *MockWAL) LPush(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPush", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockWALMockRecorder) LPush(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockWAL)(nil).LPush), arg0, arg1, arg2)
+}
+
+// LTrim mocks base method.
+func (m *MockWAL) LTrim(arg0 context.Context, arg1 string, arg2, arg3 int) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LTrim", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// LTrim indicates an expected call of LTrim.
+func (mr *MockWALMockRecorder) LTrim(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LTrim", reflect.TypeOf((*MockWAL)(nil).LTrim), arg0, arg1, arg2, arg3)
+}
+
+// MDel mocks base method.
+func (m *MockWAL) MDel(arg0 context.Context, arg1 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MDel", arg0, arg1)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// MDel indicates an expected call of MDel.
+func (mr *MockWALMockRecorder) MDel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MDel", reflect.TypeOf((*MockWAL)(nil).MDel), arg0, arg1)
+}
+
+// MSet mocks base method.
+func (m *MockWAL) MSet(arg0 context.Context, arg1 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MSet", arg0, arg1)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// MSet indicates an expected call of MSet.
+func (mr *MockWALMockRecorder) MSet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockWAL)(nil).MSet), arg0, arg1)
+}
+
+// Persist mocks base method.
+func (m *MockWAL) Persist(arg0 context.Context, arg1 string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persist", arg0, arg1)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// Persist indicates an expected call of Persist.
+func (mr *MockWALMockRecorder) Persist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persist", reflect.TypeOf((*MockWAL)(nil).Persist), arg0, arg1)
+}
+
+// RPop mocks base method.
+func (m *MockWAL) RPop(arg0 context.Context, arg1 string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPop", arg0, arg1)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// RPop indicates an expected call of RPop.
+func (mr *MockWALMockRecorder) RPop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPop", reflect.TypeOf((*MockWAL)(nil).RPop), arg0, arg1)
+}
+
+// RPush mocks base method.
+func (m *MockWAL) RPush(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPush", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// RPush indicates an expected call of RPush.
+func (mr *MockWALMockRecorder) RPush(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPush", reflect.TypeOf((*MockWAL)(nil).RPush), arg0, arg1, arg2)
+}
+
 // Recover mocks base method.
 func (m *MockWAL) Recover() ([]wal.LogData, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +1016,34 @@ func (mr *MockWALMockRecorder) Recover() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recover", reflect.TypeOf((*MockWAL)(nil).Recover))
 }
 
+// SAdd mocks base method.
+func (m *MockWAL) SAdd(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SAdd", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockWALMockRecorder) SAdd(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockWAL)(nil).SAdd), arg0, arg1, arg2)
+}
+
+// SRem mocks base method.
+func (m *MockWAL) SRem(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SRem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockWALMockRecorder) SRem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockWAL)(nil).SRem), arg0, arg1, arg2)
+}
+
 // Set mocks base method.
 func (m *MockWAL) Set(arg0 context.Context, arg1, arg2 string) tools.FutureError {
 	m.ctrl.T.Helper()
@@ -141,6 +1058,20 @@ func (mr *MockWALMockRecorder) Set(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockWAL)(nil).Set), arg0, arg1, arg2)
 }
 
+// SetWithExpiration mocks base method.
+func (m *MockWAL) SetWithExpiration(arg0 context.Context, arg1, arg2 string, arg3 int64) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithExpiration", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// SetWithExpiration indicates an expected call of SetWithExpiration.
+func (mr *MockWALMockRecorder) SetWithExpiration(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithExpiration", reflect.TypeOf((*MockWAL)(nil).SetWithExpiration), arg0, arg1, arg2, arg3)
+}
+
 // Shutdown mocks base method.
 func (m *MockWAL) Shutdown() {
 	m.ctrl.T.Helper()
@@ -164,3 +1095,45 @@ func (mr *MockWALMockRecorder) Start() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockWAL)(nil).Start))
 }
+
+// Write mocks base method.
+func (m *MockWAL) Write(arg0 context.Context, arg1 []wal.LogData) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", arg0, arg1)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockWALMockRecorder) Write(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockWAL)(nil).Write), arg0, arg1)
+}
+
+// ZAdd mocks base method.
+func (m *MockWAL) ZAdd(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZAdd", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockWALMockRecorder) ZAdd(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockWAL)(nil).ZAdd), arg0, arg1, arg2)
+}
+
+// ZRem mocks base method.
+func (m *MockWAL) ZRem(arg0 context.Context, arg1 string, arg2 []string) tools.FutureError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(tools.FutureError)
+	return ret0
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockWALMockRecorder) ZRem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockWAL)(nil).ZRem), arg0, arg1, arg2)
+}
@@ -4,14 +4,40 @@ import (
 	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
 	"github.com/passsquale/key-value-storage/internal/tools"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"testing"
+	"time"
 )
 
 // mockgen -source=storage.go -destination=storage_mock.go -package=storage
 
+// commitWithLog is called instead of Engine.Commit of the mocks
+// to log the writes to WAL the same way as the engine does
+func commitWithLog(_ context.Context, _ map[string]int64, _ map[string]*string, _ map[string]int64, log func() error) (bool, []string, error) {
+	if err := log(); err != nil {
+		return false, nil, err
+	}
+
+	return true, nil, nil
+}
+
+// executeOver returns function called instead of Engine.Execute
+// of the mocks, which runs the writes over the string values
+func executeOver(values map[string]string) func(context.Context, []string, func(func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error)) error {
+	return func(_ context.Context, _ []string, run func(func(string) (string, int64, bool, error)) (map[string]*string, map[string]int64, error)) error {
+		_, _, err := run(func(key string) (string, int64, bool, error) {
+			value, found := values[key]
+			return value, 0, found, nil
+		})
+
+		return err
+	}
+}
+
 func TestNewStorage(t *testing.T) {
 	t.Parallel()
 
@@ -59,7 +85,14 @@ func TestSetWithWALError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	engine := NewMockEngine(ctrl)
 
+	value := "value"
+	engine.EXPECT().
+		Commit(ctx, gomock.Nil(), map[string]*string{"key": &value}, gomock.Nil(), gomock.Any()).
+		DoAndReturn(commitWithLog)
+
 	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
 	wal.EXPECT().
 		Set(ctx, "key", "value").
 		Return(tools.NewFuture(result))
@@ -81,10 +114,14 @@ func TestSuccessfulSet(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	engine := NewMockEngine(ctrl)
+	value := "value"
 	engine.EXPECT().
-		Set(ctx, "key", "value")
+		Commit(ctx, gomock.Nil(), map[string]*string{"key": &value}, gomock.Nil(), gomock.Any()).
+		DoAndReturn(commitWithLog)
 
 	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
 	wal.EXPECT().
 		Set(ctx, "key", "value").
 		Return(tools.NewFuture(result))
@@ -148,7 +185,7 @@ func TestDelWithCanceledContext(t *testing.T) {
 	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
 	require.NoError(t, err)
 
-	err = storage.Del(ctxWithCancel, "key")
+	_, err = storage.Del(ctxWithCancel, "key")
 	require.Error(t, err, context.Canceled)
 }
 
@@ -162,8 +199,13 @@ func TestDelWithWALError(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		Execute(ctx, []string{"key"}, gomock.Any()).
+		DoAndReturn(executeOver(map[string]string{"key": "value"}))
 
 	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
 	wal.EXPECT().
 		Del(ctx, "key").
 		Return(tools.NewFuture(result))
@@ -171,7 +213,7 @@ func TestDelWithWALError(t *testing.T) {
 	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
 	require.NoError(t, err)
 
-	err = storage.Del(ctx, "key")
+	_, err = storage.Del(ctx, "key")
 	require.Error(t, err, "wal error")
 }
 
@@ -186,9 +228,12 @@ func TestSuccessfulDel(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	engine := NewMockEngine(ctrl)
 	engine.EXPECT().
-		Del(ctx, "key")
+		Execute(ctx, []string{"key"}, gomock.Any()).
+		DoAndReturn(executeOver(map[string]string{"key": "value"}))
 
 	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
 	wal.EXPECT().
 		Del(ctx, "key").
 		Return(tools.NewFuture(result))
@@ -196,6 +241,446 @@ func TestSuccessfulDel(t *testing.T) {
 	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
 	require.NoError(t, err)
 
-	err = storage.Del(ctx, "key")
+	deleted, err := storage.Del(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, deleted)
+}
+
+func TestSuccessfulSetWithTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		Commit(ctx, gomock.Nil(), gomock.Len(1), gomock.Len(1), gomock.Any()).
+		DoAndReturn(commitWithLog)
+
+	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
+	wal.EXPECT().
+		SetWithExpiration(ctx, "key", "value", gomock.Any()).
+		Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	err = storage.SetWithTTL(ctx, "key", "value", time.Minute)
+	require.NoError(t, err)
+}
+
+func TestSuccessfulExpireAndPersist(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+
+	// changes are logged by the engine under the partition lock
+	gomock.InOrder(
+		engine.EXPECT().
+			Expire(ctx, "key", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ int64, log func() error) (bool, error) {
+				return true, log()
+			}),
+		walMock.EXPECT().
+			Expire(ctx, "key", gomock.Any()).
+			Return(tools.NewFuture(result)),
+	)
+	engine.EXPECT().
+		Persist(ctx, "key", gomock.Any()).
+		Return(false, nil)
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	updated, err := storage.Expire(ctx, "key", time.Minute)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	updated, err = storage.Persist(ctx, "key")
+	require.NoError(t, err)
+	require.False(t, updated)
+}
+
+func TestTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		ExpiresAt(ctx, "missing_key").
+		Return(int64(0), false)
+	engine.EXPECT().
+		ExpiresAt(ctx, "persistent_key").
+		Return(int64(0), true)
+	engine.EXPECT().
+		ExpiresAt(ctx, "volatile_key").
+		Return(time.Now().Add(time.Hour).UnixMilli(), true)
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	_, found, err := storage.TTL(ctx, "missing_key")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	ttl, found, err := storage.TTL(ctx, "persistent_key")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, NoExpiration, ttl)
+
+	ttl, found, err = storage.TTL(ctx, "volatile_key")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.InDelta(t, time.Hour, ttl, float64(time.Second))
+}
+
+func TestRecoverExpirations(t *testing.T) {
+	t.Parallel()
+
+	logs := []wal.LogData{
+		{LSN: 1, CommandID: compute.SetCommandID, Arguments: []string{"key_1", "value_1", "1000"}},
+		{LSN: 2, CommandID: compute.SetCommandID, Arguments: []string{"key_2", "value_2"}},
+		{LSN: 3, CommandID: compute.ExpireCommandID, Arguments: []string{"key_2", "2000"}},
+		{LSN: 4, CommandID: compute.PersistCommandID, Arguments: []string{"key_1"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	gomock.InOrder(
+		engine.EXPECT().SetWithExpiration(gomock.Any(), "key_1", "value_1", int64(1000)),
+		engine.EXPECT().Set(gomock.Any(), "key_2", "value_2"),
+		engine.EXPECT().Expire(gomock.Any(), "key_2", int64(2000), gomock.Any()).Return(true, nil),
+		engine.EXPECT().Persist(gomock.Any(), "key_1", gomock.Any()).Return(true, nil),
+	)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(logs, nil)
+	walMock.EXPECT().Start()
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, storage)
+}
+
+func TestSuccessfulMSetAndMDel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 2)
+	result <- nil
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	first, second := "value_1", "value_2"
+	gomock.InOrder(
+		engine.EXPECT().
+			Commit(ctx, gomock.Nil(), map[string]*string{"key_1": &first, "key_2": &second}, gomock.Nil(), gomock.Any()).
+			DoAndReturn(commitWithLog),
+		engine.EXPECT().
+			Execute(ctx, []string{"key_1", "key_2", "key_1"}, gomock.Any()).
+			DoAndReturn(executeOver(map[string]string{"key_1": first})),
+	)
+
+	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
+	wal.EXPECT().
+		MSet(ctx, []string{"key_1", "value_1", "key_2", "value_2"}).
+		Return(tools.NewFuture(result))
+	wal.EXPECT().
+		MDel(ctx, []string{"key_1", "key_2", "key_1"}).
+		Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	err = storage.MSet(ctx, []string{"key_1", "value_1", "key_2", "value_2"})
+	require.NoError(t, err)
+
+	removed, err := storage.MDel(ctx, []string{"key_1", "key_2", "key_1"})
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+}
+
+func TestMSetWithWALError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- errors.New("wal error")
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		Commit(ctx, gomock.Nil(), gomock.Len(1), gomock.Nil(), gomock.Any()).
+		DoAndReturn(commitWithLog)
+
+	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
+	wal.EXPECT().
+		MSet(ctx, []string{"key_1", "value_1"}).
+		Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	err = storage.MSet(ctx, []string{"key_1", "value_1"})
+	require.Error(t, err, "wal error")
+}
+
+func TestSuccessfulMGet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().Get(ctx, "key_1").Return("value_1", true)
+	engine.EXPECT().Get(ctx, "key_2").Return("", false)
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	values, err := storage.MGet(ctx, []string{"key_1", "key_2"})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(values))
+	require.Equal(t, "value_1", *values[0])
+	require.Nil(t, values[1])
+}
+
+func TestGetMissingKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().Get(ctx, "key").Return("", false)
+	engine.EXPECT().Type(ctx, "key").Return("")
+	engine.EXPECT().Get(ctx, "hash").Return("", false)
+	engine.EXPECT().Type(ctx, "hash").Return("hash")
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	value, err := storage.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, "", value)
+
+	_, err = storage.Get(ctx, "hash")
+	require.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestExists(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().Type(ctx, "key_1").Return("string").Times(2)
+	engine.EXPECT().Type(ctx, "key_2").Return("")
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	count, err := storage.Exists(ctx, []string{"key_1", "key_2", "key_1"})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestCompareAndSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		CompareAndSet(ctx, "key_1", "value_1", "value_2", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _ string, log func() error) (bool, error) {
+			return true, log()
+		})
+
+	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
+	wal.EXPECT().Set(ctx, "key_1", "value_2").Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	updated, err := storage.CompareAndSet(ctx, "key_1", "value_1", "value_2")
+	require.NoError(t, err)
+	require.True(t, updated)
+}
+
+func TestIncrBy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 2)
+	result <- nil
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		IncrBy(ctx, "key_1", int64(5), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int64, log func(string, int64) error) (int64, error) {
+			return 15, log("15", 0)
+		})
+	engine.EXPECT().
+		IncrByFloat(ctx, "key_2", 0.5, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ float64, log func(string, int64) error) (float64, error) {
+			return 1.5, log("1.5", 100)
+		})
+
+	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
+	wal.EXPECT().Set(ctx, "key_1", "15").Return(tools.NewFuture(result))
+	wal.EXPECT().SetWithExpiration(ctx, "key_2", "1.5", int64(100)).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	value, err := storage.IncrBy(ctx, "key_1", 5)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), value)
+
+	floatValue, err := storage.IncrByFloat(ctx, "key_2", 0.5)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, floatValue)
+}
+
+func TestGetRange(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().Get(ctx, "key_1").Return("This is a string", true).AnyTimes()
+	engine.EXPECT().Get(ctx, "key_2").Return("", false)
+	engine.EXPECT().Type(ctx, "key_2").Return("")
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	tests := []struct {
+		start  int
+		end    int
+		result string
+	}{
+		{start: 0, end: 3, result: "This"},
+		{start: -3, end: -1, result: "ing"},
+		{start: 0, end: -1, result: "This is a string"},
+		{start: 10, end: 100, result: "string"},
+		{start: -100, end: 3, result: "This"},
+		{start: 5, end: 3, result: ""},
+		{start: 100, end: 200, result: ""},
+	}
+
+	for _, test := range tests {
+		value, err := storage.GetRange(ctx, "key_1", test.start, test.end)
+		require.NoError(t, err)
+		require.Equal(t, test.result, value)
+	}
+
+	value, err := storage.GetRange(ctx, "key_2", 0, -1)
+	require.NoError(t, err)
+	require.Empty(t, value)
+}
+
+func TestGetDel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		GetDel(ctx, "key_1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, log func() error) (string, bool, error) {
+			return "value_1", true, log()
+		})
+	engine.EXPECT().GetDel(ctx, "key_2", gomock.Any()).Return("", false, nil)
+
+	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
+	wal.EXPECT().Del(ctx, "key_1").Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	value, err := storage.GetDel(ctx, "key_1")
+	require.NoError(t, err)
+	require.Equal(t, "value_1", value)
+
+	_, err = storage.GetDel(ctx, "key_2")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSetIf(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().
+		SetIf(ctx, "key_1", "value_1", gomock.Any(), false, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, value string, expiresAt int64, _ bool, log func(string, int64) error) (bool, error) {
+			require.NotZero(t, expiresAt)
+			return true, log(value, expiresAt)
+		})
+	engine.EXPECT().SetIf(ctx, "key_2", "value_2", int64(0), true, gomock.Any()).Return(false, nil)
+
+	wal := NewMockWAL(ctrl)
+	wal.EXPECT().Recover().Return(nil, nil)
+	wal.EXPECT().Start()
+	wal.EXPECT().SetWithExpiration(ctx, "key_1", "value_1", gomock.Any()).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, wal, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	updated, err := storage.SetIf(ctx, "key_1", "value_1", time.Minute, false)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	updated, err = storage.SetIf(ctx, "key_2", "value_2", 0, true)
 	require.NoError(t, err)
+	require.False(t, updated)
 }
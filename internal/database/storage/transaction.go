@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"time"
+)
+
+// Transaction buffers writes until commit, reads
+// inside the transaction see its own buffered writes
+type Transaction struct {
+	storage     *Storage
+	logs        []wal.LogData
+	values      map[string]*string
+	expirations map[string]int64
+	watches     map[string]int64
+}
+
+func (s *Storage) Begin() *Transaction {
+	return &Transaction{
+		storage:     s,
+		values:      make(map[string]*string),
+		expirations: make(map[string]int64),
+		watches:     make(map[string]int64),
+	}
+}
+
+// Watch makes commit fail with ErrWatchedKeyChanged if version
+// of the key differs from the given one at the moment of commit
+func (t *Transaction) Watch(key string, version int64) {
+	t.watches[key] = version
+}
+
+func (t *Transaction) Set(_ context.Context, key, value string) error {
+	t.logs = append(t.logs, wal.LogData{CommandID: compute.SetCommandID, Arguments: []string{key, value}})
+	t.values[key] = &value
+	delete(t.expirations, key)
+	return nil
+}
+
+func (t *Transaction) SetWithTTL(_ context.Context, key, value string, ttl time.Duration) error {
+	expiresAt := now().Add(ttl).UnixMilli()
+	t.logs = append(t.logs, wal.LogData{
+		CommandID: compute.SetCommandID,
+		Arguments: []string{key, value, formatExpiration(expiresAt)},
+	})
+
+	t.values[key] = &value
+	t.expirations[key] = expiresAt
+	return nil
+}
+
+// Del buffers deletion of the key and reports whether
+// it exists, taking the buffered writes into account
+func (t *Transaction) Del(ctx context.Context, key string) (bool, error) {
+	count, err := t.Exists(ctx, []string{key})
+	if err != nil {
+		return false, err
+	}
+
+	t.logs = append(t.logs, wal.LogData{CommandID: compute.DelCommandID, Arguments: []string{key}})
+	t.values[key] = nil
+	delete(t.expirations, key)
+	return count != 0, nil
+}
+
+func (t *Transaction) MSet(ctx context.Context, pairs []string) error {
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		_ = t.Set(ctx, pairs[idx], pairs[idx+1])
+	}
+
+	return nil
+}
+
+func (t *Transaction) MDel(ctx context.Context, keys []string) (int, error) {
+	count := 0
+	for _, key := range keys {
+		deleted, err := t.Del(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+
+		if deleted {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (t *Transaction) Get(ctx context.Context, key string) (string, error) {
+	value, found := t.values[key]
+	if !found {
+		return t.storage.Get(ctx, key)
+	}
+
+	if value == nil {
+		return "", ErrNotFound
+	}
+
+	return *value, nil
+}
+
+func (t *Transaction) MGet(ctx context.Context, keys []string) ([]*string, error) {
+	values := make([]*string, 0, len(keys))
+	for _, key := range keys {
+		value, err := t.Get(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			values = append(values, nil)
+		} else if err != nil {
+			return nil, err
+		} else {
+			values = append(values, &value)
+		}
+	}
+
+	return values, nil
+}
+
+func (t *Transaction) Exists(ctx context.Context, keys []string) (int, error) {
+	count := 0
+	for _, key := range keys {
+		value, found := t.values[key]
+		if !found {
+			existing, err := t.storage.Exists(ctx, []string{key})
+			if err != nil {
+				return 0, err
+			}
+
+			count += existing
+		} else if value != nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Commit logs all buffered writes to WAL as one batch and applies
+// them atomically under the locks of the partitions, transactions
+// with watched keys are aborted if any of them has been changed
+func (t *Transaction) Commit(ctx context.Context) error {
+	if t.storage.stream != nil {
+		return ErrSlaveWrite
+	}
+
+	if len(t.logs) == 0 && len(t.watches) == 0 {
+		return nil
+	}
+
+	committed, removed, err := t.storage.engine.Commit(ctx, t.watches, t.values, t.expirations, func() error {
+		if t.storage.wal == nil || len(t.logs) == 0 {
+			return nil
+		}
+
+		future := t.storage.wal.Write(ctx, t.logs)
+		return future.Get()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !committed {
+		return ErrWatchedKeyChanged
+	}
+
+	t.notifyWritten(ctx, removed)
+	return nil
+}
+
+// notifyWritten notifies about the set keys and the removed
+// ones, deletions of missing keys aren't notified
+func (t *Transaction) notifyWritten(ctx context.Context, removed []string) {
+	for key, value := range t.values {
+		if value != nil {
+			t.storage.written(ctx, key, compute.SetCommand)
+		}
+	}
+
+	for _, key := range removed {
+		t.storage.deleted(ctx, key, compute.DelCommand)
+	}
+}
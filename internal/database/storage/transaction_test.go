@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/passsquale/key-value-storage/internal/database/compute"
+	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestTransactionReadsOwnWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().Get(ctx, "key_3").Return("value_3", true)
+	engine.EXPECT().Type(ctx, "key_3").Return("string")
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	transaction := storage.Begin()
+	require.NoError(t, transaction.Set(ctx, "key_1", "value_1"))
+	require.NoError(t, transaction.MSet(ctx, []string{"key_2", "value_2", "key_4", "value_4"}))
+	deleted, err := transaction.Del(ctx, "key_4")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	value, err := transaction.Get(ctx, "key_1")
+	require.NoError(t, err)
+	require.Equal(t, "value_1", value)
+
+	_, err = transaction.Get(ctx, "key_4")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	values, err := transaction.MGet(ctx, []string{"key_2", "key_3", "key_4"})
+	require.NoError(t, err)
+	require.Equal(t, "value_2", *values[0])
+	require.Equal(t, "value_3", *values[1])
+	require.Nil(t, values[2])
+
+	count, err := transaction.Exists(ctx, []string{"key_1", "key_3", "key_4"})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestTransactionCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- nil
+
+	logs := []wal.LogData{
+		{CommandID: compute.SetCommandID, Arguments: []string{"key_1", "value_1"}},
+		{CommandID: compute.DelCommandID, Arguments: []string{"key_2"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+
+	value := "value_1"
+	values := map[string]*string{"key_1": &value, "key_2": nil}
+	engine.EXPECT().Type(ctx, "key_2").Return("")
+	engine.EXPECT().
+		Commit(ctx, map[string]int64{}, values, map[string]int64{}, gomock.Any()).
+		DoAndReturn(commitWithLog)
+
+	walMock.EXPECT().Write(ctx, logs).Return(tools.NewFuture(result))
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	transaction := storage.Begin()
+	require.NoError(t, transaction.Set(ctx, "key_1", "value_1"))
+	deleted, err := transaction.Del(ctx, "key_2")
+	require.NoError(t, err)
+	require.False(t, deleted)
+	require.NoError(t, transaction.Commit(ctx))
+}
+
+func TestTransactionCommitWithWALError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	result := make(chan error, 1)
+	result <- errors.New("wal error")
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+	walMock.EXPECT().Write(ctx, gomock.Any()).Return(tools.NewFuture(result))
+
+	engine.EXPECT().
+		Commit(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(commitWithLog)
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	transaction := storage.Begin()
+	require.NoError(t, transaction.Set(ctx, "key_1", "value_1"))
+	require.Error(t, transaction.Commit(ctx), "wal error")
+}
+
+func TestEmptyTransactionCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+
+	walMock := NewMockWAL(ctrl)
+	walMock.EXPECT().Recover().Return(nil, nil)
+	walMock.EXPECT().Start()
+
+	storage, err := NewStorage(engine, walMock, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, storage.Begin().Commit(ctx))
+}
+
+func TestWatchedTransactionCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), "tx", int64(555))
+
+	ctrl := gomock.NewController(t)
+	engine := NewMockEngine(ctrl)
+	engine.EXPECT().Version(ctx, "key_1").Return(int64(10))
+	engine.EXPECT().
+		Commit(ctx, map[string]int64{"key_1": 10}, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(false, nil, nil)
+
+	storage, err := NewStorage(engine, nil, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	transaction := storage.Begin()
+	transaction.Watch("key_1", storage.Version(ctx, "key_1"))
+	require.NoError(t, transaction.Set(ctx, "key_1", "value_1"))
+	require.ErrorIs(t, transaction.Commit(ctx), ErrWatchedKeyChanged)
+}
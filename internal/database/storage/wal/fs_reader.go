@@ -42,7 +42,8 @@ func (r *FSReader) ReadLogs() ([]LogData, error) {
 		logs = append(logs, segmentedLogs...)
 	}
 
-	sort.Slice(logs, func(i, j int) bool {
+	// logs of the same transaction share LSN and must keep their order
+	sort.SliceStable(logs, func(i, j int) bool {
 		return logs[i].LSN < logs[j].LSN
 	})
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/passsquale/key-value-storage/internal/database/compute"
 	"github.com/passsquale/key-value-storage/internal/tools"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -80,10 +81,97 @@ func (w *WAL) Set(ctx context.Context, key, value string) tools.FutureError {
 	return w.push(ctx, compute.SetCommandID, []string{key, value})
 }
 
+func (w *WAL) SetWithExpiration(ctx context.Context, key, value string, expiresAt int64) tools.FutureError {
+	return w.push(ctx, compute.SetCommandID, []string{key, value, strconv.FormatInt(expiresAt, 10)})
+}
+
 func (w *WAL) Del(ctx context.Context, key string) tools.FutureError {
 	return w.push(ctx, compute.DelCommandID, []string{key})
 }
 
+func (w *WAL) MSet(ctx context.Context, pairs []string) tools.FutureError {
+	logs := make([]LogData, 0, len(pairs)/2)
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		logs = append(logs, LogData{CommandID: compute.SetCommandID, Arguments: []string{pairs[idx], pairs[idx+1]}})
+	}
+
+	return w.pushBatch(ctx, logs)
+}
+
+func (w *WAL) MDel(ctx context.Context, keys []string) tools.FutureError {
+	logs := make([]LogData, 0, len(keys))
+	for _, key := range keys {
+		logs = append(logs, LogData{CommandID: compute.DelCommandID, Arguments: []string{key}})
+	}
+
+	return w.pushBatch(ctx, logs)
+}
+
+// HSet logs fields with values of the hash, which
+// are replayed by setting all of them again
+func (w *WAL) HSet(ctx context.Context, key string, pairs []string) tools.FutureError {
+	return w.push(ctx, compute.HSetCommandID, append([]string{key}, pairs...))
+}
+
+func (w *WAL) HDel(ctx context.Context, key string, fields []string) tools.FutureError {
+	return w.push(ctx, compute.HDelCommandID, append([]string{key}, fields...))
+}
+
+func (w *WAL) LPush(ctx context.Context, key string, values []string) tools.FutureError {
+	return w.push(ctx, compute.LPushCommandID, append([]string{key}, values...))
+}
+
+func (w *WAL) RPush(ctx context.Context, key string, values []string) tools.FutureError {
+	return w.push(ctx, compute.RPushCommandID, append([]string{key}, values...))
+}
+
+// LPop logs removal of the first value without the value itself,
+// replaying logs in order pops the same value again
+func (w *WAL) LPop(ctx context.Context, key string) tools.FutureError {
+	return w.push(ctx, compute.LPopCommandID, []string{key})
+}
+
+func (w *WAL) RPop(ctx context.Context, key string) tools.FutureError {
+	return w.push(ctx, compute.RPopCommandID, []string{key})
+}
+
+func (w *WAL) LTrim(ctx context.Context, key string, start, stop int) tools.FutureError {
+	return w.push(ctx, compute.LTrimCommandID, []string{key, strconv.Itoa(start), strconv.Itoa(stop)})
+}
+
+func (w *WAL) SAdd(ctx context.Context, key string, members []string) tools.FutureError {
+	return w.push(ctx, compute.SAddCommandID, append([]string{key}, members...))
+}
+
+func (w *WAL) SRem(ctx context.Context, key string, members []string) tools.FutureError {
+	return w.push(ctx, compute.SRemCommandID, append([]string{key}, members...))
+}
+
+// ZAdd logs scores followed by members of the sorted set
+// in the same format as arguments of the ZADD command
+func (w *WAL) ZAdd(ctx context.Context, key string, pairs []string) tools.FutureError {
+	return w.push(ctx, compute.ZAddCommandID, append([]string{key}, pairs...))
+}
+
+func (w *WAL) ZRem(ctx context.Context, key string, members []string) tools.FutureError {
+	return w.push(ctx, compute.ZRemCommandID, append([]string{key}, members...))
+}
+
+// Write logs records of a committed transaction as one batch
+func (w *WAL) Write(ctx context.Context, logs []LogData) tools.FutureError {
+	return w.pushBatch(ctx, logs)
+}
+
+// Expire logs absolute expiration time instead of relative
+// timeout, so replaying logs later doesn't prolong key life
+func (w *WAL) Expire(ctx context.Context, key string, expiresAt int64) tools.FutureError {
+	return w.push(ctx, compute.ExpireCommandID, []string{key, strconv.FormatInt(expiresAt, 10)})
+}
+
+func (w *WAL) Persist(ctx context.Context, key string) tools.FutureError {
+	return w.push(ctx, compute.PersistCommandID, []string{key})
+}
+
 func (w *WAL) flushBatch() {
 	var batch []Log
 	tools.WithLock(&w.mutex, func() {
@@ -99,16 +187,25 @@ func (w *WAL) flushBatch() {
 }
 
 func (w *WAL) push(ctx context.Context, commandID int, args []string) tools.FutureError {
+	return w.pushBatch(ctx, []LogData{{CommandID: commandID, Arguments: args}})
+}
+
+// pushBatch appends all logs to the same flushing batch, so
+// they are written to a segment together or not at all
+func (w *WAL) pushBatch(ctx context.Context, logs []LogData) tools.FutureError {
 	txID := ctx.Value("tx").(int64)
-	record := NewLog(txID, commandID, args)
+	records := make([]Log, 0, len(logs))
+	for _, log := range logs {
+		records = append(records, NewLog(txID, log.CommandID, log.Arguments))
+	}
 
 	tools.WithLock(&w.mutex, func() {
-		w.batch = append(w.batch, record)
-		if len(w.batch) == w.maxBatchSize {
+		w.batch = append(w.batch, records...)
+		if len(w.batch) >= w.maxBatchSize {
 			w.batches <- w.batch
 			w.batch = nil
 		}
 	})
 
-	return record.Result()
+	return records[len(records)-1].Result()
 }
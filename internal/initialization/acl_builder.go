@@ -0,0 +1,26 @@
+package initialization
+
+import (
+	"github.com/passsquale/key-value-storage/internal/configuration"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+)
+
+// CreateACL returns nil ACL if it isn't configured,
+// so the database is available without authentication
+func CreateACL(cfg *configuration.ACLConfig) (*acl.ACL, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	users := make([]*acl.User, 0, len(cfg.Users))
+	for _, userCfg := range cfg.Users {
+		user, err := acl.NewUser(userCfg.Name, userCfg.PasswordHash, userCfg.Categories, userCfg.Keys)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return acl.NewACL(users)
+}
@@ -0,0 +1,62 @@
+package initialization
+
+import (
+	"github.com/passsquale/key-value-storage/internal/configuration"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreateACLWithoutConfig(t *testing.T) {
+	t.Parallel()
+
+	accessList, err := CreateACL(nil)
+	require.NoError(t, err)
+	require.Nil(t, accessList)
+}
+
+func TestCreateACLWithoutUsers(t *testing.T) {
+	t.Parallel()
+
+	accessList, err := CreateACL(&configuration.ACLConfig{})
+	require.Error(t, err)
+	require.Nil(t, accessList)
+}
+
+func TestCreateACLWithPlainTextPassword(t *testing.T) {
+	t.Parallel()
+
+	cfg := &configuration.ACLConfig{
+		Users: []configuration.ACLUserConfig{{Name: "user", PasswordHash: "password"}},
+	}
+
+	accessList, err := CreateACL(cfg)
+	require.Error(t, err)
+	require.Nil(t, accessList)
+}
+
+func TestCreateACL(t *testing.T) {
+	t.Parallel()
+
+	passwordHash, err := acl.HashPassword("password")
+	require.NoError(t, err)
+
+	cfg := &configuration.ACLConfig{
+		Users: []configuration.ACLUserConfig{
+			{
+				Name:         "user",
+				PasswordHash: passwordHash,
+				Categories:   []string{"read"},
+				Keys:         []string{"user:*"},
+			},
+		},
+	}
+
+	accessList, err := CreateACL(cfg)
+	require.NoError(t, err)
+
+	user, err := accessList.Authenticate("user", "password")
+	require.NoError(t, err)
+	require.True(t, user.HasCategory(acl.ReadCategory))
+	require.True(t, user.CanAccessKey("user:1"))
+}
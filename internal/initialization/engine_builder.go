@@ -6,6 +6,7 @@ import (
 	"github.com/passsquale/key-value-storage/internal/database/storage"
 	"github.com/passsquale/key-value-storage/internal/database/storage/engine/in_memory"
 	"go.uber.org/zap"
+	"time"
 )
 
 const (
@@ -17,6 +18,7 @@ var supportedEngineTypes = map[string]struct{}{
 }
 
 const defaultPartitionsNumber = 10
+const defaultExpirationInterval = time.Second
 
 func CreateEngine(cfg *configuration.EngineConfig, logger *zap.Logger) (storage.Engine, error) {
 	if cfg == nil {
@@ -6,22 +6,28 @@ import (
 	"fmt"
 	"github.com/passsquale/key-value-storage/internal/configuration"
 	"github.com/passsquale/key-value-storage/internal/database"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
 	"github.com/passsquale/key-value-storage/internal/database/compute"
 	"github.com/passsquale/key-value-storage/internal/database/storage"
 	"github.com/passsquale/key-value-storage/internal/database/storage/replication"
 	"github.com/passsquale/key-value-storage/internal/database/storage/wal"
 	"github.com/passsquale/key-value-storage/internal/network"
+	"github.com/passsquale/key-value-storage/internal/rpc"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
 type Initializer struct {
-	wal    storage.WAL
-	engine storage.Engine
-	server *network.TCPServer
-	slave  *replication.Slave
-	master *replication.Master
-	logger *zap.Logger
+	wal     storage.WAL
+	engine  storage.Engine
+	servers []*network.TCPServer
+	gateway *gateway
+	rpcAddr string
+	slave   *replication.Slave
+	master  *replication.Master
+	events  []string
+	acl     *acl.ACL
+	logger  *zap.Logger
 }
 
 func NewInitializer(cfg *configuration.Config) (*Initializer, error) {
@@ -49,21 +55,49 @@ func NewInitializer(cfg *configuration.Config) (*Initializer, error) {
 		return nil, fmt.Errorf("failed to initialize network: %w", err)
 	}
 
+	servers := []*network.TCPServer{tcpServer}
+	for _, listenerCfg := range cfg.Listeners {
+		server, err := CreateNetwork(listenerCfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize listener: %w", err)
+		}
+
+		servers = append(servers, server)
+	}
+
 	replica, err := CreateReplica(cfg.Replication, cfg.WAL, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize replication: %w", err)
 	}
 
+	accessList, err := CreateACL(cfg.ACL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize acl: %w", err)
+	}
+
 	initializer := &Initializer{
-		engine: dbEngine,
-		server: tcpServer,
-		logger: logger,
+		engine:  dbEngine,
+		servers: servers,
+		acl:     accessList,
+		logger:  logger,
 	}
 
 	if wal != nil {
 		initializer.wal = wal
 	}
 
+	if cfg.Network != nil && cfg.Network.HTTPAddress != "" {
+		initializer.gateway = createGateway(cfg.Network)
+	}
+
+	if cfg.Network != nil {
+		initializer.rpcAddr = cfg.Network.GRPCAddress
+	}
+
+	if cfg.Notifications != nil {
+		initializer.events = cfg.Notifications.Events
+	}
+
 	initializer.initializeReplication(replica)
 	return initializer, nil
 }
@@ -84,19 +118,62 @@ func (i *Initializer) StartDatabase(ctx context.Context) error {
 		return err
 	}
 
+	if i.acl != nil {
+		database.EnableACL(i.acl)
+	}
+
+	if len(i.events) != 0 {
+		if err := storage.EnableNotifications(i.events, database.PublishEvent); err != nil {
+			i.logger.Error("failed to enable notifications", zap.Error(err))
+			return err
+		}
+	}
+
+	var rpcServer *rpc.Server
+	if i.rpcAddr != "" {
+		rpcServer, err = i.createRPCServer(storage, database.IDGenerator())
+		if err != nil {
+			return err
+		}
+	}
+
 	group, groupCtx := errgroup.WithContext(ctx)
+	storage.StartExpiration(groupCtx, defaultExpirationInterval)
+
+	if rpcServer != nil {
+		group.Go(func() error {
+			return rpcServer.Serve(groupCtx, i.rpcAddr, i.servers[0].TLSConfig())
+		})
+	}
+
 	if i.master != nil {
 		group.Go(func() error {
 			return i.master.HandleSynchronizations(groupCtx)
 		})
 	}
 
-	group.Go(func() error {
-		return i.server.HandleQueries(groupCtx, func(ctx context.Context, query []byte) []byte {
-			response := database.HandleQuery(ctx, string(query))
-			return []byte(response)
+	if i.gateway != nil {
+		group.Go(func() error {
+			handler := database.HTTPHandler(i.gateway.maxBodySize)
+			return i.servers[0].HandleHTTP(groupCtx, i.gateway.address, handler)
+		})
+	}
+
+	for _, server := range i.servers {
+		server := server
+		group.Go(func() error {
+			if protocol := server.Protocol(); protocol != network.TextProtocol {
+				return server.HandleCommands(groupCtx, func(ctx context.Context, arguments []string) []byte {
+					return database.HandleRESPCommand(ctx, arguments, protocol)
+				})
+			}
+
+			return server.HandleQueries(groupCtx, func(ctx context.Context, query []byte) []byte {
+				response := database.HandlePipeline(ctx, string(query))
+				return []byte(response)
+			})
 		})
-	})
+	}
 
 	return group.Wait()
 }
@@ -123,6 +200,28 @@ func (i *Initializer) createComputeLayer() (*compute.Compute, error) {
 	return compute, nil
 }
 
+// createRPCServer creates gRPC server, which watches all
+// keyspace events regardless of notifications config
+func (i *Initializer) createRPCServer(storageLayer *storage.Storage, idGenerator *database.IDGenerator) (*rpc.Server, error) {
+	rpcServer, err := rpc.NewServer(storageLayer, idGenerator, i.logger)
+	if err != nil {
+		i.logger.Error("failed to initialize rpc server", zap.Error(err))
+		return nil, err
+	}
+
+	if i.acl != nil {
+		rpcServer.EnableACL(i.acl)
+	}
+
+	classes := []string{storage.SetEventClass, storage.DelEventClass, storage.ExpiredEventClass}
+	if err := storageLayer.EnableNotifications(classes, rpcServer.PublishEvent); err != nil {
+		i.logger.Error("failed to enable notifications", zap.Error(err))
+		return nil, err
+	}
+
+	return rpcServer, nil
+}
+
 func (i *Initializer) createStorageLayer(ctx context.Context) (*storage.Storage, error) {
 	var replicationStream <-chan []wal.LogData
 	if i.slave != nil {
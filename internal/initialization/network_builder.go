@@ -1,7 +1,9 @@
 package initialization
 
 import (
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"github.com/passsquale/key-value-storage/internal/configuration"
 	"github.com/passsquale/key-value-storage/internal/network"
 	"github.com/passsquale/key-value-storage/internal/tools"
@@ -13,18 +15,25 @@ const defaultServerAddress = "localhost:8080"
 const defaultMaxConnectionNumber = 100
 const defaultMaxMessageSize = 2048
 const defaultIdleTimeout = time.Minute * 5
+const defaultOutputBufferSize = 64
 
 func CreateNetwork(cfg *configuration.NetworkConfig, logger *zap.Logger) (*network.TCPServer, error) {
 	address := defaultServerAddress
 	maxConnectionsNumber := defaultMaxConnectionNumber
 	maxMessageSize := defaultMaxMessageSize
 	idleTimeout := defaultIdleTimeout
+	outputBufferSize := defaultOutputBufferSize
+	protocol := network.TextProtocol
 
 	if cfg != nil {
 		if cfg.Address != "" {
 			address = cfg.Address
 		}
 
+		if cfg.Protocol != "" {
+			protocol = cfg.Protocol
+		}
+
 		if cfg.MaxConnections != 0 {
 			maxConnectionsNumber = cfg.MaxConnections
 		}
@@ -41,7 +50,39 @@ func CreateNetwork(cfg *configuration.NetworkConfig, logger *zap.Logger) (*netwo
 		if cfg.IdleTimeout != 0 {
 			idleTimeout = cfg.IdleTimeout
 		}
+
+		if cfg.OutputBufferSize != 0 {
+			outputBufferSize = cfg.OutputBufferSize
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if cfg != nil {
+		var err error
+		if tlsConfig, err = CreateServerTLSConfig(cfg.TLS); err != nil {
+			return nil, fmt.Errorf("incorrect tls config: %w", err)
+		}
 	}
 
-	return network.NewTCPServer(address, maxConnectionsNumber, maxMessageSize, idleTimeout, logger)
+	return network.NewTCPServerWithProtocol(address, maxConnectionsNumber, maxMessageSize, idleTimeout, outputBufferSize, protocol, tlsConfig, logger)
+}
+
+// gateway is HTTP listener sharing connection
+// limit and logger of the network server
+type gateway struct {
+	address     string
+	maxBodySize int
+}
+
+// createGateway expects the config already validated by CreateNetwork
+func createGateway(cfg *configuration.NetworkConfig) *gateway {
+	maxBodySize := defaultMaxMessageSize
+	if size, err := tools.ParseSize(cfg.MaxMessageSize); err == nil {
+		maxBodySize = size
+	}
+
+	return &gateway{
+		address:     cfg.HTTPAddress,
+		maxBodySize: maxBodySize,
+	}
 }
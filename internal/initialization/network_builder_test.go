@@ -38,3 +38,41 @@ func TestCreateNetwork(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, server)
 }
+
+func TestCreateNetworkWithProtocol(t *testing.T) {
+	t.Parallel()
+
+	server, err := CreateNetwork(&configuration.NetworkConfig{Protocol: "resp3"}, zap.NewNop())
+	require.NoError(t, err)
+	require.Equal(t, "resp3", server.Protocol())
+
+	server, err = CreateNetwork(&configuration.NetworkConfig{Protocol: "http"}, zap.NewNop())
+	require.Error(t, err)
+	require.Nil(t, server)
+}
+
+func TestCreateGateway(t *testing.T) {
+	t.Parallel()
+
+	gateway := createGateway(&configuration.NetworkConfig{HTTPAddress: "localhost:8081"})
+	require.Equal(t, "localhost:8081", gateway.address)
+	require.Equal(t, defaultMaxMessageSize, gateway.maxBodySize)
+
+	gateway = createGateway(&configuration.NetworkConfig{HTTPAddress: "localhost:8081", MaxMessageSize: "4KB"})
+	require.Equal(t, 4096, gateway.maxBodySize)
+}
+
+func TestCreateNetworkWithIncorrectTLS(t *testing.T) {
+	t.Parallel()
+
+	cfg := &configuration.NetworkConfig{
+		TLS: &configuration.TLSConfig{
+			CertFile: "/unknown/server.crt",
+			KeyFile:  "/unknown/server.key",
+		},
+	}
+
+	server, err := CreateNetwork(cfg, zap.NewNop())
+	require.Error(t, err)
+	require.Nil(t, server)
+}
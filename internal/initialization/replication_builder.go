@@ -2,6 +2,7 @@ package initialization
 
 import (
 	"errors"
+	"fmt"
 	"github.com/passsquale/key-value-storage/internal/configuration"
 	"github.com/passsquale/key-value-storage/internal/database/storage/replication"
 	"github.com/passsquale/key-value-storage/internal/network"
@@ -47,17 +48,42 @@ func CreateReplica(
 
 	const maxReplicasNumber = 5
 	const maxMessageSize = 16 << 20
+	const outputBufferSize = 1
 	idleTimeout := syncInterval * 3
 
+	var tlsCfg *configuration.TLSConfig
+	if replicationCfg != nil {
+		tlsCfg = replicationCfg.TLS
+	}
+
 	if replicaType == "master" {
-		server, err := network.NewTCPServer(masterAddress, maxReplicasNumber, maxMessageSize, idleTimeout, logger)
+		tlsConfig, err := CreateServerTLSConfig(tlsCfg)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect tls config: %w", err)
+		}
+
+		server, err := network.NewTCPServerWithProtocol(
+			masterAddress,
+			maxReplicasNumber,
+			maxMessageSize,
+			idleTimeout,
+			outputBufferSize,
+			network.TextProtocol,
+			tlsConfig,
+			logger,
+		)
 		if err != nil {
 			return nil, err
 		}
 
 		return replication.NewMaster(server, walDirectory, logger)
 	} else {
-		client, err := network.NewTCPClient(masterAddress, maxMessageSize, idleTimeout)
+		tlsConfig, err := CreateClientTLSConfig(tlsCfg)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect tls config: %w", err)
+		}
+
+		client, err := network.NewTCPClientWithTLS(masterAddress, maxMessageSize, idleTimeout, tlsConfig)
 		if err != nil {
 			return nil, err
 		}
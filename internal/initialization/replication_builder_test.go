@@ -45,3 +45,27 @@ func TestCreateReplica(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, replica)
 }
+
+func TestCreateReplicaWithIncorrectTLS(t *testing.T) {
+	t.Parallel()
+
+	cfg := &configuration.ReplicationConfig{
+		ReplicaType:   "master",
+		MasterAddress: "localhost:4443",
+		TLS:           &configuration.TLSConfig{},
+	}
+
+	replica, err := CreateReplica(cfg, nil, zap.NewNop())
+	require.Error(t, err)
+	require.Nil(t, replica)
+
+	cfg = &configuration.ReplicationConfig{
+		ReplicaType:   "slave",
+		MasterAddress: "localhost:4444",
+		TLS:           &configuration.TLSConfig{CAFile: "/unknown/ca.crt"},
+	}
+
+	replica, err = CreateReplica(cfg, nil, zap.NewNop())
+	require.Error(t, err)
+	require.Nil(t, replica)
+}
@@ -0,0 +1,26 @@
+package initialization
+
+import (
+	"crypto/tls"
+	"github.com/passsquale/key-value-storage/internal/configuration"
+	"github.com/passsquale/key-value-storage/internal/network"
+)
+
+// CreateServerTLSConfig returns nil config if TLS isn't configured
+func CreateServerTLSConfig(cfg *configuration.TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	return network.NewServerTLSConfig(cfg.CertFile, cfg.KeyFile, cfg.CAFile, cfg.RequireClientCert)
+}
+
+// CreateClientTLSConfig returns nil config if TLS isn't configured,
+// requirement of client certificates is ignored by clients
+func CreateClientTLSConfig(cfg *configuration.TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	return network.NewClientTLSConfig(cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+}
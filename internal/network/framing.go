@@ -0,0 +1,50 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// frameHeaderSize is size of big endian length of the
+// message, which is written before every message
+const frameHeaderSize = 4
+
+var ErrMessageTooLarge = errors.New("message is too large")
+
+// writeMessage writes the message with its length prefix by one
+// write, so concurrent writers of frames aren't needed to be synced
+func writeMessage(writer io.Writer, message []byte) error {
+	frame := make([]byte, frameHeaderSize+len(message))
+	binary.BigEndian.PutUint32(frame, uint32(len(message)))
+	copy(frame[frameHeaderSize:], message)
+
+	_, err := writer.Write(frame)
+	return err
+}
+
+// readMessage reads the whole message into the buffer regardless of how
+// it has been split by reads, the payload of messages larger than the
+// buffer is left unread, so it must be discarded or the stream closed
+func readMessage(reader io.Reader, buffer []byte) ([]byte, int, error) {
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, 0, err
+	}
+
+	size := int(binary.BigEndian.Uint32(header[:]))
+	if size > len(buffer) {
+		return nil, size, fmt.Errorf("%w: %d bytes, limit is %d bytes", ErrMessageTooLarge, size, len(buffer))
+	}
+
+	if _, err := io.ReadFull(reader, buffer[:size]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, size, err
+	}
+
+	return buffer[:size], size, nil
+}
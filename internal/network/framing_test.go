@@ -0,0 +1,59 @@
+package network
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFraming(t *testing.T) {
+	t.Parallel()
+
+	var stream bytes.Buffer
+	require.NoError(t, writeMessage(&stream, []byte("first")))
+	require.NoError(t, writeMessage(&stream, nil))
+	require.NoError(t, writeMessage(&stream, []byte("second message")))
+
+	// every byte is returned by a separate read
+	reader := iotest.OneByteReader(&stream)
+	buffer := make([]byte, 16)
+
+	message, size, err := readMessage(reader, buffer)
+	require.NoError(t, err)
+	require.Equal(t, 5, size)
+	require.Equal(t, "first", string(message))
+
+	message, _, err = readMessage(reader, buffer)
+	require.NoError(t, err)
+	require.Empty(t, message)
+
+	message, _, err = readMessage(reader, buffer)
+	require.NoError(t, err)
+	require.Equal(t, "second message", string(message))
+
+	_, _, err = readMessage(reader, buffer)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestFramingErrors(t *testing.T) {
+	t.Parallel()
+
+	var stream bytes.Buffer
+	require.NoError(t, writeMessage(&stream, []byte("large message")))
+
+	_, size, err := readMessage(&stream, make([]byte, 4))
+	require.ErrorIs(t, err, ErrMessageTooLarge)
+	require.Equal(t, 13, size)
+
+	stream.Reset()
+	require.NoError(t, writeMessage(&stream, []byte("truncated")))
+	stream.Truncate(frameHeaderSize + 3)
+
+	_, _, err = readMessage(&stream, make([]byte, 16))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, _, err = readMessage(bytes.NewReader([]byte{0, 0}), make([]byte, 16))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,110 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const httpShutdownTimeout = 5 * time.Second
+
+// httpUnavailableResponse is written to connections over the limit
+const httpUnavailableResponse = "HTTP/1.1 503 Service Unavailable\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Length: 21\r\n" +
+	"Connection: close\r\n" +
+	"\r\n" +
+	"too many connections\n"
+
+// limitedListener takes a ticket of the semaphore per accepted
+// connection, so the limit is shared with TCP connections, connections
+// over the limit are answered with 503 and closed instead of waiting
+// for a ticket, since the server doesn't accept anything while waiting
+type limitedListener struct {
+	net.Listener
+	semaphore    *tools.Semaphore
+	writeTimeout time.Duration
+}
+
+func (l *limitedListener) Accept() (net.Conn, error) {
+	for {
+		connection, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if l.semaphore.TryAcquire() {
+			return &limitedConnection{Conn: connection, release: l.semaphore.Release}, nil
+		}
+
+		go l.reject(connection)
+	}
+}
+
+func (l *limitedListener) reject(connection net.Conn) {
+	_ = connection.SetWriteDeadline(time.Now().Add(l.writeTimeout))
+	_, _ = connection.Write([]byte(httpUnavailableResponse))
+	_ = connection.Close()
+}
+
+type limitedConnection struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitedConnection) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
+
+// HandleHTTP serves the handler on the address with connection limit, idle
+// timeout, TLS config and logger of the server, requests are canceled and
+// the server is shut down gracefully when the context is done
+func (s *TCPServer) HandleHTTP(ctx context.Context, address string, handler http.Handler) error {
+	listener, err := s.listen(address)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: s.idleTimeout,
+		IdleTimeout:       s.idleTimeout,
+		ErrorLog:          zap.NewStdLog(s.logger),
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			s.logger.Warn("failed to shutdown http server", zap.Error(err))
+		}
+	}()
+
+	err = server.Serve(&limitedListener{
+		Listener:     listener,
+		semaphore:    &s.semaphore,
+		writeTimeout: s.idleTimeout,
+	})
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	<-done
+	return nil
+}
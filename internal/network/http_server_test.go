@@ -0,0 +1,104 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHandleHTTP(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server, err := NewTCPServer(":20008", 1, 2048, time.Minute, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- server.HandleHTTP(ctx, ":20008", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/wait" {
+				<-r.Context().Done()
+				_, _ = w.Write([]byte("canceled"))
+				return
+			}
+
+			_, _ = w.Write([]byte("response"))
+		}))
+	}()
+
+	send := func(connection net.Conn, path string) {
+		_, err := connection.Write([]byte("GET " + path + " HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+		require.NoError(t, err)
+	}
+
+	receive := func(reader *bufio.Reader) (string, error) {
+		response, err := http.ReadResponse(reader, nil)
+		if err != nil {
+			return "", err
+		}
+
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		return string(body), err
+	}
+
+	var first net.Conn
+	require.Eventually(t, func() bool {
+		first, err = net.Dial("tcp", "localhost:20008")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	send(first, "/")
+	body, err := receive(bufio.NewReader(first))
+	require.NoError(t, err)
+	require.Equal(t, "response", body)
+
+	// the only connection ticket is held by the first connection
+	second, err := net.Dial("tcp", "localhost:20008")
+	require.NoError(t, err)
+	response, err := http.ReadResponse(bufio.NewReader(second), nil)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	require.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	_, err = second.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+
+	require.NoError(t, first.Close())
+	var third net.Conn
+	var reader *bufio.Reader
+	require.Eventually(t, func() bool {
+		if third, err = net.Dial("tcp", "localhost:20008"); err != nil {
+			return false
+		}
+
+		reader = bufio.NewReader(third)
+		if _, err = third.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")); err != nil {
+			return false
+		}
+
+		body, err = receive(reader)
+		return err == nil && body == "response"
+	}, time.Second, time.Millisecond*10)
+
+	// requests are canceled on shutdown
+	send(third, "/wait")
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	body, err = receive(reader)
+	require.NoError(t, err)
+	require.Equal(t, "canceled", body)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("server hasn't been stopped")
+	}
+}
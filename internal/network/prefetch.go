@@ -0,0 +1,99 @@
+package network
+
+import (
+	"net"
+	"sync"
+)
+
+// prefetchReader reads the connection ahead of the handled requests by
+// a separate goroutine, so disconnection of the client is noticed while
+// its request is still handled (like blocked BGETDEL), at most limit
+// bytes are read ahead, the error of the connection is returned only
+// after all bytes read before it
+type prefetchReader struct {
+	connection   net.Conn
+	limit        int
+	disconnected func()
+
+	mutex   sync.Mutex
+	changed *sync.Cond
+	buffer  []byte
+	err     error
+	stopped bool
+}
+
+func newPrefetchReader(connection net.Conn, limit int, disconnected func()) *prefetchReader {
+	reader := &prefetchReader{
+		connection:   connection,
+		limit:        max(limit, 1),
+		disconnected: disconnected,
+	}
+
+	reader.changed = sync.NewCond(&reader.mutex)
+	return reader
+}
+
+// run reads the connection until it fails or the reader is stopped,
+// disconnected is called once the connection fails
+func (r *prefetchReader) run() {
+	chunk := make([]byte, r.limit)
+	for {
+		r.mutex.Lock()
+		for len(r.buffer) >= r.limit && !r.stopped {
+			r.changed.Wait()
+		}
+
+		stopped := r.stopped
+		size := r.limit - len(r.buffer)
+		r.mutex.Unlock()
+
+		if stopped {
+			return
+		}
+
+		count, err := r.connection.Read(chunk[:size])
+
+		r.mutex.Lock()
+		r.buffer = append(r.buffer, chunk[:count]...)
+		r.err = err
+		r.changed.Broadcast()
+		r.mutex.Unlock()
+
+		if err != nil {
+			r.disconnected()
+			return
+		}
+	}
+}
+
+func (r *prefetchReader) Read(data []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for len(r.buffer) == 0 && r.err == nil && !r.stopped {
+		r.changed.Wait()
+	}
+
+	if len(r.buffer) == 0 {
+		if r.err == nil {
+			return 0, net.ErrClosed
+		}
+
+		return 0, r.err
+	}
+
+	count := copy(data, r.buffer)
+	r.buffer = r.buffer[count:]
+	r.changed.Broadcast()
+	return count, nil
+}
+
+// stop makes the reading goroutine exit, which
+// is blocked by a read until the connection is closed
+func (r *prefetchReader) stop() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.stopped = true
+	r.changed.Broadcast()
+}
@@ -0,0 +1,166 @@
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	TextProtocol  = "text"
+	RESP2Protocol = "resp2"
+	RESP3Protocol = "resp3"
+)
+
+var errRESPProtocol = errors.New("protocol error")
+
+// readCommand reads RESP array of bulk strings or inline command
+// separated by whitespaces, empty commands have no arguments
+func readCommand(reader *bufio.Reader, maxMessageSize int) ([]string, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count > maxMessageSize {
+		return nil, fmt.Errorf("%w: invalid multibulk length", errRESPProtocol)
+	}
+
+	var size int
+	arguments := make([]string, 0, max(count, 0))
+	for idx := 0; idx < count; idx++ {
+		line, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasPrefix(line, "$") {
+			return nil, fmt.Errorf("%w: expected '$', got '%s'", errRESPProtocol, line)
+		}
+
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("%w: invalid bulk length", errRESPProtocol)
+		}
+
+		size += length
+		if size > maxMessageSize {
+			return nil, fmt.Errorf("%w: limit is %d bytes", ErrMessageTooLarge, maxMessageSize)
+		}
+
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+
+		if data[length] != '\r' || data[length+1] != '\n' {
+			return nil, fmt.Errorf("%w: bulk string isn't terminated by CRLF", errRESPProtocol)
+		}
+
+		arguments = append(arguments, string(data[:length]))
+	}
+
+	return arguments, nil
+}
+
+// readLine reads line terminated by LF or CRLF, lines
+// mustn't be longer than the buffer of the reader
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return "", fmt.Errorf("%w: line is longer than %d bytes", ErrMessageTooLarge, reader.Size())
+		}
+
+		return "", err
+	}
+
+	line = line[:len(line)-1]
+	if len(line) != 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+
+	return string(line), nil
+}
+
+// AppendSimpleString appends RESP simple string, line
+// breaks aren't allowed, so they are replaced by spaces
+func AppendSimpleString(dst []byte, value string) []byte {
+	dst = append(dst, '+')
+	dst = append(dst, removeLineBreaks(value)...)
+	return append(dst, '\r', '\n')
+}
+
+// AppendError appends RESP error, the message is expected
+// to start with error code like "ERR something went wrong"
+func AppendError(dst []byte, message string) []byte {
+	dst = append(dst, '-')
+	dst = append(dst, removeLineBreaks(message)...)
+	return append(dst, '\r', '\n')
+}
+
+func AppendBulkString(dst []byte, value string) []byte {
+	dst = append(dst, '$')
+	dst = strconv.AppendInt(dst, int64(len(value)), 10)
+	dst = append(dst, '\r', '\n')
+	dst = append(dst, value...)
+	return append(dst, '\r', '\n')
+}
+
+func AppendInteger(dst []byte, value int64) []byte {
+	dst = append(dst, ':')
+	dst = strconv.AppendInt(dst, value, 10)
+	return append(dst, '\r', '\n')
+}
+
+// AppendArrayHeader appends header of RESP array,
+// which must be followed by size elements
+func AppendArrayHeader(dst []byte, size int) []byte {
+	dst = append(dst, '*')
+	dst = strconv.AppendInt(dst, int64(size), 10)
+	return append(dst, '\r', '\n')
+}
+
+// AppendMapHeader appends header of RESP3 map, which must be followed
+// by size keys and values, RESP2 has no maps, so flat array is used
+func AppendMapHeader(dst []byte, size int, protocol string) []byte {
+	if protocol != RESP3Protocol {
+		return AppendArrayHeader(dst, size*2)
+	}
+
+	dst = append(dst, '%')
+	dst = strconv.AppendInt(dst, int64(size), 10)
+	return append(dst, '\r', '\n')
+}
+
+// AppendNull appends null bulk string for RESP2
+// and dedicated null type for RESP3
+func AppendNull(dst []byte, protocol string) []byte {
+	if protocol == RESP3Protocol {
+		return append(dst, '_', '\r', '\n')
+	}
+
+	return append(dst, '$', '-', '1', '\r', '\n')
+}
+
+// AppendNullArray appends null array for RESP2
+// and dedicated null type for RESP3
+func AppendNullArray(dst []byte, protocol string) []byte {
+	if protocol == RESP3Protocol {
+		return append(dst, '_', '\r', '\n')
+	}
+
+	return append(dst, '*', '-', '1', '\r', '\n')
+}
+
+func removeLineBreaks(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,110 @@
+package network
+
+import (
+	"bufio"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadCommand(t *testing.T) {
+	t.Parallel()
+
+	stream := "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$11\r\nvalue\r\nwith\r\n" +
+		"GET  key\r\n" +
+		"\r\n" +
+		"*0\r\n" +
+		"*1\r\n$4\r\nPING\r\n"
+
+	// every byte is returned by a separate read
+	reader := bufio.NewReaderSize(iotest.OneByteReader(strings.NewReader(stream)), 64)
+
+	expected := [][]string{
+		{"SET", "key", "value\r\nwith"},
+		{"GET", "key"},
+		{},
+		{},
+		{"PING"},
+	}
+
+	for _, arguments := range expected {
+		command, err := readCommand(reader, 64)
+		require.NoError(t, err)
+		require.Equal(t, arguments, command)
+	}
+
+	_, err := readCommand(reader, 64)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestReadCommandErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		stream string
+		err    error
+	}{
+		"invalid array length": {
+			stream: "*x\r\n",
+			err:    errRESPProtocol,
+		},
+		"array is too long": {
+			stream: "*100\r\n",
+			err:    errRESPProtocol,
+		},
+		"not bulk string": {
+			stream: "*1\r\n+GET\r\n",
+			err:    errRESPProtocol,
+		},
+		"negative bulk length": {
+			stream: "*1\r\n$-1\r\n",
+			err:    errRESPProtocol,
+		},
+		"unterminated bulk string": {
+			stream: "*1\r\n$3\r\nGETX\r\n",
+			err:    errRESPProtocol,
+		},
+		"bulk strings are too large": {
+			stream: "*2\r\n$10\r\n0123456789\r\n$10\r\n0123456789\r\n",
+			err:    ErrMessageTooLarge,
+		},
+		"inline command is too large": {
+			stream: strings.Repeat("x", 100) + "\r\n",
+			err:    ErrMessageTooLarge,
+		},
+		"truncated bulk string": {
+			stream: "*1\r\n$3\r\nGE",
+			err:    io.ErrUnexpectedEOF,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := bufio.NewReaderSize(strings.NewReader(test.stream), 16)
+			_, err := readCommand(reader, 16)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestAppendRESP(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "+OK\r\n", string(AppendSimpleString(nil, "OK")))
+	require.Equal(t, "-ERR invalid  value\r\n", string(AppendError(nil, "ERR invalid\r\nvalue")))
+	require.Equal(t, "$7\r\nva\r\nlue\r\n", string(AppendBulkString(nil, "va\r\nlue")))
+	require.Equal(t, "$0\r\n\r\n", string(AppendBulkString(nil, "")))
+	require.Equal(t, "$-1\r\n", string(AppendNull(nil, RESP2Protocol)))
+	require.Equal(t, "_\r\n", string(AppendNull(nil, RESP3Protocol)))
+	require.Equal(t, ":-12\r\n", string(AppendInteger(nil, -12)))
+	require.Equal(t, "*2\r\n", string(AppendArrayHeader(nil, 2)))
+	require.Equal(t, "*4\r\n", string(AppendMapHeader(nil, 2, RESP2Protocol)))
+	require.Equal(t, "%2\r\n", string(AppendMapHeader(nil, 2, RESP3Protocol)))
+	require.Equal(t, "*-1\r\n", string(AppendNullArray(nil, RESP2Protocol)))
+	require.Equal(t, "_\r\n", string(AppendNullArray(nil, RESP3Protocol)))
+}
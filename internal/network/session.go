@@ -0,0 +1,129 @@
+package network
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrSessionClosed  = errors.New("session is closed")
+	ErrOutputOverflow = errors.New("output buffer is overflowed")
+)
+
+// Session keeps state bound to a single client connection,
+// handlers find it in the context under "session" key
+type Session struct {
+	id     int64
+	mutex  sync.Mutex
+	values map[string]interface{}
+
+	// user is authenticated by the client, it's
+	// empty until the client is authenticated
+	user string
+
+	// output is bounded queue of messages written
+	// to the client by a separate goroutine
+	output     chan []byte
+	closed     chan struct{}
+	closeOnce  sync.Once
+	overflowed atomic.Bool
+	persistent atomic.Bool
+
+	// deadlineMutex orders write deadlines with interruption of the
+	// connection, so the writer doesn't extend the interrupted deadline
+	deadlineMutex sync.Mutex
+}
+
+func NewSession(id int64, outputSize int) *Session {
+	return &Session{
+		id:     id,
+		values: make(map[string]interface{}),
+		output: make(chan []byte, outputSize),
+		closed: make(chan struct{}),
+	}
+}
+
+func (s *Session) ID() int64 {
+	return s.id
+}
+
+func (s *Session) Value(key string) (interface{}, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, found := s.values[key]
+	return value, found
+}
+
+func (s *Session) SetValue(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.values[key] = value
+}
+
+func (s *Session) User() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.user
+}
+
+func (s *Session) SetUser(user string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.user = user
+}
+
+// Push queues unsolicited message to the client without waiting, if
+// the output buffer is full, the client is too slow and it is disconnected
+func (s *Session) Push(message []byte) error {
+	select {
+	case <-s.closed:
+		return ErrSessionClosed
+	default:
+	}
+
+	select {
+	case s.output <- message:
+		return nil
+	default:
+		s.overflowed.Store(true)
+		s.close()
+		return ErrOutputOverflow
+	}
+}
+
+// Done is closed when the client connection is closed
+func (s *Session) Done() <-chan struct{} {
+	return s.closed
+}
+
+// SetPersistent excludes the session from idle timeout, it's used by
+// clients waiting for pushed messages without sending requests
+func (s *Session) SetPersistent(persistent bool) {
+	s.persistent.Store(persistent)
+}
+
+func (s *Session) isPersistent() bool {
+	return s.persistent.Load()
+}
+
+// reply queues response to the request, unlike push it
+// waits for free space to keep the client in sync
+func (s *Session) reply(message []byte) error {
+	select {
+	case s.output <- message:
+		return nil
+	case <-s.closed:
+		return ErrSessionClosed
+	}
+}
+
+func (s *Session) close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
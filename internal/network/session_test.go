@@ -0,0 +1,42 @@
+package network
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	session := NewSession(10, 1)
+	require.Equal(t, int64(10), session.ID())
+
+	_, found := session.Value("key")
+	require.False(t, found)
+
+	session.SetValue("key", "value")
+	value, found := session.Value("key")
+	require.True(t, found)
+	require.Equal(t, "value", value)
+
+	require.Empty(t, session.User())
+	session.SetUser("user")
+	require.Equal(t, "user", session.User())
+}
+
+func TestSessionPushOverflow(t *testing.T) {
+	t.Parallel()
+
+	session := NewSession(1, 1)
+	require.NoError(t, session.Push([]byte("first")))
+	require.ErrorIs(t, session.Push([]byte("second")), ErrOutputOverflow)
+
+	select {
+	case <-session.Done():
+	default:
+		t.Fatal("session hasn't been closed")
+	}
+
+	require.ErrorIs(t, session.Push([]byte("third")), ErrSessionClosed)
+	require.ErrorIs(t, session.reply([]byte("response")), ErrSessionClosed)
+}
@@ -1,7 +1,10 @@
 package network
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"time"
 )
@@ -13,7 +16,21 @@ type TCPClient struct {
 }
 
 func NewTCPClient(address string, maxMessageSize int, idleTimeout time.Duration) (*TCPClient, error) {
-	connection, err := net.Dial("tcp", address)
+	return NewTCPClientWithTLS(address, maxMessageSize, idleTimeout, nil)
+}
+
+// NewTCPClientWithTLS connects to the server over TLS if the config is set,
+// server name is taken from the address unless it's set by the config
+func NewTCPClientWithTLS(address string, maxMessageSize int, idleTimeout time.Duration, tlsConfig *tls.Config) (*TCPClient, error) {
+	var connection net.Conn
+	var err error
+	if tlsConfig != nil {
+		dialer := &net.Dialer{Timeout: idleTimeout}
+		connection, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		connection, err = net.Dial("tcp", address)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial: %w", err)
 	}
@@ -25,20 +42,41 @@ func NewTCPClient(address string, maxMessageSize int, idleTimeout time.Duration)
 	}, nil
 }
 
+// Send sends the request framed by its length and waits for the whole
+// response, requests and responses larger than max message size are
+// rejected with ErrMessageTooLarge
 func (c *TCPClient) Send(request []byte) ([]byte, error) {
-	if err := c.connection.SetDeadline(time.Now().Add(c.idleTimeout)); err != nil {
+	return c.SendWithTimeout(request, c.idleTimeout)
+}
+
+// SendWithTimeout waits for the response up to timeout instead of
+// idle timeout, it is used by blocking queries like BGETDEL
+func (c *TCPClient) SendWithTimeout(request []byte, timeout time.Duration) ([]byte, error) {
+	if len(request) > c.maxMessageSize {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d bytes", ErrMessageTooLarge, len(request), c.maxMessageSize)
+	}
+
+	if err := c.connection.SetDeadline(time.Now().Add(timeout)); err != nil {
 		return nil, err
 	}
 
-	if _, err := c.connection.Write(request); err != nil {
+	if err := writeMessage(c.connection, request); err != nil {
 		return nil, err
 	}
 
-	response := make([]byte, c.maxMessageSize)
-	count, err := c.connection.Read(response)
+	buffer := make([]byte, c.maxMessageSize)
+	response, size, err := readMessage(c.connection, buffer)
+	if errors.Is(err, ErrMessageTooLarge) {
+		// the rest of the response is skipped to
+		// keep the connection usable for next requests
+		if _, discardErr := io.CopyN(io.Discard, c.connection, int64(size)); discardErr != nil {
+			return nil, discardErr
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	return response[:count], nil
+	return response, nil
 }
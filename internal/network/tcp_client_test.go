@@ -25,11 +25,11 @@ func TestTCPClient(t *testing.T) {
 		}
 
 		buffer := make([]byte, 2048)
-		count, err := connection.Read(buffer)
+		message, _, err := readMessage(connection, buffer)
 		require.NoError(t, err)
-		require.True(t, reflect.DeepEqual([]byte(request), buffer[:count]))
+		require.True(t, reflect.DeepEqual([]byte(request), message))
 
-		_, err = connection.Write([]byte(response))
+		err = writeMessage(connection, []byte(response))
 		require.NoError(t, err)
 
 		defer func() {
@@ -68,9 +68,9 @@ func TestTCPIdleClientConnection(t *testing.T) {
 		}
 
 		buffer := make([]byte, 2048)
-		count, err := connection.Read(buffer)
+		message, _, err := readMessage(connection, buffer)
 		require.NoError(t, err)
-		require.True(t, reflect.DeepEqual([]byte(request), buffer[:count]))
+		require.True(t, reflect.DeepEqual([]byte(request), message))
 
 		<-ctx.Done()
 		defer func() {
@@ -89,3 +89,80 @@ func TestTCPIdleClientConnection(t *testing.T) {
 	_, err = client.Send([]byte(request))
 	require.Error(t, err)
 }
+
+func TestTCPClientSendWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", ":10003")
+	require.NoError(t, err)
+
+	go func() {
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() {
+			_ = connection.Close()
+			_ = listener.Close()
+		}()
+
+		buffer := make([]byte, 2048)
+		if _, _, err := readMessage(connection, buffer); err != nil {
+			return
+		}
+
+		time.Sleep(100 * time.Millisecond)
+		_ = writeMessage(connection, []byte("response"))
+	}()
+
+	client, err := NewTCPClient("127.0.0.1:10003", 2048, time.Millisecond*50)
+	require.NoError(t, err)
+
+	buffer, err := client.SendWithTimeout([]byte("request"), time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "response", string(buffer))
+}
+
+func TestTCPClientLargeMessage(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", ":10004")
+	require.NoError(t, err)
+
+	go func() {
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() {
+			_ = connection.Close()
+			_ = listener.Close()
+		}()
+
+		buffer := make([]byte, 2048)
+		for _, response := range []string{"response over the limit", "response"} {
+			if _, _, err := readMessage(connection, buffer); err != nil {
+				return
+			}
+
+			if err := writeMessage(connection, []byte(response)); err != nil {
+				return
+			}
+		}
+	}()
+
+	client, err := NewTCPClient("127.0.0.1:10004", 16, time.Second)
+	require.NoError(t, err)
+
+	_, err = client.Send([]byte("request over the limit"))
+	require.ErrorIs(t, err, ErrMessageTooLarge)
+
+	_, err = client.Send([]byte("request"))
+	require.ErrorIs(t, err, ErrMessageTooLarge)
+
+	response, err := client.Send([]byte("request"))
+	require.NoError(t, err)
+	require.Equal(t, "response", string(response))
+}
@@ -1,7 +1,9 @@
 package network
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/passsquale/key-value-storage/internal/tools"
@@ -9,17 +11,27 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type TCPHandler = func(context.Context, []byte) []byte
 
+// CommandHandler handles commands of RESP protocol, which
+// are already split into arguments, and returns RESP response
+type CommandHandler = func(context.Context, []string) []byte
+
 type TCPServer struct {
 	address     string
+	protocol    string
+	tlsConfig   *tls.Config
 	semaphore   tools.Semaphore
 	idleTimeout time.Duration
 	messageSize int
+	outputSize  int
 	logger      *zap.Logger
+
+	sessionsCounter atomic.Int64
 }
 
 func NewTCPServer(
@@ -27,29 +39,105 @@ func NewTCPServer(
 	maxConnectionsNumber int,
 	maxMessageSize int,
 	idleTimeout time.Duration,
+	outputBufferSize int,
+	logger *zap.Logger,
+) (*TCPServer, error) {
+	return NewTCPServerWithProtocol(address, maxConnectionsNumber, maxMessageSize, idleTimeout, outputBufferSize, TextProtocol, nil, logger)
+}
+
+// NewTCPServerWithProtocol creates server of the text protocol with length
+// prefixed messages, which is handled by HandleQueries, or server of RESP2
+// or RESP3 protocol, which is handled by HandleCommands, connections are
+// accepted over TLS if the config is set
+func NewTCPServerWithProtocol(
+	address string,
+	maxConnectionsNumber int,
+	maxMessageSize int,
+	idleTimeout time.Duration,
+	outputBufferSize int,
+	protocol string,
+	tlsConfig *tls.Config,
 	logger *zap.Logger,
 ) (*TCPServer, error) {
 	if logger == nil {
 		return nil, errors.New("logger is invalid")
 	}
 
+	if protocol != TextProtocol && protocol != RESP2Protocol && protocol != RESP3Protocol {
+		return nil, fmt.Errorf("unknown protocol '%s'", protocol)
+	}
+
 	if maxConnectionsNumber <= 0 {
 		return nil, errors.New("invalid number of max connections")
 	}
 
+	if outputBufferSize <= 0 {
+		return nil, errors.New("invalid output buffer size")
+	}
+
 	return &TCPServer{
 		address:     address,
+		protocol:    protocol,
+		tlsConfig:   tlsConfig,
 		semaphore:   tools.NewSemaphore(maxConnectionsNumber),
 		idleTimeout: idleTimeout,
 		messageSize: maxMessageSize,
+		outputSize:  outputBufferSize,
 		logger:      logger,
 	}, nil
 }
 
+func (s *TCPServer) Protocol() string {
+	return s.protocol
+}
+
+func (s *TCPServer) TLSConfig() *tls.Config {
+	return s.tlsConfig
+}
+
+// listen listens the address over TLS if the server has TLS config
+func (s *TCPServer) listen(address string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+
+	return listener, nil
+}
+
 func (s *TCPServer) HandleQueries(ctx context.Context, handler TCPHandler) error {
-	listener, err := net.Listen("tcp", s.address)
+	if s.protocol != TextProtocol {
+		return fmt.Errorf("queries can't be handled by %s server", s.protocol)
+	}
+
+	return s.serve(ctx, func(ctx, handlerCtx context.Context, connection net.Conn, reader io.Reader, session *Session) {
+		s.readRequests(ctx, handlerCtx, connection, reader, session, handler)
+	})
+}
+
+func (s *TCPServer) HandleCommands(ctx context.Context, handler CommandHandler) error {
+	if s.protocol == TextProtocol {
+		return errors.New("commands can't be handled by text server")
+	}
+
+	return s.serve(ctx, func(ctx, handlerCtx context.Context, connection net.Conn, reader io.Reader, session *Session) {
+		s.readCommands(ctx, handlerCtx, connection, reader, session, handler)
+	})
+}
+
+// connectionReader reads requests of the session from the reader
+// and handles them with the context, which is canceled when the
+// client disconnects, reading is stopped when ctx is done
+type connectionReader = func(ctx, handlerCtx context.Context, connection net.Conn, reader io.Reader, session *Session)
+
+func (s *TCPServer) serve(ctx context.Context, read connectionReader) error {
+	listener, err := s.listen(s.address)
 	if err != nil {
-		return fmt.Errorf("failed to listen: %w", err)
+		return err
 	}
 
 	var wg sync.WaitGroup
@@ -78,7 +166,7 @@ func (s *TCPServer) HandleQueries(ctx context.Context, handler TCPHandler) error
 					wg.Done()
 				}()
 
-				s.handleConnection(ctx, connection, handler)
+				s.handleConnection(ctx, connection, read)
 			}(connection)
 		}
 	}()
@@ -96,32 +184,236 @@ func (s *TCPServer) HandleQueries(ctx context.Context, handler TCPHandler) error
 	return nil
 }
 
-func (s *TCPServer) handleConnection(ctx context.Context, connection net.Conn, handler TCPHandler) {
+// handleConnection reads requests and queues responses to the session
+// output, which is written by a separate goroutine, so handlers are able
+// to push messages to the connection out of request-response order, the
+// connection is read ahead, so handlers blocked by the requests of the
+// disconnected client are canceled
+func (s *TCPServer) handleConnection(ctx context.Context, connection net.Conn, read connectionReader) {
+	session := NewSession(s.sessionsCounter.Add(1), s.outputSize)
+	handlerCtx, disconnected := context.WithCancel(ctx)
+	defer disconnected()
+
+	handlerCtx = context.WithValue(handlerCtx, "session", session)
+	reader := newPrefetchReader(connection, s.messageSize, disconnected)
+
+	prefetched := make(chan struct{})
+	go func() {
+		defer close(prefetched)
+		reader.run()
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		s.writeMessages(connection, session)
+	}()
+
+	go func() {
+		defer wg.Done()
+		s.interruptClosed(connection, session)
+	}()
+
+	read(ctx, handlerCtx, connection, reader, session)
+	session.close()
+	reader.stop()
+	wg.Wait()
+
+	if err := connection.Close(); err != nil {
+		s.logger.Warn("failed to close connection", zap.Error(err))
+	}
+
+	<-prefetched
+}
+
+// setReadDeadline resets idle timeout before next request, sessions
+// waiting for pushed messages aren't limited by idle timeout, as well as
+// connections while their requests are handled, the deadline isn't
+// changed once the connection is interrupted
+func (s *TCPServer) setReadDeadline(connection net.Conn, session *Session, idle bool) bool {
+	var deadline time.Time
+	if idle && !session.isPersistent() {
+		deadline = time.Now().Add(s.idleTimeout)
+	}
+
+	session.deadlineMutex.Lock()
+	defer session.deadlineMutex.Unlock()
+
+	select {
+	case <-session.closed:
+		return false
+	default:
+	}
+
+	if err := connection.SetReadDeadline(deadline); err != nil {
+		s.logger.Warn("failed to set read deadline", zap.Error(err))
+		return false
+	}
+
+	return true
+}
+
+func (s *TCPServer) readRequests(
+	ctx context.Context,
+	handlerCtx context.Context,
+	connection net.Conn,
+	reader io.Reader,
+	session *Session,
+	handler TCPHandler,
+) {
 	request := make([]byte, s.messageSize)
 
-	for {
-		if err := connection.SetDeadline(time.Now().Add(s.idleTimeout)); err != nil {
-			s.logger.Warn("failed to set read deadline", zap.Error(err))
+	for s.setReadDeadline(connection, session, true) {
+		message, _, err := readMessage(reader, request)
+		if err != nil {
+			if errors.Is(err, ErrMessageTooLarge) {
+				s.logger.Warn("message is too large, disconnecting", zap.Int64("session", session.ID()), zap.Error(err))
+				_ = session.reply([]byte(fmt.Sprintf("[error] %s", err.Error())))
+			} else if err != io.EOF && !session.overflowed.Load() {
+				s.logger.Warn("failed to read", zap.Error(err))
+			}
+
+			break
+		}
+
+		if !s.setReadDeadline(connection, session, false) {
 			break
 		}
 
-		count, err := connection.Read(request)
+		response := handler(handlerCtx, message)
+		if err := session.reply(response); err != nil {
+			break
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+}
+
+// readCommands reads RESP commands, protocol errors are replied
+// before disconnecting like by Redis, since the stream can't be
+// synchronized with the client after them
+func (s *TCPServer) readCommands(
+	ctx context.Context,
+	handlerCtx context.Context,
+	connection net.Conn,
+	reader io.Reader,
+	session *Session,
+	handler CommandHandler,
+) {
+	buffered := bufio.NewReaderSize(reader, s.messageSize)
+
+	for s.setReadDeadline(connection, session, true) {
+		arguments, err := readCommand(buffered, s.messageSize)
 		if err != nil {
-			if err != io.EOF {
+			if errors.Is(err, errRESPProtocol) || errors.Is(err, ErrMessageTooLarge) {
+				s.logger.Warn("invalid command, disconnecting", zap.Int64("session", session.ID()), zap.Error(err))
+				_ = session.reply(AppendError(nil, "ERR "+err.Error()))
+			} else if err != io.EOF && !session.overflowed.Load() {
 				s.logger.Warn("failed to read", zap.Error(err))
 			}
 
 			break
 		}
 
-		response := handler(ctx, request[:count])
-		if _, err := connection.Write(response); err != nil {
-			s.logger.Warn("failed to write", zap.Error(err))
+		if len(arguments) == 0 {
+			continue
+		}
+
+		if !s.setReadDeadline(connection, session, false) {
 			break
 		}
+
+		if err := session.reply(handler(handlerCtx, arguments)); err != nil {
+			break
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+}
+
+// interruptClosed wakes up the reader when the session is closed by
+// the writer, writes are interrupted too if the client has been too slow
+func (s *TCPServer) interruptClosed(connection net.Conn, session *Session) {
+	<-session.closed
+
+	session.deadlineMutex.Lock()
+	defer session.deadlineMutex.Unlock()
+
+	var err error
+	if session.overflowed.Load() {
+		err = connection.SetDeadline(time.Now())
+	} else {
+		err = connection.SetReadDeadline(time.Now())
+	}
+
+	if err != nil {
+		s.logger.Warn("failed to interrupt connection", zap.Error(err))
+	}
+}
+
+// writeMessages writes queued messages until the session is closed, the
+// rest of the queue is flushed then unless the client has been too slow
+func (s *TCPServer) writeMessages(connection net.Conn, session *Session) {
+	// handler may block the connection for longer than idle
+	// timeout (like BGETDEL), so the deadline is set per write
+	write := func(message []byte) bool {
+		session.deadlineMutex.Lock()
+		if session.overflowed.Load() {
+			session.deadlineMutex.Unlock()
+			return false
+		}
+
+		err := connection.SetWriteDeadline(time.Now().Add(s.idleTimeout))
+		session.deadlineMutex.Unlock()
+		if err != nil {
+			s.logger.Warn("failed to set write deadline", zap.Error(err))
+			return false
+		}
+
+		// RESP responses are self delimited unlike text ones
+		if s.protocol == TextProtocol {
+			err = writeMessage(connection, message)
+		} else {
+			_, err = connection.Write(message)
+		}
+
+		if err != nil {
+			s.logger.Warn("failed to write", zap.Error(err))
+			return false
+		}
+
+		return true
 	}
 
-	if err := connection.Close(); err != nil {
-		s.logger.Warn("failed to close connection", zap.Error(err))
+	for {
+		select {
+		case message := <-session.output:
+			if !write(message) {
+				session.close()
+				return
+			}
+		case <-session.closed:
+			if session.overflowed.Load() {
+				s.logger.Warn("output buffer is overflowed, disconnecting", zap.Int64("session", session.ID()))
+				return
+			}
+
+			for {
+				select {
+				case message := <-session.output:
+					if !write(message) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
 	}
 }
@@ -1,11 +1,17 @@
 package network
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"io"
 	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -22,7 +28,7 @@ func TestTCPServer(t *testing.T) {
 	maxMessageSize := 2048
 	maxConnectionsNumber := 10
 	idleTimeout := time.Minute
-	server, err := NewTCPServer(":20001", maxConnectionsNumber, maxMessageSize, idleTimeout, zap.NewNop())
+	server, err := NewTCPServer(":20001", maxConnectionsNumber, maxMessageSize, idleTimeout, 16, zap.NewNop())
 	require.NoError(t, err)
 
 	go func() {
@@ -35,11 +41,338 @@ func TestTCPServer(t *testing.T) {
 	connection, err := net.Dial("tcp", "localhost:20001")
 	require.NoError(t, err)
 
-	_, err = connection.Write([]byte(request))
+	err = writeMessage(connection, []byte(request))
 	require.NoError(t, err)
 
 	buffer := make([]byte, 2048)
-	count, err := connection.Read(buffer)
+	message, _, err := readMessage(connection, buffer)
 	require.NoError(t, err)
-	require.True(t, reflect.DeepEqual([]byte(response), buffer[:count]))
+	require.True(t, reflect.DeepEqual([]byte(response), message))
+}
+
+func TestTCPServerSessions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := NewTCPServer(":20002", 10, 2048, time.Minute, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	go func() {
+		require.NoError(t, server.HandleQueries(ctx, func(ctx context.Context, buffer []byte) []byte {
+			session := ctx.Value("session").(*Session)
+			return []byte(fmt.Sprintf("%d", session.ID()))
+		}))
+	}()
+
+	send := func(connection net.Conn) string {
+		err := writeMessage(connection, []byte("request"))
+		require.NoError(t, err)
+
+		buffer := make([]byte, 2048)
+		message, _, err := readMessage(connection, buffer)
+		require.NoError(t, err)
+		return string(message)
+	}
+
+	var first, second net.Conn
+	require.Eventually(t, func() bool {
+		first, err = net.Dial("tcp", "localhost:20002")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	second, err = net.Dial("tcp", "localhost:20002")
+	require.NoError(t, err)
+
+	firstSession := send(first)
+	require.Equal(t, firstSession, send(first))
+	require.NotEqual(t, firstSession, send(second))
+}
+
+func TestTCPServerBlockedHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	idleTimeout := 200 * time.Millisecond
+	server, err := NewTCPServer(":20003", 10, 2048, idleTimeout, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	blocked := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, server.HandleQueries(ctx, func(ctx context.Context, buffer []byte) []byte {
+			if string(buffer) == "wait" {
+				close(blocked)
+				<-ctx.Done()
+				return []byte("released")
+			}
+
+			time.Sleep(idleTimeout + 100*time.Millisecond)
+			return []byte("slow")
+		}))
+	}()
+
+	var connection net.Conn
+	require.Eventually(t, func() bool {
+		connection, err = net.Dial("tcp", "localhost:20003")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	buffer := make([]byte, 2048)
+	err = writeMessage(connection, []byte("sleep"))
+	require.NoError(t, err)
+	message, _, err := readMessage(connection, buffer)
+	require.NoError(t, err)
+	require.Equal(t, "slow", string(message))
+
+	err = writeMessage(connection, []byte("wait"))
+	require.NoError(t, err)
+	<-blocked
+	cancel()
+
+	message, _, err = readMessage(connection, buffer)
+	require.NoError(t, err)
+	require.Equal(t, "released", string(message))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("server hasn't been stopped")
+	}
+}
+
+func TestTCPServerDisconnectedClient(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := NewTCPServer(":20011", 10, 2048, time.Minute, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	canceled := make(chan string, 2)
+	go func() {
+		require.NoError(t, server.HandleQueries(ctx, func(ctx context.Context, buffer []byte) []byte {
+			if string(buffer) == "wait" {
+				<-ctx.Done()
+			}
+
+			canceled <- fmt.Sprintf("%s %t", buffer, ctx.Err() != nil)
+			return buffer
+		}))
+	}()
+
+	var connection net.Conn
+	require.Eventually(t, func() bool {
+		connection, err = net.Dial("tcp", "localhost:20011")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	// the request sent before disconnection is still handled
+	require.NoError(t, writeMessage(connection, []byte("wait")))
+	require.NoError(t, writeMessage(connection, []byte("next")))
+	require.NoError(t, connection.Close())
+
+	for _, expected := range []string{"wait true", "next true"} {
+		select {
+		case handled := <-canceled:
+			require.Equal(t, expected, handled)
+		case <-time.After(time.Second):
+			t.Fatal("handler hasn't been canceled")
+		}
+	}
+}
+
+func TestTCPServerPushMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := NewTCPServer(":20004", 10, 2048, time.Minute, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	sessions := make(chan *Session, 1)
+	go func() {
+		require.NoError(t, server.HandleQueries(ctx, func(ctx context.Context, buffer []byte) []byte {
+			sessions <- ctx.Value("session").(*Session)
+			return []byte("subscribed")
+		}))
+	}()
+
+	var connection net.Conn
+	require.Eventually(t, func() bool {
+		connection, err = net.Dial("tcp", "localhost:20004")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	buffer := make([]byte, 2048)
+	err = writeMessage(connection, []byte("subscribe"))
+	require.NoError(t, err)
+	message, _, err := readMessage(connection, buffer)
+	require.NoError(t, err)
+	require.Equal(t, "subscribed", string(message))
+
+	session := <-sessions
+	require.NoError(t, session.Push([]byte("message")))
+	message, _, err = readMessage(connection, buffer)
+	require.NoError(t, err)
+	require.Equal(t, "message", string(message))
+
+	require.NoError(t, connection.Close())
+	select {
+	case <-session.Done():
+	case <-time.After(time.Second):
+		t.Fatal("session hasn't been closed")
+	}
+
+	require.ErrorIs(t, session.Push([]byte("message")), ErrSessionClosed)
+}
+
+func TestTCPServerSlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := NewTCPServer(":20005", 10, 2048, time.Minute, 1, zap.NewNop())
+	require.NoError(t, err)
+
+	sessions := make(chan *Session, 1)
+	go func() {
+		require.NoError(t, server.HandleQueries(ctx, func(ctx context.Context, buffer []byte) []byte {
+			sessions <- ctx.Value("session").(*Session)
+			return []byte("subscribed")
+		}))
+	}()
+
+	var connection net.Conn
+	require.Eventually(t, func() bool {
+		connection, err = net.Dial("tcp", "localhost:20005")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	err = writeMessage(connection, []byte("subscribe"))
+	require.NoError(t, err)
+	session := <-sessions
+
+	// client doesn't read, so socket buffers are filled
+	// and then the output buffer is overflowed
+	message := make([]byte, 1<<20)
+	require.Eventually(t, func() bool {
+		return errors.Is(session.Push(message), ErrOutputOverflow)
+	}, 5*time.Second, time.Millisecond)
+
+	select {
+	case <-session.Done():
+	case <-time.After(time.Second):
+		t.Fatal("session hasn't been closed")
+	}
+
+	require.NoError(t, connection.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, err = io.Copy(io.Discard, connection)
+	var timeout net.Error
+	require.False(t, errors.As(err, &timeout) && timeout.Timeout(), "connection hasn't been closed")
+}
+
+func TestTCPServerLargeMessage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := NewTCPServer(":20006", 10, 16, time.Minute, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	go func() {
+		require.NoError(t, server.HandleQueries(ctx, func(ctx context.Context, buffer []byte) []byte {
+			return buffer
+		}))
+	}()
+
+	var connection net.Conn
+	require.Eventually(t, func() bool {
+		connection, err = net.Dial("tcp", "localhost:20006")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	// message is split into several writes
+	frame := bytes.NewBuffer(nil)
+	require.NoError(t, writeMessage(frame, []byte("split request")))
+	for _, part := range [][]byte{frame.Bytes()[:2], frame.Bytes()[2:7], frame.Bytes()[7:]} {
+		_, err = connection.Write(part)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	buffer := make([]byte, 2048)
+	message, _, err := readMessage(connection, buffer)
+	require.NoError(t, err)
+	require.Equal(t, "split request", string(message))
+
+	// only the header is sent, since the connection closed
+	// with unread payload may be reset before the reply is read
+	frame.Reset()
+	require.NoError(t, writeMessage(frame, []byte("request over the limit")))
+	_, err = connection.Write(frame.Bytes()[:frameHeaderSize])
+	require.NoError(t, err)
+
+	require.NoError(t, connection.SetReadDeadline(time.Now().Add(time.Second)))
+	message, _, err = readMessage(connection, buffer)
+	require.NoError(t, err)
+	require.Equal(t, "[error] message is too large: 22 bytes, limit is 16 bytes", string(message))
+
+	_, _, err = readMessage(connection, buffer)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestTCPServerRESPProtocol(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := NewTCPServerWithProtocol(":20007", 10, 2048, time.Minute, 16, "resp4", nil, zap.NewNop())
+	require.Error(t, err)
+
+	server, err := NewTCPServerWithProtocol(":20007", 10, 64, time.Minute, 16, RESP2Protocol, nil, zap.NewNop())
+	require.NoError(t, err)
+	require.Error(t, server.HandleQueries(ctx, nil))
+
+	go func() {
+		require.NoError(t, server.HandleCommands(ctx, func(ctx context.Context, arguments []string) []byte {
+			return AppendBulkString(nil, strings.Join(arguments, ","))
+		}))
+	}()
+
+	var connection net.Conn
+	require.Eventually(t, func() bool {
+		connection, err = net.Dial("tcp", "localhost:20007")
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	// commands are pipelined and split between writes
+	_, err = connection.Write([]byte("*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n*1\r\n$4\r\nPI"))
+	require.NoError(t, err)
+	_, err = connection.Write([]byte("NG\r\nECHO value\r\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(connection)
+	for _, expected := range []string{"$7\r\n", "GET,key\r\n", "$4\r\n", "PING\r\n", "$10\r\n", "ECHO,value\r\n"} {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		require.Equal(t, expected, line)
+	}
+
+	_, err = connection.Write([]byte("*1\r\n$100\r\n"))
+	require.NoError(t, err)
+
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "-ERR message is too large"))
+
+	_, err = reader.ReadString('\n')
+	require.ErrorIs(t, err, io.EOF)
 }
@@ -0,0 +1,217 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCertificates struct {
+	caFile         string
+	serverCertFile string
+	serverKeyFile  string
+	clientCertFile string
+	clientKeyFile  string
+}
+
+// generateCertificates writes self-signed CA and server and
+// client certificates signed by it to the temporary directory
+func generateCertificates(t *testing.T) testCertificates {
+	t.Helper()
+
+	directory := t.TempDir()
+	writePEM := func(name, blockType string, data []byte) string {
+		path := filepath.Join(directory, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600))
+		return path
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCertificate, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, caCertificate, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		return writePEM(name+".crt", "CERTIFICATE", der), writePEM(name+".key", "EC PRIVATE KEY", keyDER)
+	}
+
+	certificates := testCertificates{caFile: writePEM("ca.crt", "CERTIFICATE", caDER)}
+	certificates.serverCertFile, certificates.serverKeyFile = issue(2, "server", x509.ExtKeyUsageServerAuth)
+	certificates.clientCertFile, certificates.clientKeyFile = issue(3, "client", x509.ExtKeyUsageClientAuth)
+	return certificates
+}
+
+func startTLSServer(t *testing.T, address string, requireClientCert bool, certificates testCertificates) {
+	t.Helper()
+
+	tlsConfig, err := NewServerTLSConfig(
+		certificates.serverCertFile,
+		certificates.serverKeyFile,
+		certificates.caFile,
+		requireClientCert,
+	)
+	require.NoError(t, err)
+
+	server, err := NewTCPServerWithProtocol(address, 10, 2048, time.Minute, 16, TextProtocol, tlsConfig, zap.NewNop())
+	require.NoError(t, err)
+	require.Equal(t, tlsConfig, server.TLSConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go func() {
+		_ = server.HandleQueries(ctx, func(ctx context.Context, buffer []byte) []byte {
+			return append([]byte("echo "), buffer...)
+		})
+	}()
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	certificates := generateCertificates(t)
+
+	config, err := NewServerTLSConfig(certificates.serverCertFile, certificates.serverKeyFile, "", false)
+	require.NoError(t, err)
+	require.Len(t, config.Certificates, 1)
+	require.Nil(t, config.ClientCAs)
+
+	_, err = NewServerTLSConfig("", certificates.serverKeyFile, "", false)
+	require.Error(t, err)
+
+	_, err = NewServerTLSConfig(certificates.serverCertFile, certificates.clientKeyFile, "", false)
+	require.Error(t, err)
+
+	_, err = NewServerTLSConfig(certificates.serverCertFile, certificates.serverKeyFile, "", true)
+	require.Error(t, err)
+
+	_, err = NewServerTLSConfig(certificates.serverCertFile, certificates.serverKeyFile, certificates.serverKeyFile, false)
+	require.Error(t, err)
+}
+
+func TestNewClientTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	certificates := generateCertificates(t)
+
+	config, err := NewClientTLSConfig("", "", "")
+	require.NoError(t, err)
+	require.Empty(t, config.Certificates)
+	require.Nil(t, config.RootCAs)
+
+	config, err = NewClientTLSConfig(certificates.clientCertFile, certificates.clientKeyFile, certificates.caFile)
+	require.NoError(t, err)
+	require.Len(t, config.Certificates, 1)
+	require.NotNil(t, config.RootCAs)
+
+	_, err = NewClientTLSConfig(certificates.clientCertFile, "", "")
+	require.Error(t, err)
+
+	_, err = NewClientTLSConfig("", "", filepath.Join(t.TempDir(), "unknown.crt"))
+	require.Error(t, err)
+}
+
+func TestTCPServerWithTLS(t *testing.T) {
+	t.Parallel()
+
+	certificates := generateCertificates(t)
+	startTLSServer(t, "localhost:20009", false, certificates)
+
+	tlsConfig, err := NewClientTLSConfig("", "", certificates.caFile)
+	require.NoError(t, err)
+
+	var client *TCPClient
+	require.Eventually(t, func() bool {
+		client, err = NewTCPClientWithTLS("localhost:20009", 2048, time.Minute, tlsConfig)
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	response, err := client.Send([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "echo hello", string(response))
+
+	// the server isn't trusted without the CA
+	_, err = NewTCPClientWithTLS("localhost:20009", 2048, time.Minute, &tls.Config{MinVersion: tls.VersionTLS12})
+	require.Error(t, err)
+
+	// plain text messages aren't handled
+	plainClient, err := NewTCPClient("localhost:20009", 2048, time.Second)
+	require.NoError(t, err)
+	_, err = plainClient.Send([]byte("hello"))
+	require.Error(t, err)
+}
+
+func TestTCPServerWithMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	certificates := generateCertificates(t)
+	startTLSServer(t, "localhost:20010", true, certificates)
+
+	tlsConfig, err := NewClientTLSConfig(certificates.clientCertFile, certificates.clientKeyFile, certificates.caFile)
+	require.NoError(t, err)
+
+	var client *TCPClient
+	require.Eventually(t, func() bool {
+		client, err = NewTCPClientWithTLS("localhost:20010", 2048, time.Minute, tlsConfig)
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	response, err := client.Send([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "echo hello", string(response))
+
+	// with TLS 1.3 the client finds out about rejected handshake on read
+	tlsConfig, err = NewClientTLSConfig("", "", certificates.caFile)
+	require.NoError(t, err)
+
+	client, err = NewTCPClientWithTLS("localhost:20010", 2048, time.Second, tlsConfig)
+	if err == nil {
+		_, err = client.Send([]byte("hello"))
+	}
+	require.Error(t, err)
+}
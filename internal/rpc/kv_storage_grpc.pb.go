@@ -0,0 +1,308 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: kv_storage.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	KeyValueStorage_Get_FullMethodName   = "/kvstorage.v1.KeyValueStorage/Get"
+	KeyValueStorage_Set_FullMethodName   = "/kvstorage.v1.KeyValueStorage/Set"
+	KeyValueStorage_Del_FullMethodName   = "/kvstorage.v1.KeyValueStorage/Del"
+	KeyValueStorage_Write_FullMethodName = "/kvstorage.v1.KeyValueStorage/Write"
+	KeyValueStorage_Watch_FullMethodName = "/kvstorage.v1.KeyValueStorage/Watch"
+)
+
+// KeyValueStorageClient is the client API for KeyValueStorage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// KeyValueStorage gives typed access to string values of the storage,
+// errors are reported by status codes: NOT_FOUND for missing keys,
+// FAILED_PRECONDITION for keys of other types and writes to replicas,
+// INVALID_ARGUMENT for malformed requests
+type KeyValueStorageClient interface {
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error)
+	Del(ctx context.Context, in *DelRequest, opts ...grpc.CallOption) (*DelResponse, error)
+	// Write applies all operations as one batch logged to WAL together
+	Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteResponse, error)
+	// Watch streams changes of keys matching any of the glob patterns
+	// (all keys if there are no patterns), slow watchers are disconnected
+	// with RESOURCE_EXHAUSTED
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (KeyValueStorage_WatchClient, error)
+}
+
+type keyValueStorageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeyValueStorageClient(cc grpc.ClientConnInterface) KeyValueStorageClient {
+	return &keyValueStorageClient{cc}
+}
+
+func (c *keyValueStorageClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, KeyValueStorage_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValueStorageClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetResponse)
+	err := c.cc.Invoke(ctx, KeyValueStorage_Set_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValueStorageClient) Del(ctx context.Context, in *DelRequest, opts ...grpc.CallOption) (*DelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DelResponse)
+	err := c.cc.Invoke(ctx, KeyValueStorage_Del_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValueStorageClient) Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WriteResponse)
+	err := c.cc.Invoke(ctx, KeyValueStorage_Write_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValueStorageClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (KeyValueStorage_WatchClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &KeyValueStorage_ServiceDesc.Streams[0], KeyValueStorage_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &keyValueStorageWatchClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type KeyValueStorage_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type keyValueStorageWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *keyValueStorageWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KeyValueStorageServer is the server API for KeyValueStorage service.
+// All implementations must embed UnimplementedKeyValueStorageServer
+// for forward compatibility
+//
+// KeyValueStorage gives typed access to string values of the storage,
+// errors are reported by status codes: NOT_FOUND for missing keys,
+// FAILED_PRECONDITION for keys of other types and writes to replicas,
+// INVALID_ARGUMENT for malformed requests
+type KeyValueStorageServer interface {
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	Set(context.Context, *SetRequest) (*SetResponse, error)
+	Del(context.Context, *DelRequest) (*DelResponse, error)
+	// Write applies all operations as one batch logged to WAL together
+	Write(context.Context, *WriteRequest) (*WriteResponse, error)
+	// Watch streams changes of keys matching any of the glob patterns
+	// (all keys if there are no patterns), slow watchers are disconnected
+	// with RESOURCE_EXHAUSTED
+	Watch(*WatchRequest, KeyValueStorage_WatchServer) error
+	mustEmbedUnimplementedKeyValueStorageServer()
+}
+
+// UnimplementedKeyValueStorageServer must be embedded to have forward compatible implementations.
+type UnimplementedKeyValueStorageServer struct {
+}
+
+func (UnimplementedKeyValueStorageServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedKeyValueStorageServer) Set(context.Context, *SetRequest) (*SetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedKeyValueStorageServer) Del(context.Context, *DelRequest) (*DelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Del not implemented")
+}
+func (UnimplementedKeyValueStorageServer) Write(context.Context, *WriteRequest) (*WriteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Write not implemented")
+}
+func (UnimplementedKeyValueStorageServer) Watch(*WatchRequest, KeyValueStorage_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedKeyValueStorageServer) mustEmbedUnimplementedKeyValueStorageServer() {}
+
+// UnsafeKeyValueStorageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeyValueStorageServer will
+// result in compilation errors.
+type UnsafeKeyValueStorageServer interface {
+	mustEmbedUnimplementedKeyValueStorageServer()
+}
+
+func RegisterKeyValueStorageServer(s grpc.ServiceRegistrar, srv KeyValueStorageServer) {
+	s.RegisterService(&KeyValueStorage_ServiceDesc, srv)
+}
+
+func _KeyValueStorage_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValueStorageServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyValueStorage_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValueStorageServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyValueStorage_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValueStorageServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyValueStorage_Set_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValueStorageServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyValueStorage_Del_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValueStorageServer).Del(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyValueStorage_Del_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValueStorageServer).Del(ctx, req.(*DelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyValueStorage_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValueStorageServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyValueStorage_Write_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValueStorageServer).Write(ctx, req.(*WriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyValueStorage_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KeyValueStorageServer).Watch(m, &keyValueStorageWatchServer{ServerStream: stream})
+}
+
+type KeyValueStorage_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type keyValueStorageWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *keyValueStorageWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// KeyValueStorage_ServiceDesc is the grpc.ServiceDesc for KeyValueStorage service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KeyValueStorage_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kvstorage.v1.KeyValueStorage",
+	HandlerType: (*KeyValueStorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _KeyValueStorage_Get_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _KeyValueStorage_Set_Handler,
+		},
+		{
+			MethodName: "Del",
+			Handler:    _KeyValueStorage_Del_Handler,
+		},
+		{
+			MethodName: "Write",
+			Handler:    _KeyValueStorage_Write_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _KeyValueStorage_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "kv_storage.proto",
+}
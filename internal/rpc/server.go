@@ -0,0 +1,369 @@
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative kv_storage.proto
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"github.com/passsquale/key-value-storage/internal/tools"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// watchBufferSize limits events queued for a watcher,
+// which is disconnected if it isn't able to keep up
+const watchBufferSize = 64
+
+type storageLayer interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string) error
+	SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error
+	Del(ctx context.Context, key string) (bool, error)
+	Begin() *storage.Transaction
+}
+
+type idGenerator interface {
+	Generate() int64
+}
+
+type watcher struct {
+	user         *acl.User
+	patterns     []string
+	events       chan *WatchEvent
+	overflowed   chan struct{}
+	overflowOnce sync.Once
+}
+
+func (w *watcher) matches(key string) bool {
+	if w.user != nil && !w.user.CanAccessKey(key) {
+		return false
+	}
+
+	if len(w.patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range w.patterns {
+		if tools.MatchPattern(pattern, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Server implements KeyValueStorage service on top of the storage, writes
+// get transaction IDs from the generator shared with the database
+type Server struct {
+	UnimplementedKeyValueStorageServer
+
+	storage     storageLayer
+	idGenerator idGenerator
+	acl         *acl.ACL
+	logger      *zap.Logger
+
+	mutex    sync.RWMutex
+	watchers map[*watcher]struct{}
+
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewServer(storage storageLayer, idGenerator idGenerator, logger *zap.Logger) (*Server, error) {
+	if storage == nil {
+		return nil, errors.New("storage is invalid")
+	}
+
+	if idGenerator == nil {
+		return nil, errors.New("id generator is invalid")
+	}
+
+	if logger == nil {
+		return nil, errors.New("logger is invalid")
+	}
+
+	return &Server{
+		storage:     storage,
+		idGenerator: idGenerator,
+		logger:      logger,
+		watchers:    make(map[*watcher]struct{}),
+		stopped:     make(chan struct{}),
+	}, nil
+}
+
+// EnableACL makes calls to be authenticated by "user" and "password"
+// metadata, users need read category for Get and Watch, which gets
+// only events of allowed keys, and write category for other calls
+func (s *Server) EnableACL(accessList *acl.ACL) {
+	s.acl = accessList
+}
+
+// Serve serves the service on the address until the context is done,
+// watch streams are finished then and other calls are waited for,
+// connections are accepted over TLS if the config is set
+func (s *Server) Serve(ctx context.Context, address string, tlsConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	var options []grpc.ServerOption
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	return s.ServeListener(ctx, listener, options...)
+}
+
+func (s *Server) ServeListener(ctx context.Context, listener net.Listener, options ...grpc.ServerOption) error {
+	server := grpc.NewServer(options...)
+	RegisterKeyValueStorageServer(server, s)
+
+	go func() {
+		<-ctx.Done()
+		s.stopOnce.Do(func() {
+			close(s.stopped)
+		})
+
+		server.GracefulStop()
+	}()
+
+	return server.Serve(listener)
+}
+
+// PublishEvent passes the storage event to matching watchers
+// without blocking, overflowed watchers are disconnected
+func (s *Server) PublishEvent(event storage.Event) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for w := range s.watchers {
+		if !w.matches(event.Key) {
+			continue
+		}
+
+		select {
+		case w.events <- &WatchEvent{Key: event.Key, Command: event.Command, Lsn: event.LSN}:
+		default:
+			w.overflowOnce.Do(func() {
+				close(w.overflowed)
+			})
+		}
+	}
+}
+
+func (s *Server) Get(ctx context.Context, request *GetRequest) (*GetResponse, error) {
+	if _, err := s.authorize(ctx, acl.ReadCategory, request.GetKey()); err != nil {
+		return nil, err
+	}
+
+	value, err := s.storage.Get(s.withTx(ctx), request.GetKey())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &GetResponse{Value: value}, nil
+}
+
+func (s *Server) Set(ctx context.Context, request *SetRequest) (*SetResponse, error) {
+	if _, err := s.authorize(ctx, acl.WriteCategory, request.GetKey()); err != nil {
+		return nil, err
+	}
+
+	ttl, err := requestTTL(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = s.withTx(ctx)
+	if ttl != 0 {
+		err = s.storage.SetWithTTL(ctx, request.GetKey(), request.GetValue(), ttl)
+	} else {
+		err = s.storage.Set(ctx, request.GetKey(), request.GetValue())
+	}
+
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &SetResponse{}, nil
+}
+
+func (s *Server) Del(ctx context.Context, request *DelRequest) (*DelResponse, error) {
+	if _, err := s.authorize(ctx, acl.WriteCategory, request.GetKey()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.storage.Del(s.withTx(ctx), request.GetKey()); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &DelResponse{}, nil
+}
+
+// Write buffers operations in a transaction, so
+// they are logged to WAL as one batch on commit
+func (s *Server) Write(ctx context.Context, request *WriteRequest) (*WriteResponse, error) {
+	keys := make([]string, 0, len(request.GetOperations()))
+	for _, operation := range request.GetOperations() {
+		switch operation := operation.GetOperation().(type) {
+		case *WriteOperation_Set:
+			keys = append(keys, operation.Set.GetKey())
+		case *WriteOperation_Del:
+			keys = append(keys, operation.Del.GetKey())
+		}
+	}
+
+	if _, err := s.authorize(ctx, acl.WriteCategory, keys...); err != nil {
+		return nil, err
+	}
+
+	ctx = s.withTx(ctx)
+	transaction := s.storage.Begin()
+
+	for idx, operation := range request.GetOperations() {
+		var err error
+		switch operation := operation.GetOperation().(type) {
+		case *WriteOperation_Set:
+			var ttl time.Duration
+			if ttl, err = requestTTL(operation.Set); err != nil {
+				return nil, err
+			}
+
+			if ttl != 0 {
+				err = transaction.SetWithTTL(ctx, operation.Set.GetKey(), operation.Set.GetValue(), ttl)
+			} else {
+				err = transaction.Set(ctx, operation.Set.GetKey(), operation.Set.GetValue())
+			}
+		case *WriteOperation_Del:
+			_, err = transaction.Del(ctx, operation.Del.GetKey())
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "operation %d is empty", idx)
+		}
+
+		if err != nil {
+			return nil, statusError(err)
+		}
+	}
+
+	if err := transaction.Commit(ctx); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &WriteResponse{}, nil
+}
+
+func (s *Server) Watch(request *WatchRequest, stream KeyValueStorage_WatchServer) error {
+	user, err := s.authorize(stream.Context(), acl.ReadCategory)
+	if err != nil {
+		return err
+	}
+
+	w := &watcher{
+		user:       user,
+		patterns:   request.GetPatterns(),
+		events:     make(chan *WatchEvent, watchBufferSize),
+		overflowed: make(chan struct{}),
+	}
+
+	s.mutex.Lock()
+	s.watchers[w] = struct{}{}
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.watchers, w)
+		s.mutex.Unlock()
+	}()
+
+	for {
+		select {
+		case event := <-w.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-w.overflowed:
+			s.logger.Warn("watch buffer is overflowed, disconnecting")
+			return status.Error(codes.ResourceExhausted, "watcher is too slow")
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-s.stopped:
+			return status.Error(codes.Unavailable, "server is stopped")
+		}
+	}
+}
+
+// authorize authenticates the call by its metadata and checks
+// permissions of the user, nil user is returned without ACL
+func (s *Server) authorize(ctx context.Context, category string, keys ...string) (*acl.User, error) {
+	if s.acl == nil {
+		return nil, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	names, passwords := md.Get("user"), md.Get("password")
+	if len(names) != 1 || len(passwords) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "user and password metadata are required")
+	}
+
+	user, err := s.acl.Authenticate(names[0], passwords[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if !user.HasCategory(category) {
+		return nil, status.Errorf(codes.PermissionDenied, "user %s has no %s category", user.Name(), category)
+	}
+
+	for _, key := range keys {
+		if !user.CanAccessKey(key) {
+			return nil, status.Errorf(codes.PermissionDenied, "user %s has no access to key %s", user.Name(), key)
+		}
+	}
+
+	return user, nil
+}
+
+// withTx sets transaction ID used as LSN by the storage
+func (s *Server) withTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, "tx", s.idGenerator.Generate())
+}
+
+func requestTTL(request *SetRequest) (time.Duration, error) {
+	if request.GetTtlMilliseconds() < 0 {
+		return 0, status.Error(codes.InvalidArgument, "ttl must not be negative")
+	}
+
+	if request.GetTtlMilliseconds() > math.MaxInt64/int64(time.Millisecond) {
+		return 0, status.Error(codes.InvalidArgument, "ttl is out of range")
+	}
+
+	return time.Duration(request.GetTtlMilliseconds()) * time.Millisecond, nil
+}
+
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, storage.ErrWrongType), errors.Is(err, storage.ErrSlaveWrite):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, storage.ErrWatchedKeyChanged):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,279 @@
+package rpc
+
+import (
+	"context"
+	"github.com/passsquale/key-value-storage/internal/database"
+	"github.com/passsquale/key-value-storage/internal/database/acl"
+	"github.com/passsquale/key-value-storage/internal/database/storage"
+	"github.com/passsquale/key-value-storage/internal/database/storage/engine/in_memory"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) (*Server, KeyValueStorageClient, context.CancelFunc) {
+	logger := zap.NewNop()
+	engine, err := in_memory.NewEngine(in_memory.HashTableBuilder, 4, logger)
+	require.NoError(t, err)
+	storageLayer, err := storage.NewStorage(engine, nil, nil, logger)
+	require.NoError(t, err)
+
+	server, err := NewServer(storageLayer, database.NewIDGenerator(), logger)
+	require.NoError(t, err)
+
+	classes := []string{storage.SetEventClass, storage.DelEventClass}
+	require.NoError(t, storageLayer.EnableNotifications(classes, server.PublishEvent))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listener := bufconn.Listen(1 << 20)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, server.ServeListener(ctx, listener))
+	}()
+
+	connection, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		_ = connection.Close()
+	})
+
+	return server, NewKeyValueStorageClient(connection), cancel
+}
+
+// waitWatchers waits for watchers, which are registered asynchronously
+func waitWatchers(t *testing.T, server *Server, count int) {
+	require.Eventually(t, func() bool {
+		server.mutex.RLock()
+		defer server.mutex.RUnlock()
+		return len(server.watchers) == count
+	}, time.Second, time.Millisecond)
+}
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewServer(nil, nil, nil)
+	require.Error(t, err, "storage is invalid")
+	require.Nil(t, server)
+}
+
+func TestServerKeyValueCalls(t *testing.T) {
+	t.Parallel()
+
+	_, client, _ := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := client.Get(ctx, &GetRequest{Key: "key"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Set(ctx, &SetRequest{Key: "key", Value: "value with spaces"})
+	require.NoError(t, err)
+
+	response, err := client.Get(ctx, &GetRequest{Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, "value with spaces", response.GetValue())
+
+	_, err = client.Set(ctx, &SetRequest{Key: "temporary", Value: "value", TtlMilliseconds: 50})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, err := client.Get(ctx, &GetRequest{Key: "temporary"})
+		return status.Code(err) == codes.NotFound
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = client.Set(ctx, &SetRequest{Key: "key", Value: "value", TtlMilliseconds: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.Set(ctx, &SetRequest{Key: "huge", Value: "value", TtlMilliseconds: math.MaxInt64})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.Get(ctx, &GetRequest{Key: "huge"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Del(ctx, &DelRequest{Key: "key"})
+	require.NoError(t, err)
+
+	_, err = client.Get(ctx, &GetRequest{Key: "key"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServerWrite(t *testing.T) {
+	t.Parallel()
+
+	_, client, _ := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := client.Set(ctx, &SetRequest{Key: "key_3", Value: "value_3"})
+	require.NoError(t, err)
+
+	_, err = client.Write(ctx, &WriteRequest{Operations: []*WriteOperation{
+		{Operation: &WriteOperation_Set{Set: &SetRequest{Key: "key_1", Value: "value_1"}}},
+		{Operation: &WriteOperation_Set{Set: &SetRequest{Key: "key_2", Value: "value_2", TtlMilliseconds: 60000}}},
+		{Operation: &WriteOperation_Del{Del: &DelRequest{Key: "key_3"}}},
+	}})
+	require.NoError(t, err)
+
+	for key, value := range map[string]string{"key_1": "value_1", "key_2": "value_2"} {
+		response, err := client.Get(ctx, &GetRequest{Key: key})
+		require.NoError(t, err)
+		require.Equal(t, value, response.GetValue())
+	}
+
+	_, err = client.Get(ctx, &GetRequest{Key: "key_3"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Write(ctx, &WriteRequest{Operations: []*WriteOperation{
+		{Operation: &WriteOperation_Set{Set: &SetRequest{Key: "key_4", Value: "value_4"}}},
+		{},
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Get(ctx, &GetRequest{Key: "key_4"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServerWatch(t *testing.T) {
+	t.Parallel()
+
+	server, client, cancel := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := client.Watch(ctx, &WatchRequest{Patterns: []string{"user:*"}})
+	require.NoError(t, err)
+
+	waitWatchers(t, server, 1)
+
+	_, err = client.Set(ctx, &SetRequest{Key: "user:0", Value: "value"})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "user:0", event.GetKey())
+	require.Equal(t, "SET", event.GetCommand())
+
+	_, err = client.Set(ctx, &SetRequest{Key: "session:1", Value: "value"})
+	require.NoError(t, err)
+	_, err = client.Del(ctx, &DelRequest{Key: "user:0"})
+	require.NoError(t, err)
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "user:0", event.GetKey())
+	require.Equal(t, "DEL", event.GetCommand())
+	require.NotZero(t, event.GetLsn())
+
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestServerSlowWatcher(t *testing.T) {
+	t.Parallel()
+
+	server, client, _ := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := client.Watch(ctx, &WatchRequest{})
+	require.NoError(t, err)
+
+	waitWatchers(t, server, 1)
+
+	// events are published faster than the watcher receives them
+	for idx := 0; idx < 100*watchBufferSize; idx++ {
+		server.PublishEvent(storage.Event{Key: "key", Command: "SET"})
+	}
+
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestServerWithACL(t *testing.T) {
+	t.Parallel()
+
+	newUser := func(name string, categories []string) *acl.User {
+		passwordHash, err := acl.HashPassword("password")
+		require.NoError(t, err)
+		user, err := acl.NewUser(name, passwordHash, categories, []string{"user:*"})
+		require.NoError(t, err)
+		return user
+	}
+
+	accessList, err := acl.NewACL([]*acl.User{
+		newUser("reader", []string{acl.ReadCategory}),
+		newUser("writer", []string{acl.ReadCategory, acl.WriteCategory}),
+	})
+	require.NoError(t, err)
+
+	server, client, _ := newTestServer(t)
+	server.EnableACL(accessList)
+
+	reader := metadata.AppendToOutgoingContext(context.Background(), "user", "reader", "password", "password")
+	writer := metadata.AppendToOutgoingContext(context.Background(), "user", "writer", "password", "password")
+
+	_, err = client.Get(context.Background(), &GetRequest{Key: "user:1"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	wrongPassword := metadata.AppendToOutgoingContext(context.Background(), "user", "writer", "password", "secret")
+	_, err = client.Set(wrongPassword, &SetRequest{Key: "user:1", Value: "value"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.Set(reader, &SetRequest{Key: "user:1", Value: "value"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.Set(writer, &SetRequest{Key: "config", Value: "value"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.Write(writer, &WriteRequest{Operations: []*WriteOperation{
+		{Operation: &WriteOperation_Set{Set: &SetRequest{Key: "user:2", Value: "value"}}},
+		{Operation: &WriteOperation_Del{Del: &DelRequest{Key: "config"}}},
+	}})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.Get(reader, &GetRequest{Key: "user:2"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	unauthenticated, err := client.Watch(context.Background(), &WatchRequest{})
+	require.NoError(t, err)
+	_, err = unauthenticated.Recv()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.Watch(reader, &WatchRequest{})
+	require.NoError(t, err)
+	waitWatchers(t, server, 1)
+
+	// events of keys, which aren't allowed to the user, are skipped
+	require.NoError(t, server.storage.Set(server.withTx(context.Background()), "config", "value"))
+	_, err = client.Write(writer, &WriteRequest{Operations: []*WriteOperation{
+		{Operation: &WriteOperation_Set{Set: &SetRequest{Key: "user:1", Value: "value"}}},
+	}})
+	require.NoError(t, err)
+
+	response, err := client.Get(reader, &GetRequest{Key: "user:1"})
+	require.NoError(t, err)
+	require.Equal(t, "value", response.GetValue())
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "user:1", event.GetKey())
+}
@@ -0,0 +1,12 @@
+package tools
+
+import "math"
+
+// AddInt64 returns sum of the numbers and false if it overflows int64
+func AddInt64(number, delta int64) (int64, bool) {
+	if (delta > 0 && number > math.MaxInt64-delta) || (delta < 0 && number < math.MinInt64-delta) {
+		return 0, false
+	}
+
+	return number + delta, true
+}
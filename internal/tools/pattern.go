@@ -0,0 +1,95 @@
+package tools
+
+// MatchPattern reports whether value matches glob-style pattern,
+// which supports *, ?, [abc], [^abc], [a-z] and escaping with backslash
+func MatchPattern(pattern, value string) bool {
+	patternIdx, valueIdx := 0, 0
+	starIdx, starValueIdx := -1, 0
+
+	for valueIdx < len(value) {
+		if patternIdx < len(pattern) && pattern[patternIdx] == '*' {
+			starIdx, starValueIdx = patternIdx, valueIdx
+			patternIdx++
+			continue
+		}
+
+		if patternIdx < len(pattern) {
+			if matched, next := matchSymbol(pattern, patternIdx, value[valueIdx]); matched {
+				patternIdx = next
+				valueIdx++
+				continue
+			}
+		}
+
+		if starIdx < 0 {
+			return false
+		}
+
+		starValueIdx++
+		patternIdx, valueIdx = starIdx+1, starValueIdx
+	}
+
+	for patternIdx < len(pattern) && pattern[patternIdx] == '*' {
+		patternIdx++
+	}
+
+	return patternIdx == len(pattern)
+}
+
+// matchSymbol matches one symbol against pattern element
+// at idx and returns index of the next pattern element
+func matchSymbol(pattern string, idx int, symbol byte) (bool, int) {
+	switch pattern[idx] {
+	case '?':
+		return true, idx + 1
+	case '\\':
+		if idx+1 < len(pattern) {
+			return pattern[idx+1] == symbol, idx + 2
+		}
+	case '[':
+		if matched, next, ok := matchClass(pattern, idx, symbol); ok {
+			return matched, next
+		}
+	}
+
+	return pattern[idx] == symbol, idx + 1
+}
+
+// matchClass matches symbol against [...] class, ok is false
+// if the class isn't closed and must be treated literally
+func matchClass(pattern string, idx int, symbol byte) (bool, int, bool) {
+	idx++
+	negated := idx < len(pattern) && pattern[idx] == '^'
+	if negated {
+		idx++
+	}
+
+	matched := false
+	for first := true; idx < len(pattern); first = false {
+		current := pattern[idx]
+		if current == ']' && !first {
+			return matched != negated, idx + 1, true
+		}
+
+		if current == '\\' && idx+1 < len(pattern) {
+			idx++
+			current = pattern[idx]
+		}
+
+		if idx+2 < len(pattern) && pattern[idx+1] == '-' && pattern[idx+2] != ']' {
+			low, high := current, pattern[idx+2]
+			if low > high {
+				low, high = high, low
+			}
+
+			matched = matched || (symbol >= low && symbol <= high)
+			idx += 3
+			continue
+		}
+
+		matched = matched || symbol == current
+		idx++
+	}
+
+	return false, 0, false
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMatchPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		value   string
+		matched bool
+	}{
+		{pattern: "*", value: "", matched: true},
+		{pattern: "*", value: "key", matched: true},
+		{pattern: "key", value: "key", matched: true},
+		{pattern: "key", value: "keys", matched: false},
+		{pattern: "user:*", value: "user:1", matched: true},
+		{pattern: "user:*", value: "session:1", matched: false},
+		{pattern: "*:*:name", value: "user:1:name", matched: true},
+		{pattern: "*:name", value: "user:1:email", matched: false},
+		{pattern: "h?llo", value: "hello", matched: true},
+		{pattern: "h?llo", value: "hllo", matched: false},
+		{pattern: "h[ae]llo", value: "hallo", matched: true},
+		{pattern: "h[ae]llo", value: "hillo", matched: false},
+		{pattern: "h[^e]llo", value: "hallo", matched: true},
+		{pattern: "h[^e]llo", value: "hello", matched: false},
+		{pattern: "h[a-c]llo", value: "hbllo", matched: true},
+		{pattern: "h[a-c]llo", value: "hdllo", matched: false},
+		{pattern: `h\*llo`, value: "h*llo", matched: true},
+		{pattern: `h\*llo`, value: "hello", matched: false},
+		{pattern: "h[ello", value: "h[ello", matched: true},
+		{pattern: "a*b*c", value: "aXbYbZc", matched: true},
+		{pattern: "a*b*c", value: "aXbYbZ", matched: false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.matched, MatchPattern(test.pattern, test.value), test.pattern+" "+test.value)
+	}
+}
@@ -14,6 +14,17 @@ func (s *Semaphore) Acquire() {
 	s.tickets <- struct{}{}
 }
 
+// TryAcquire takes a ticket without waiting
+// and returns false if there are no tickets
+func (s *Semaphore) TryAcquire() bool {
+	select {
+	case s.tickets <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *Semaphore) Release() {
 	<-s.tickets
 }